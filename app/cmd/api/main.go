@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/breach"
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/mail"
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/memory"
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/oidc"
 	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/postgres"
 	"github.com/twaydev/golang-todolist/app/internal/adapter/driving/http"
+	"github.com/twaydev/golang-todolist/app/internal/auth"
 	"github.com/twaydev/golang-todolist/app/internal/config"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
 	"github.com/twaydev/golang-todolist/app/internal/domain/service"
 )
 
@@ -34,12 +45,79 @@ func main() {
 
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(pool)
+	todoRepo := postgres.NewTodoRepository(pool)
+	todoHistoryRepo := postgres.NewTodoStatusHistoryRepository(pool)
+	tagRepo := postgres.NewTagRepository(pool)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(pool)
+	revocationStore := postgres.NewTokenRevocationStore(pool)
+	oneTimeTokenRepo := postgres.NewOneTimeTokenRepository(pool)
+	totpRepo := postgres.NewTOTPRepository(pool)
+	recoveryCodeRepo := postgres.NewRecoveryCodeRepository(pool)
+	identityRepo := postgres.NewUserIdentityRepository(pool)
+	oidcRequestRepo := postgres.NewOIDCAuthRequestRepository(pool)
+	accessTokenRepo := postgres.NewPersonalAccessTokenRepository(pool)
+	sessionRepo := postgres.NewSessionRepository(pool)
+	attemptStore := newAttemptStore(cfg, pool)
+
+	// Initialize mail delivery
+	mailer, closeMailer, err := newMailer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
+	defer closeMailer()
+
+	// Initialize token signing
+	jwtManager, err := newJWTManager(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// Initialize password hashing
+	passwordHasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize password hashing: %v", err)
+	}
+
+	// Initialize password policy
+	passwordPolicy, err := newPasswordPolicy(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load password policy: %v", err)
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg.JWTSecret, cfg.JWTExpiryHours)
+	authService, err := service.NewAuthService(userRepo, refreshTokenRepo, revocationStore, oneTimeTokenRepo, totpRepo, recoveryCodeRepo, identityRepo, oidcRequestRepo, accessTokenRepo, sessionRepo, attemptStore, mailer, jwtManager, passwordHasher, newBreachChecker(cfg), newIdentityProviders(cfg), service.AuthConfig{
+		RefreshTokenExpiry:       time.Duration(cfg.RefreshTokenExpiryHours) * time.Hour,
+		EmailVerificationExpiry:  time.Duration(cfg.EmailVerificationExpiryHours) * time.Hour,
+		RequireEmailVerification: cfg.RequireEmailVerification,
+		PasswordResetExpiry:      time.Duration(cfg.PasswordResetExpiryMinutes) * time.Minute,
+		PasswordPolicy:           passwordPolicy,
+		AppBaseURL:               cfg.AppBaseURL,
+		MaxFailedLogins:          cfg.LoginMaxFailures,
+		LockoutDuration:          time.Duration(cfg.LoginLockoutSeconds) * time.Second,
+		MaxLockoutDuration:       time.Duration(cfg.LoginMaxLockoutMinutes) * time.Minute,
+		FailedLoginWindow:        time.Duration(cfg.LoginFailureWindowMinutes) * time.Minute,
+		ClientRateLimit:          cfg.AuthRateLimitPerIP,
+		ClientRateWindow:         time.Duration(cfg.AuthRateLimitWindowSeconds) * time.Second,
+		EmailRateLimit:           cfg.EmailRateLimitPerAddress,
+		EmailRateWindow:          time.Duration(cfg.EmailRateLimitWindowMinutes) * time.Minute,
+		MFAIssuer:                cfg.MFAIssuer,
+		MFAChallengeExpiry:       time.Duration(cfg.MFAChallengeExpiryMinutes) * time.Minute,
+		MFAMaxAttempts:           cfg.MFAMaxAttempts,
+		MFAMaxFailures:           cfg.MFAMaxFailures,
+
+		AccountDeletionGracePeriod: time.Duration(cfg.AccountDeletionGraceDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize auth service: %v", err)
+	}
+	todoService := service.NewTodoService(todoRepo, todoHistoryRepo)
+	tagService := service.NewTagService(tagRepo)
 
 	// Create HTTP server
-	server := http.NewServer(authService)
+	server := http.NewServer(authService, todoService, tagService)
+
+	// Purge deleted accounts whose grace period has ended and expired auth records
+	go runPurge(ctx, authService, time.Duration(cfg.AccountPurgeIntervalMinutes)*time.Minute)
 
 	// Start server in goroutine
 	go func() {
@@ -64,5 +142,169 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Deliver the emails requests left to be sent in the background
+	authService.Wait()
+
 	log.Println("Server stopped")
 }
+
+// runPurge purges deleted accounts and expired auth records at every
+// interval until ctx is done
+func runPurge(ctx context.Context, authService *service.AuthService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purge(ctx, "deleted accounts", authService.PurgeDeletedAccounts)
+		purge(ctx, "expired auth attempt counters", authService.PurgeExpiredAttempts)
+		purge(ctx, "expired token revocations", authService.PurgeExpiredRevocations)
+		purge(ctx, "expired refresh tokens", authService.PurgeExpiredRefreshTokens)
+		purge(ctx, "expired one-time tokens", authService.PurgeExpiredOneTimeTokens)
+		purge(ctx, "expired OIDC login requests", authService.PurgeExpiredOIDCRequests)
+		purge(ctx, "ended sessions", authService.PurgeEndedSessions)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge runs one purge job and logs its outcome
+func purge(ctx context.Context, what string, job func(context.Context) (int, error)) {
+	purged, err := job(ctx)
+	if err != nil {
+		log.Printf("Failed to purge %s: %v", what, err)
+	} else if purged > 0 {
+		log.Printf("Purged %d %s", purged, what)
+	}
+}
+
+// newJWTManager signs with the configured asymmetric keys, falling back to the shared secret
+func newJWTManager(cfg *config.Config) (*auth.JWTManager, error) {
+	if cfg.JWTSigningKeys == "" {
+		return auth.NewJWTManager(cfg.JWTSecret, cfg.JWTExpiryHours), nil
+	}
+
+	keys, err := auth.LoadKeySet(cfg.JWTSigningKeys, cfg.JWTVerificationKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewJWTManagerWithKeys(keys, cfg.JWTExpiryHours), nil
+}
+
+// newPasswordHasher hashes with the configured algorithm and still verifies hashes made by the other one
+func newPasswordHasher(cfg *config.Config) (auth.PasswordHasher, error) {
+	bcryptHasher := auth.NewBcryptHasher(cfg.BcryptCost)
+	argon2Hasher := auth.NewArgon2idHasher(auth.Argon2Params{
+		Memory:      uint32(cfg.Argon2MemoryKiB),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+		SaltLength:  auth.DefaultArgon2Params.SaltLength,
+		KeyLength:   auth.DefaultArgon2Params.KeyLength,
+	})
+
+	switch cfg.PasswordHashAlgorithm {
+	case "argon2id":
+		return auth.NewMigratingHasher(argon2Hasher, bcryptHasher), nil
+	case "bcrypt":
+		return auth.NewMigratingHasher(bcryptHasher, argon2Hasher), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", cfg.PasswordHashAlgorithm)
+	}
+}
+
+// newPasswordPolicy builds the password policy, including any extra blocklisted passwords
+func newPasswordPolicy(cfg *config.Config) (entity.PasswordPolicy, error) {
+	policy := entity.DefaultPasswordPolicy()
+	policy.MinLength = cfg.PasswordMinLength
+	policy.MaxLength = cfg.PasswordMaxLength
+	policy.RequireUppercase = cfg.PasswordRequireUppercase
+	policy.RequireLowercase = cfg.PasswordRequireLowercase
+	policy.RequireDigit = cfg.PasswordRequireDigit
+	policy.RequireSymbol = cfg.PasswordRequireSymbol
+
+	if cfg.PasswordBlocklistFile == "" {
+		return policy, nil
+	}
+
+	data, err := os.ReadFile(cfg.PasswordBlocklistFile)
+	if err != nil {
+		return policy, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			policy.Blocklist = append(policy.Blocklist, line)
+		}
+	}
+
+	return policy, nil
+}
+
+// newBreachChecker checks passwords against local breach ranges when a dataset is configured
+func newBreachChecker(cfg *config.Config) output.BreachedPasswordChecker {
+	if cfg.BreachedPasswordsDir == "" {
+		return nil
+	}
+	return breach.NewRangeChecker(breach.NewDirSource(cfg.BreachedPasswordsDir), cfg.BreachedPasswordMinCount)
+}
+
+// newIdentityProviders creates the configured OpenID Connect providers
+func newIdentityProviders(cfg *config.Config) []output.IdentityProvider {
+	providers := make([]output.IdentityProvider, 0, len(cfg.OIDCProviders))
+	for _, p := range cfg.OIDCProviders {
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, nil))
+	}
+	return providers
+}
+
+// newAttemptStore keeps throttling counters in Postgres unless the in-memory store is configured
+func newAttemptStore(cfg *config.Config, pool *pgxpool.Pool) output.AttemptStore {
+	if cfg.AttemptStore == "memory" {
+		return memory.NewAttemptStore()
+	}
+	return postgres.NewAttemptStore(pool)
+}
+
+// newMailer sends through SMTP when configured. In development and test it
+// otherwise writes emails to a log file or stdout; other environments must
+// configure SMTP, since emails carry verification and password reset tokens.
+func newMailer(cfg *config.Config) (output.Mailer, func(), error) {
+	if cfg.SMTPHost != "" {
+		return mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}), func() {}, nil
+	}
+
+	if !cfg.IsDevelopment() && !cfg.IsTest() {
+		return nil, nil, fmt.Errorf("SMTP_HOST must be set in %s mode", cfg.Environment)
+	}
+
+	if cfg.MailLogFile == "" {
+		return mail.NewLogMailer(os.Stdout, cfg.MailFrom), func() {}, nil
+	}
+
+	f, err := os.OpenFile(cfg.MailLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mail.NewLogMailer(f, cfg.MailFrom), func() { f.Close() }, nil
+}
@@ -0,0 +1,162 @@
+Feature: Account Deletion Grace Period
+  As a user of the todolist application
+  I want a deleted account to be kept for a while before it is purged
+  So that I can change my mind or recover from a mistaken deletion
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "alice@example.com" and password "correct-horse-battery"
+
+  # ============================================================================
+  # Restoring by signing in
+  # ============================================================================
+
+  @account-deletion @happy-path
+  Scenario: Signing in restores a recently deleted account
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And a todo exists with title "Keep me"
+    And I delete my account with password "correct-horse-battery"
+    And the deletion of "alice@example.com" happened 29 days ago
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    When I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I list my todos
+    Then the response status code should be 200
+    And the response should contain 1 todo
+
+  @account-deletion
+  Scenario: Restoring an account does not revive its old tokens
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I have a personal access token with scopes "todos:read"
+    And I delete my account with password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I request my profile with my previous token
+    Then the response status code should be 401
+    When I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 401
+
+  @account-deletion @mfa
+  Scenario: A deleted account with two-factor authentication is restored only after the second factor
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I have enabled two-factor authentication
+    And I delete my account with password "correct-horse-battery"
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response should contain "mfa_token"
+    And the user "alice@example.com" should still be deleted
+    When I verify the login with code "000000"
+    Then the response status code should be 401
+    And the user "alice@example.com" should still be deleted
+    When I verify the login with a valid authenticator code
+    Then the response status code should be 200
+    And the user "alice@example.com" should no longer be deleted
+
+  @account-deletion
+  Scenario: A deleted account cannot be restored with a wrong password
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I delete my account with password "correct-horse-battery"
+    When I login with email "alice@example.com" and password "wrong-horse-battery"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_credentials"
+
+  @account-deletion
+  Scenario: An account past its grace period cannot be restored
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I delete my account with password "correct-horse-battery"
+    And the deletion of "alice@example.com" happened 31 days ago
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_credentials"
+
+  @account-deletion
+  Scenario: The email of a deleted account stays taken during the grace period
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I delete my account with password "correct-horse-battery"
+    When I register with email "alice@example.com" and password "another-horse-battery"
+    Then the response status code should be 409
+
+  # ============================================================================
+  # Purging
+  # ============================================================================
+
+  @account-deletion @purge
+  Scenario: The purge job removes accounts past their grace period
+    Given a user exists with email "bob@example.com" and password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I delete my account with password "correct-horse-battery"
+    And I am logged in as "bob@example.com" with password "correct-horse-battery"
+    And I delete my account with password "correct-horse-battery"
+    And the deletion of "alice@example.com" happened 31 days ago
+    When the account purge job runs
+    Then 1 account should have been purged
+    And the user "alice@example.com" should no longer exist
+    When I login with email "bob@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @account-deletion @purge
+  Scenario: The purge job keeps active accounts
+    Given the account purge job runs
+    Then 0 accounts should have been purged
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @account-deletion @purge
+  Scenario: A purged account's email can be registered again
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I delete my account with password "correct-horse-battery"
+    And the deletion of "alice@example.com" happened 31 days ago
+    And the account purge job runs
+    When I register with email "alice@example.com" and password "another-horse-battery"
+    Then the response status code should be 201
+
+  # ============================================================================
+  # Restoring by an administrator
+  # ============================================================================
+
+  @account-deletion @admin
+  Scenario: An administrator restores a deleted user
+    Given an admin exists with email "admin@example.com" and password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    And I delete the user "alice@example.com"
+    When I restore the user "alice@example.com"
+    Then the response status code should be 200
+    And the response "email" should be "alice@example.com"
+    When I list the users
+    Then the listed users should be "alice@example.com,admin@example.com"
+    When I list the deleted users
+    Then the response should list 0 users
+
+  @account-deletion @admin
+  Scenario: Restoring a user that is not deleted fails
+    Given an admin exists with email "admin@example.com" and password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I restore the user "alice@example.com"
+    Then the response status code should be 409
+    And the response "error" should be "user_not_deleted"
+
+  @account-deletion @admin
+  Scenario: A user past the grace period cannot be restored
+    Given an admin exists with email "admin@example.com" and password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    And I delete the user "alice@example.com"
+    And the deletion of "alice@example.com" happened 31 days ago
+    When I restore the user "alice@example.com"
+    Then the response status code should be 404
+
+  @account-deletion @admin
+  Scenario: A disabled user cannot restore their deleted account by signing in
+    Given an admin exists with email "admin@example.com" and password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    And I disable the user "alice@example.com"
+    And I delete the user "alice@example.com"
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+
+  @account-deletion @admin
+  Scenario: Only administrators can restore users
+    Given a user exists with email "bob@example.com" and password "correct-horse-battery"
+    And I am logged in as "bob@example.com" with password "correct-horse-battery"
+    When I restore the user "alice@example.com"
+    Then the response status code should be 403
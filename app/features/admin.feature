@@ -0,0 +1,197 @@
+Feature: User Administration
+  As an administrator of the todolist application
+  I want to manage user accounts
+  So that I can help users and stop abuse
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And an admin exists with email "admin@example.com" and password "correct-horse-battery"
+    And a user exists with email "alice@example.com" and password "correct-horse-battery"
+    And a user exists with email "bob@example.com" and password "correct-horse-battery"
+
+  # ============================================================================
+  # Authorization
+  # ============================================================================
+
+  @admin @authorization
+  Scenario: Regular users cannot manage users
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I list the users
+    Then the response status code should be 403
+    And the response "error" should be "forbidden"
+
+  @admin @authorization
+  Scenario: Anonymous requests are rejected
+    When I list the users
+    Then the response status code should be 401
+
+  @admin @authorization
+  Scenario: Profiles show the role
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I request my profile
+    Then the response "role" should be "user"
+
+  @admin @authorization
+  Scenario: Personal access tokens cannot manage users
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    And I have a personal access token with scopes "todos:read,todos:write"
+    When I authenticate with my personal access token
+    And I list the users
+    Then the response status code should be 403
+
+  # ============================================================================
+  # Listing and Searching
+  # ============================================================================
+
+  @admin @list
+  Scenario: List users
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I list the users
+    Then the response status code should be 200
+    And the response "total" should be 3
+    And the listed users should be "admin@example.com,alice@example.com,bob@example.com"
+
+  @admin @list
+  Scenario: Search users by email
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I search the users for "ALICE"
+    Then the response status code should be 200
+    And the listed users should be "alice@example.com"
+
+  @admin @list
+  Scenario: Filter users by role
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I list the users with role "admin"
+    Then the listed users should be "admin@example.com"
+
+  @admin @list
+  Scenario: Unknown roles cannot be listed
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I list the users with role "superuser"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_role"
+
+  @admin @list
+  Scenario: Page through users
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I list the users with limit 1 and offset 1
+    Then the response status code should be 200
+    And the response "total" should be 3
+    And the listed users should be "alice@example.com"
+
+  @admin @list
+  Scenario: Get a user
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I get the user "bob@example.com"
+    Then the response status code should be 200
+    And the response "email" should be "bob@example.com"
+    And the response "role" should be "user"
+
+  @admin @list
+  Scenario: Get an unknown user
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I get the user with id "00000000-0000-0000-0000-000000000000"
+    Then the response status code should be 404
+    And the response "error" should be "user_not_found"
+
+  # ============================================================================
+  # Disabling
+  # ============================================================================
+
+  @admin @disable
+  Scenario: Disabled users cannot log in
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I disable the user "alice@example.com"
+    Then the response status code should be 200
+    And the response "disabled" should be true
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 403
+    And the response "error" should be "account_disabled"
+
+  @admin @disable
+  Scenario: Disabling a user ends their sessions
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I disable the user "alice@example.com"
+    And I request my profile with my previous token
+    Then the response status code should be 401
+
+  @admin @disable
+  Scenario: Disabling a user suspends their personal access tokens
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I have a personal access token with scopes "todos:read"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I disable the user "alice@example.com"
+    And I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 403
+    And the response "error" should be "account_disabled"
+
+  @admin @disable
+  Scenario: Enabled users can log in again
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    And I disable the user "alice@example.com"
+    When I enable the user "alice@example.com"
+    Then the response status code should be 200
+    And the response "disabled" should be false
+    When I login with email "alice@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @admin @disable
+  Scenario: Administrators cannot disable themselves
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I disable the user "admin@example.com"
+    Then the response status code should be 409
+    And the response "error" should be "cannot_modify_self"
+
+  # ============================================================================
+  # Roles
+  # ============================================================================
+
+  @admin @roles
+  Scenario: Promote a user to administrator
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I change the role of "alice@example.com" to "admin"
+    Then the response status code should be 200
+    And the response "role" should be "admin"
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I list the users
+    Then the response status code should be 200
+
+  @admin @roles
+  Scenario: Demoted administrators lose access at once
+    Given I change the role of "alice@example.com" to "admin" directly
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I change the role of "alice@example.com" to "user"
+    And I request my profile with my previous token
+    Then the response status code should be 401
+
+  @admin @roles
+  Scenario: Unknown roles are rejected
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I change the role of "alice@example.com" to "superuser"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_role"
+
+  @admin @roles
+  Scenario: Administrators cannot demote themselves
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I change the role of "admin@example.com" to "user"
+    Then the response status code should be 409
+    And the response "error" should be "cannot_modify_self"
+
+  # ============================================================================
+  # Deleting
+  # ============================================================================
+
+  @admin @delete
+  Scenario: Delete a user
+    Given I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I delete the user "bob@example.com"
+    Then the response status code should be 204
+    When I list the users
+    Then the listed users should be "admin@example.com,alice@example.com"
+    When I list the deleted users
+    Then the listed users should be "bob@example.com"
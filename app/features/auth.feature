@@ -13,7 +13,7 @@ Feature: User Authentication
 
   @registration @happy-path
   Scenario: Successful user registration
-    When I register with email "newuser@example.com" and password "password123"
+    When I register with email "newuser@example.com" and password "correct-horse-battery"
     Then the response status code should be 201
     And the response should contain "id"
     And the response should contain "email"
@@ -21,53 +21,137 @@ Feature: User Authentication
 
   @registration @validation
   Scenario: Registration fails with invalid email format
-    When I register with email "invalid-email" and password "password123"
+    When I register with email "invalid-email" and password "correct-horse-battery"
     Then the response status code should be 400
-    And the response "error" should be "invalid_email"
-    And the response "message" should be "Invalid email format"
+    And the response "error" should be "validation_error"
+    And the field "email" should fail the "email" rule
 
   @registration @validation
   Scenario: Registration fails with short password
     When I register with email "user@example.com" and password "short"
     Then the response status code should be 400
-    And the response "error" should be "password_too_short"
+    And the response "error" should be "weak_password"
+    And the response should list the password violation "too_short"
     And the response "message" should be "Password must be at least 8 characters"
 
+  @registration @validation @password-policy
+  Scenario: Password length counts characters, not bytes
+    When I register with email "user@example.com" and password "ääääääą"
+    Then the response status code should be 400
+    And the response should list the password violation "too_short"
+    When I register with email "user@example.com" and password "äöüäöüäöü"
+    Then the response status code should be 201
+
+  @registration @validation @password-policy
+  Scenario: Registration rejects a common password
+    When I register with email "user@example.com" and password "Password123"
+    Then the response status code should be 400
+    And the response "error" should be "weak_password"
+    And the response should list the password violation "common_password"
+
+  @registration @validation @password-policy
+  Scenario: Registration rejects a password containing the email
+    When I register with email "jonathan@example.com" and password "jonathan-2024!"
+    Then the response status code should be 400
+    And the response should list the password violation "similar_to_email"
+
+  @registration @validation @password-policy
+  Scenario: Registration rejects a breached password
+    When I register with email "user@example.com" and password "monkey-banana-42"
+    Then the response status code should be 400
+    And the response should list the password violation "breached"
+
+  @registration @validation @password-policy
+  Scenario: Registration lists every password violation
+    When I register with email "user@example.com" and password "qwerty"
+    Then the response status code should be 400
+    And the response should list the password violation "too_short"
+    And the response should list the password violation "common_password"
+
   @registration @validation
   Scenario: Registration fails with empty email
-    When I register with email "" and password "password123"
+    When I register with email "" and password "correct-horse-battery"
     Then the response status code should be 400
     And the response "error" should be "validation_error"
-    And the response "message" should be "Email and password are required"
+    And the response "message" should be "email is required"
+    And the field "email" should fail the "required" rule
 
   @registration @validation
   Scenario: Registration fails with empty password
     When I register with email "user@example.com" and password ""
     Then the response status code should be 400
     And the response "error" should be "validation_error"
-    And the response "message" should be "Email and password are required"
+    And the field "password" should fail the "required" rule
 
   @registration @duplicate
   Scenario: Registration fails with duplicate email
-    Given a user exists with email "existing@example.com" and password "password123"
+    Given a user exists with email "existing@example.com" and password "correct-horse-battery"
     When I register with email "existing@example.com" and password "newpassword123"
     Then the response status code should be 409
     And the response "error" should be "email_exists"
     And the response "message" should be "Email already registered"
 
+  @registration @email-normalization
+  Scenario: Registration normalizes the email address
+    When I register with email "  Bob@Example.COM " and password "correct-horse-battery"
+    Then the response status code should be 201
+    And the response "email" should be "Bob@example.com"
+
+  @registration @email-normalization @duplicate
+  Scenario: Registration fails with an email differing only in case
+    Given a user exists with email "existing@example.com" and password "correct-horse-battery"
+    When I register with email "Existing@EXAMPLE.com" and password "newpassword123"
+    Then the response status code should be 409
+    And the response "error" should be "email_exists"
+
+  @registration @email-normalization
+  Scenario: Registration converts an internationalized domain to punycode
+    When I register with email "user@Bücher.example" and password "correct-horse-battery"
+    Then the response status code should be 201
+    And the response "email" should be "user@xn--bcher-kva.example"
+
+  @registration @email-normalization
+  Scenario: Registration accepts an internationalized top-level domain
+    When I register with email "user@пример.рф" and password "correct-horse-battery"
+    Then the response status code should be 201
+    And the response "email" should be "user@xn--e1afmkfd.xn--p1ai"
+
+  @registration @email-normalization @duplicate
+  Scenario: Registration treats Unicode forms of an address as the same
+    Given a user exists with email "user@bücher.example" and password "correct-horse-battery"
+    When I register with email "user@bücher.example" and password "newpassword123"
+    Then the response status code should be 409
+    And the response "error" should be "email_exists"
+
   # ============================================================================
   # User Login
   # ============================================================================
 
   @login @happy-path
   Scenario: Successful login
-    Given a user exists with email "login@example.com" and password "password123"
-    When I login with email "login@example.com" and password "password123"
+    Given a user exists with email "login@example.com" and password "correct-horse-battery"
+    When I login with email "login@example.com" and password "correct-horse-battery"
     Then the response status code should be 200
     And the response should contain "token"
     And the response should contain "expires_in"
     And the response "expires_in" should be 86400
 
+  @login @email-normalization
+  Scenario: Login ignores the case of the email address
+    Given a user exists with email "login@example.com" and password "correct-horse-battery"
+    When I login with email " LOGIN@Example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response should contain "token"
+
+  @login @email-normalization @lockout
+  Scenario: Failed logins count against an account whatever the case of the email
+    Given a user exists with email "login@example.com" and password "correct-horse-battery"
+    When I login with email "login@example.com" and password "wrong-horse-battery"
+    And I login with email "Login@example.com" and password "wrong-horse-battery"
+    And I login with email "LOGIN@EXAMPLE.COM" and password "wrong-horse-battery"
+    And I login with email "login@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+
   @login @validation
   Scenario: Login fails with wrong password
     Given a user exists with email "user@example.com" and password "correctpassword"
@@ -78,7 +162,7 @@ Feature: User Authentication
 
   @login @validation
   Scenario: Login fails with non-existent email
-    When I login with email "nonexistent@example.com" and password "password123"
+    When I login with email "nonexistent@example.com" and password "correct-horse-battery"
     Then the response status code should be 401
     And the response "error" should be "invalid_credentials"
     And the response "message" should be "Invalid email or password"
@@ -88,7 +172,254 @@ Feature: User Authentication
     When I login with email "" and password ""
     Then the response status code should be 400
     And the response "error" should be "validation_error"
-    And the response "message" should be "Email and password are required"
+    And the field "email" should fail the "required" rule
+    And the field "password" should fail the "required" rule
+
+  @login @refresh
+  Scenario: Login issues a refresh token
+    Given a user exists with email "login@example.com" and password "correct-horse-battery"
+    When I login with email "login@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response should contain "refresh_token"
+
+  @login @timing
+  Scenario: Failed logins take as long for unknown emails as for wrong passwords
+    Given a user exists with email "registered@example.com" and password "correct-horse-battery"
+    When I time 5 failed logins as "registered@example.com"
+    And I time 5 failed logins as "unknown@example.com"
+    Then the timed requests for "unknown@example.com" should take about as long as for "registered@example.com"
+
+  @registration @timing
+  Scenario: Registering a taken email takes as long as a new registration
+    Given a user exists with email "registered@example.com" and password "correct-horse-battery"
+    When I time 5 registrations of new accounts
+    And I time 5 registrations as "registered@example.com"
+    Then the timed requests for "registered@example.com" should take about as long as for "new accounts"
+
+  # ============================================================================
+  # Refresh Tokens
+  # ============================================================================
+
+  @refresh @happy-path
+  Scenario: Refresh token rotation
+    Given a user exists with email "refresh@example.com" and password "correct-horse-battery"
+    And I am logged in as "refresh@example.com" with password "correct-horse-battery"
+    When I refresh my token
+    Then the response status code should be 200
+    And the response should contain "token"
+    And the response should contain "refresh_token"
+    And the response "expires_in" should be 86400
+
+  @refresh @happy-path
+  Scenario: Rotated refresh token can be used again
+    Given a user exists with email "refresh@example.com" and password "correct-horse-battery"
+    And I am logged in as "refresh@example.com" with password "correct-horse-battery"
+    When I refresh my token
+    And I refresh my token
+    Then the response status code should be 200
+
+  @refresh @reuse
+  Scenario: Replaying a used refresh token revokes the token family
+    Given a user exists with email "refresh@example.com" and password "correct-horse-battery"
+    And I am logged in as "refresh@example.com" with password "correct-horse-battery"
+    When I refresh my token
+    And I refresh with my previous refresh token
+    Then the response status code should be 401
+    And the response "error" should be "refresh_token_reused"
+    When I refresh my token
+    Then the response status code should be 401
+    And the response "error" should be "invalid_refresh_token"
+    When I request my profile
+    Then the response status code should be 401
+
+  @refresh @validation
+  Scenario: Refresh fails with an unknown token
+    When I refresh with token "not-a-real-token"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_refresh_token"
+
+  @refresh @validation
+  Scenario: Refresh fails without a token
+    When I refresh with token ""
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+
+  # ============================================================================
+  # Logout
+  # ============================================================================
+
+  @logout @happy-path
+  Scenario: Logout revokes the access token
+    Given a user exists with email "logout@example.com" and password "correct-horse-battery"
+    And I am logged in as "logout@example.com" with password "correct-horse-battery"
+    When I logout
+    Then the response status code should be 204
+    When I request my profile
+    Then the response status code should be 401
+    And the response "error" should be "token_revoked"
+
+  @logout
+  Scenario: Logout with a refresh token revokes it
+    Given a user exists with email "logout@example.com" and password "correct-horse-battery"
+    And I am logged in as "logout@example.com" with password "correct-horse-battery"
+    When I logout with my refresh token
+    Then the response status code should be 204
+    When I refresh my token
+    Then the response status code should be 401
+    And the response "error" should be "invalid_refresh_token"
+
+  @logout
+  Scenario: Logout requires authentication
+    When I logout
+    Then the response status code should be 401
+
+  @logout @all-devices
+  Scenario: Logout from all devices revokes every session
+    Given a user exists with email "logout@example.com" and password "correct-horse-battery"
+    And I am logged in as "logout@example.com" with password "correct-horse-battery"
+    And I am logged in as "logout@example.com" with password "correct-horse-battery"
+    When I logout from all devices
+    Then the response status code should be 204
+    When I request my profile with my previous token
+    Then the response status code should be 401
+    When I request my profile
+    Then the response status code should be 401
+    When I refresh my token
+    Then the response status code should be 401
+    Given I am logged in as "logout@example.com" with password "correct-horse-battery"
+    When I request my profile
+    Then the response status code should be 200
+
+  # ============================================================================
+  # Account Management
+  # ============================================================================
+
+  @account @change-password @happy-path
+  Scenario: Change password signs out other sessions
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my password from "correct-horse-battery" to "newpassword456"
+    Then the response status code should be 200
+    And the response should contain "token"
+    And the response should contain "refresh_token"
+    When I request my profile with my previous token
+    Then the response status code should be 401
+    When I request my profile
+    Then the response status code should be 200
+    When I login with email "account@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+    When I login with email "account@example.com" and password "newpassword456"
+    Then the response status code should be 200
+
+  @account @change-password
+  Scenario: Change password requires the current password
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my password from "wrongpassword" to "newpassword456"
+    Then the response status code should be 403
+    And the response "error" should be "invalid_password"
+    When I request my profile
+    Then the response status code should be 200
+
+  @account @change-password @validation
+  Scenario: Change password rejects a short new password
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my password from "correct-horse-battery" to "short"
+    Then the response status code should be 400
+    And the response "error" should be "weak_password"
+    And the response should list the password violation "too_short"
+
+  @account @change-email @happy-path
+  Scenario: Change email takes effect after confirming the new address
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my email to "renamed@example.com" with password "correct-horse-battery"
+    Then the response status code should be 202
+    And 1 email should have been sent to "renamed@example.com"
+    When I login with email "account@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    When I confirm my email change using the link sent to "renamed@example.com"
+    Then the response status code should be 200
+    And the last email to "account@example.com" should mention "renamed@example.com"
+    When I login with email "account@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+    When I login with email "renamed@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @account @change-email @security
+  Scenario: Opening the email change link does not change the email
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my email to "renamed@example.com" with password "correct-horse-battery"
+    And I open the link sent to "renamed@example.com"
+    Then the response status code should be 200
+    When I verify my email using the link sent to "renamed@example.com"
+    Then the response status code should be 400
+    And the response "error" should be "email_change_not_confirmed"
+    When I login with email "account@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    When I confirm my email change using the link sent to "renamed@example.com"
+    Then the response status code should be 200
+
+  @account @change-email @security
+  Scenario: Confirming an email change signs out every session
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my email to "renamed@example.com" with password "correct-horse-battery"
+    And I confirm my email change using the link sent to "renamed@example.com"
+    Then the response status code should be 200
+    When I request my profile
+    Then the response status code should be 401
+    When I refresh my token
+    Then the response status code should be 401
+
+  @account @change-email
+  Scenario: Change email rejects an address that is already registered
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And a user exists with email "taken@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my email to "taken@example.com" with password "correct-horse-battery"
+    Then the response status code should be 409
+    And the response "error" should be "email_exists"
+
+  @account @change-email
+  Scenario: Change email requires the current password
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I change my email to "renamed@example.com" with password "wrongpassword"
+    Then the response status code should be 403
+    And the response "error" should be "invalid_password"
+    And 0 emails should have been sent to "renamed@example.com"
+
+  @account @delete-account @happy-path
+  Scenario: Delete account removes the user and their data
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    And a todo exists with title "Soon gone"
+    When I delete my account with password "correct-horse-battery"
+    Then the response status code should be 204
+    When I list my todos
+    Then the response status code should be 401
+    Given the deletion of "account@example.com" happened 31 days ago
+    And the account purge job runs
+    When I login with email "account@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I list my todos
+    Then the response should contain 0 todos
+
+  @account @delete-account
+  Scenario: Delete account requires the current password
+    Given a user exists with email "account@example.com" and password "correct-horse-battery"
+    And I am logged in as "account@example.com" with password "correct-horse-battery"
+    When I delete my account with password "wrongpassword"
+    Then the response status code should be 403
+    And the response "error" should be "invalid_password"
+    When I request my profile
+    Then the response status code should be 200
 
   # ============================================================================
   # Protected Routes (JWT Authentication)
@@ -96,8 +427,8 @@ Feature: User Authentication
 
   @protected @happy-path
   Scenario: Access protected route with valid token
-    Given a user exists with email "protected@example.com" and password "password123"
-    And I am logged in as "protected@example.com" with password "password123"
+    Given a user exists with email "protected@example.com" and password "correct-horse-battery"
+    And I am logged in as "protected@example.com" with password "correct-horse-battery"
     When I request my profile
     Then the response status code should be 200
     And the response "email" should be "protected@example.com"
@@ -0,0 +1,88 @@
+Feature: Email Verification
+  As the operator of the todolist application
+  I want new users to confirm their email address
+  So that nobody can register an address they do not own
+
+  Background:
+    Given the API server is running
+    And the database is clean
+
+  @verification @registration
+  Scenario: Registration sends a verification email
+    When I register with email "new@example.com" and password "correct-horse-battery"
+    Then the response status code should be 201
+    And the response "email_verified" should be false
+    And 1 email should have been sent to "new@example.com"
+
+  @verification @login
+  Scenario: Unverified users cannot login
+    Given an unverified user exists with email "new@example.com" and password "correct-horse-battery"
+    When I login with email "new@example.com" and password "correct-horse-battery"
+    Then the response status code should be 403
+    And the response "error" should be "email_not_verified"
+
+  @verification @login
+  Scenario: Unverified users with a wrong password get the generic error
+    Given an unverified user exists with email "new@example.com" and password "correct-horse-battery"
+    When I login with email "new@example.com" and password "wrongpassword"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_credentials"
+
+  @verification @happy-path
+  Scenario: Verifying the email allows login
+    Given an unverified user exists with email "new@example.com" and password "correct-horse-battery"
+    When I verify my email using the link sent to "new@example.com"
+    Then the response status code should be 200
+    When I login with email "new@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @verification @single-use
+  Scenario: Verification links can only be used once
+    Given an unverified user exists with email "new@example.com" and password "correct-horse-battery"
+    And I verify my email using the link sent to "new@example.com"
+    When I verify my email using the link sent to "new@example.com"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_verification_token"
+
+  @verification @validation
+  Scenario: Verification fails with an unknown token
+    When I verify my email with token "not-a-real-token"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_verification_token"
+
+  @verification @resend
+  Scenario: Resending invalidates the previous link
+    Given an unverified user exists with email "new@example.com" and password "correct-horse-battery"
+    And I remember the link sent to "new@example.com"
+    When I request a new verification email for "new@example.com"
+    Then the response status code should be 202
+    And 2 emails should have been sent to "new@example.com"
+    When I verify my email using the remembered link
+    Then the response status code should be 400
+    When I verify my email using the link sent to "new@example.com"
+    Then the response status code should be 200
+
+  @verification @resend @enumeration
+  Scenario: Resending to an unknown address looks the same
+    When I request a new verification email for "nobody@example.com"
+    Then the response status code should be 202
+    And 0 emails should have been sent to "nobody@example.com"
+
+  @verification @resend @rate-limit
+  Scenario: Resend requests for one address are throttled whether or not it is registered
+    Given an unverified user exists with email "new@example.com" and password "correct-horse-battery"
+    When I request 3 new verification emails for "new@example.com"
+    And I request a new verification email for "new@example.com"
+    Then the response status code should be 429
+    And the response "error" should be "too_many_requests"
+    And 4 emails should have been sent to "new@example.com"
+    When I request 3 new verification emails for "nobody@example.com"
+    And I request a new verification email for "nobody@example.com"
+    Then the response status code should be 429
+
+  @verification @resend
+  Scenario: Verified users are not sent another email
+    Given a user exists with email "done@example.com" and password "correct-horse-battery"
+    When I request a new verification email for "done@example.com"
+    Then the response status code should be 202
+    And 1 email should have been sent to "done@example.com"
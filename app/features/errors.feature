@@ -0,0 +1,84 @@
+Feature: Error Responses
+  As a developer integrating with the todolist API
+  I want every error reported the same way
+  So that my client can handle failures without special cases
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+
+  # ============================================================================
+  # Error Bodies
+  # ============================================================================
+
+  @errors
+  Scenario: Domain errors carry a code and a message
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I get the todo with id "00000000-0000-0000-0000-000000000000"
+    Then the response status code should be 404
+    And the response content type should be "application/json"
+    And the response "error" should be "todo_not_found"
+    And the response "message" should be "Todo not found"
+
+  @errors
+  Scenario: Unknown routes are reported as errors
+    When I send a GET request to "/api/v1/nothing-here"
+    Then the response status code should be 404
+    And the response "error" should be "not_found"
+
+  @errors
+  Scenario: Unsupported methods are reported as errors
+    When I send a PUT request to "/health"
+    Then the response status code should be 405
+    And the response "error" should be "method_not_allowed"
+
+  @errors
+  Scenario: Errors can carry details
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I create a personal access token "script" with scopes "todos:read,admin"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_scope"
+    And the response detail "scope" should be "admin"
+
+  @errors
+  Scenario: Errors without details leave them out
+    When I login with email "owner@example.com" and password "wrong-password"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_credentials"
+    And the response should not contain "details"
+
+  # ============================================================================
+  # Problem Details
+  # ============================================================================
+
+  @errors @problem-details
+  Scenario: Clients can ask for RFC 7807 problem details
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    And I accept problem details
+    When I get the todo with id "00000000-0000-0000-0000-000000000000"
+    Then the response status code should be 404
+    And the response content type should be "application/problem+json"
+    And the response "type" should be "about:blank"
+    And the response "title" should be "Not Found"
+    And the response "status" should be 404
+    And the response "detail" should be "Todo not found"
+    And the response "code" should be "todo_not_found"
+    And the response "instance" should be "/api/v1/todos/00000000-0000-0000-0000-000000000000"
+
+  @errors @problem-details
+  Scenario: Problem details keep password policy violations
+    Given I accept problem details
+    When I register with email "new@example.com" and password "short"
+    Then the response status code should be 400
+    And the response content type should be "application/problem+json"
+    And the response "code" should be "weak_password"
+    And the response should list the password violation "too_short"
+
+  @errors @problem-details
+  Scenario: Problem details cover authentication failures
+    Given I accept problem details
+    When I request my profile without authentication
+    Then the response status code should be 401
+    And the response content type should be "application/problem+json"
+    And the response "code" should be "missing_token"
@@ -0,0 +1,68 @@
+Feature: Asymmetric Token Signing
+  As a service that consumes todolist access tokens
+  I want tokens signed with published asymmetric keys
+  So that I can verify them without holding a shared secret
+
+  Background:
+    Given the database is clean
+
+  @jwks @hmac
+  Scenario: JWKS is empty when signing with a shared secret
+    Given the API server is running
+    When I fetch the JWKS
+    Then the response status code should be 200
+    And the JWKS should contain keys ""
+
+  @jwks @eddsa
+  Scenario: Tokens are signed with an Ed25519 key
+    Given the API server signs tokens with an Ed25519 key "key-1"
+    And a user exists with email "keys@example.com" and password "correct-horse-battery"
+    And I am logged in as "keys@example.com" with password "correct-horse-battery"
+    Then my token header "alg" should be "EdDSA"
+    And my token header "kid" should be "key-1"
+    When I request my profile
+    Then the response status code should be 200
+
+  @jwks @rsa
+  Scenario: Tokens are signed with an RSA key and published in the JWKS
+    Given the API server signs tokens with an RSA key "rsa-1"
+    And a user exists with email "keys@example.com" and password "correct-horse-battery"
+    And I am logged in as "keys@example.com" with password "correct-horse-battery"
+    Then my token header "alg" should be "RS256"
+    When I fetch the JWKS
+    Then the response status code should be 200
+    And the JWKS should contain keys "rsa-1"
+
+  @jwks @rotation
+  Scenario: Rotating keys keeps existing tokens valid
+    Given the API server signs tokens with an Ed25519 key "key-1"
+    And a user exists with email "keys@example.com" and password "correct-horse-battery"
+    And I am logged in as "keys@example.com" with password "correct-horse-battery"
+    When the signing key is rotated to a new Ed25519 key "key-2"
+    And I request my profile
+    Then the response status code should be 200
+    When I fetch the JWKS
+    Then the JWKS should contain keys "key-2,key-1"
+    Given I am logged in as "keys@example.com" with password "correct-horse-battery"
+    Then my token header "kid" should be "key-2"
+
+  @jwks @rotation
+  Scenario: Tokens signed by a retired key are rejected
+    Given the API server signs tokens with an Ed25519 key "key-1"
+    And a user exists with email "keys@example.com" and password "correct-horse-battery"
+    And I am logged in as "keys@example.com" with password "correct-horse-battery"
+    When the signing key is rotated to a new Ed25519 key "key-2"
+    And the signing key "key-1" is retired
+    And I request my profile
+    Then the response status code should be 401
+    When I fetch the JWKS
+    Then the JWKS should contain keys "key-2"
+
+  @jwks @security
+  Scenario: Tokens signed with the shared secret are rejected when using asymmetric keys
+    Given the API server is running
+    And a user exists with email "keys@example.com" and password "correct-horse-battery"
+    And I am logged in as "keys@example.com" with password "correct-horse-battery"
+    When the API server signs tokens with an Ed25519 key "key-1"
+    And I request my profile
+    Then the response status code should be 401
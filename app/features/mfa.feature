@@ -0,0 +1,249 @@
+Feature: Two-factor Authentication
+  As a user of the todolist application
+  I want to protect my account with an authenticator app
+  So that a stolen password is not enough to sign in as me
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "user@example.com" and password "correct-horse-battery"
+    And I am logged in as "user@example.com" with password "correct-horse-battery"
+
+  # ============================================================================
+  # Enrollment
+  # ============================================================================
+
+  @mfa @enrollment
+  Scenario: Enroll an authenticator app
+    When I enroll an authenticator app
+    Then the response status code should be 201
+    And the response should contain "secret"
+    And the response should contain "otpauth_uri"
+    When I confirm the authenticator with a valid code
+    Then the response status code should be 200
+    And the response should list 10 recovery codes
+    When I request my two-factor authentication status
+    Then the response "enabled" should be true
+    And the response "recovery_codes_remaining" should be 10
+
+  @mfa @enrollment
+  Scenario: Enrollment is not confirmed with a wrong code
+    When I enroll an authenticator app
+    And I confirm the authenticator with code "000000"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_mfa_code"
+    When I request my two-factor authentication status
+    Then the response "enabled" should be false
+
+  @mfa @enrollment
+  Scenario: Login is unchanged until the authenticator is confirmed
+    When I enroll an authenticator app
+    And I login with email "user@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response should contain "token"
+
+  @mfa @enrollment @lockout
+  Scenario: Wrong enrollment codes count towards the two-factor lock
+    When I enroll an authenticator app
+    And I confirm the authenticator with code "000000"
+    And I confirm the authenticator with code "000000"
+    And I confirm the authenticator with code "000000"
+    And I confirm the authenticator with code "000000"
+    And I confirm the authenticator with code "000000"
+    And I confirm the authenticator with a valid code
+    Then the response status code should be 429
+    And the response "error" should be "account_locked"
+
+  @mfa @enrollment
+  Scenario: Cannot enroll twice
+    Given I have enabled two-factor authentication
+    When I enroll an authenticator app
+    Then the response status code should be 409
+    And the response "error" should be "mfa_already_enabled"
+
+  # ============================================================================
+  # Two-step Login
+  # ============================================================================
+
+  @mfa @login
+  Scenario: Login asks for a second factor
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response "mfa_required" should be true
+    And the response should contain "mfa_token"
+    And the response "expires_in" should be 300
+
+  @mfa @login
+  Scenario: Complete the login with an authenticator code
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with a valid authenticator code
+    Then the response status code should be 200
+    And the response should contain "token"
+    And the response should contain "refresh_token"
+    When I request my profile
+    Then the response status code should be 200
+
+  @mfa @login
+  Scenario: Wrong authenticator code is rejected
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with code "000000"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_mfa_code"
+
+  @mfa @login
+  Scenario: Authenticator code cannot be replayed
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with a valid authenticator code
+    And I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with the same authenticator code again
+    Then the response status code should be 401
+    And the response "error" should be "invalid_mfa_code"
+
+  @mfa @login
+  Scenario: Challenge cannot be used twice
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with a recovery code
+    And I verify the login with code "000000"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_mfa_challenge"
+
+  @mfa @login
+  Scenario: Challenge is burned after too many wrong codes
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I fail to verify the login 3 times
+    And I verify the login with a valid authenticator code
+    Then the response status code should be 401
+    And the response "error" should be "invalid_mfa_challenge"
+
+  @mfa @login
+  Scenario: Parallel wrong codes cannot check more codes than the limit
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I fail to verify the login 8 times in parallel
+    Then 3 of the parallel requests should have failed with "invalid_mfa_code"
+    And 5 of the parallel requests should have failed with "invalid_mfa_challenge"
+
+  @mfa @login @lockout
+  Scenario: Too many wrong codes across challenges lock two-step logins
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I fail to verify the login 3 times
+    And I login with email "user@example.com" and password "correct-horse-battery"
+    And I fail to verify the login 2 times
+    And I login with email "user@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "account_locked"
+
+  @mfa @login
+  Scenario: Unknown challenge is rejected
+    Given I have enabled two-factor authentication
+    When I verify the challenge "not-a-real-challenge" with code "123456"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_mfa_challenge"
+
+  # ============================================================================
+  # Recovery Codes
+  # ============================================================================
+
+  @mfa @recovery
+  Scenario: Recovery code completes the login once
+    Given I have enabled two-factor authentication
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with a recovery code
+    Then the response status code should be 200
+    And the response should contain "token"
+    When I request my two-factor authentication status
+    Then the response "recovery_codes_remaining" should be 9
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with a recovery code
+    Then the response status code should be 401
+    And the response "error" should be "invalid_mfa_code"
+
+  @mfa @recovery
+  Scenario: Regenerating recovery codes replaces the old ones
+    Given I have enabled two-factor authentication
+    When I regenerate my recovery codes with password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response should list 10 recovery codes
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    And I verify the login with an old recovery code
+    Then the response status code should be 401
+    When I verify the login with a recovery code
+    Then the response status code should be 200
+
+  @mfa @recovery
+  Scenario: Regenerating recovery codes requires the password
+    Given I have enabled two-factor authentication
+    When I regenerate my recovery codes with password "wrong-password"
+    Then the response status code should be 403
+    And the response "error" should be "invalid_password"
+
+  @mfa @recovery
+  Scenario: Regenerating recovery codes requires a second factor
+    Given I have enabled two-factor authentication
+    When I regenerate my recovery codes with password "correct-horse-battery" and code "000000"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_mfa_code"
+    When I request my two-factor authentication status
+    Then the response "recovery_codes_remaining" should be 10
+
+  @mfa @recovery
+  Scenario: A recovery code can stand in for the authenticator when regenerating
+    Given I have enabled two-factor authentication
+    When I regenerate my recovery codes with password "correct-horse-battery" and a recovery code
+    Then the response status code should be 200
+    And the response should list 10 recovery codes
+
+  # ============================================================================
+  # Disabling
+  # ============================================================================
+
+  @mfa @disable
+  Scenario: Disable two-factor authentication
+    Given I have enabled two-factor authentication
+    When I disable two-factor authentication with password "correct-horse-battery"
+    Then the response status code should be 204
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the response should contain "token"
+
+  @mfa @disable
+  Scenario: Disabling requires the password
+    Given I have enabled two-factor authentication
+    When I disable two-factor authentication with password "wrong-password"
+    Then the response status code should be 403
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    Then the response "mfa_required" should be true
+
+  @mfa @disable
+  Scenario: Disabling requires a second factor
+    Given I have enabled two-factor authentication
+    When I disable two-factor authentication with password "correct-horse-battery" and code "000000"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_mfa_code"
+    When I login with email "user@example.com" and password "correct-horse-battery"
+    Then the response "mfa_required" should be true
+
+  @mfa @disable @lockout
+  Scenario: Too many wrong codes lock disabling two-factor authentication
+    Given I have enabled two-factor authentication
+    When I disable two-factor authentication with password "correct-horse-battery" and code "000000"
+    And I disable two-factor authentication with password "correct-horse-battery" and code "000000"
+    And I disable two-factor authentication with password "correct-horse-battery" and code "000000"
+    And I disable two-factor authentication with password "correct-horse-battery" and code "000000"
+    And I disable two-factor authentication with password "correct-horse-battery" and code "000000"
+    And I disable two-factor authentication with password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "account_locked"
+
+  @mfa @disable
+  Scenario: Cannot disable when not enabled
+    When I disable two-factor authentication with password "correct-horse-battery" and code "123456"
+    Then the response status code should be 400
+    And the response "error" should be "mfa_not_enabled"
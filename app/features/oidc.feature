@@ -0,0 +1,158 @@
+Feature: Sign in with an Identity Provider
+  As a user of the todolist application
+  I want to sign in with an account I already have elsewhere
+  So that I do not need another password
+
+  Background:
+    Given the API server is running
+    And the database is clean
+
+  # ============================================================================
+  # Sign-in
+  # ============================================================================
+
+  @oidc
+  Scenario: List the identity providers
+    When I list the identity providers
+    Then the response status code should be 200
+    And the response should contain "providers"
+
+  @oidc @signin
+  Scenario: First sign-in creates an account
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    Then the response status code should be 200
+    And the response should contain "token"
+    And the response should contain "refresh_token"
+    When I request my profile
+    Then the response status code should be 200
+    And the response "email" should be "newcomer@example.com"
+
+  @oidc @signin
+  Scenario: Returning sign-in uses the same account
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    And I sign in with the identity provider
+    Then the response status code should be 200
+    When I list my linked identities
+    Then the response should list 1 linked identity
+
+  @oidc @signin
+  Scenario: Existing accounts are not taken over by email
+    Given a user exists with email "user@example.com" and password "correct-horse-battery"
+    And the identity provider signs in "user@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    Then the response status code should be 409
+    And the response "error" should be "account_exists"
+
+  @oidc @signin
+  Scenario: Unverified provider emails cannot create accounts
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1" without verifying the email
+    When I sign in with the identity provider
+    Then the response status code should be 403
+    And the response "error" should be "email_not_verified"
+
+  @oidc @signin
+  Scenario: Accounts created by a provider have no password
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    And I login with email "newcomer@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+
+  @oidc @signin
+  Scenario: Sign-in denied at the provider
+    Given the identity provider denies the sign-in
+    When I sign in with the identity provider
+    Then the response status code should be 401
+    And the response "error" should be "oidc_auth_failed"
+
+  @oidc @signin
+  Scenario: Unknown provider
+    When I sign in with identity provider "nope"
+    Then the response status code should be 404
+    And the response "error" should be "provider_not_found"
+
+  @oidc @signin
+  Scenario: Sign-in with two-factor authentication still asks for a code
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    And I have enabled two-factor authentication
+    And I sign in with the identity provider
+    Then the response status code should be 200
+    And the response "mfa_required" should be true
+
+  # ============================================================================
+  # State Validation
+  # ============================================================================
+
+  @oidc @security
+  Scenario: Callback with a forged state is rejected
+    When I open the identity provider callback with state "forged-state"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_oidc_state"
+
+  @oidc @security
+  Scenario: Callback cannot be replayed
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    And I replay the identity provider callback
+    Then the response status code should be 400
+    And the response "error" should be "invalid_oidc_state"
+
+  # ============================================================================
+  # Linking
+  # ============================================================================
+
+  @oidc @linking
+  Scenario: Link a provider and sign in with it
+    Given a user exists with email "user@example.com" and password "correct-horse-battery"
+    And I am logged in as "user@example.com" with password "correct-horse-battery"
+    And the identity provider signs in "user@elsewhere.example" with subject "idp-2"
+    When I link the identity provider to my account
+    Then the response status code should be 200
+    And the response "provider" should be "stub"
+    And the response "subject" should be "idp-2"
+    When I sign in with the identity provider
+    And I request my profile
+    Then the response "email" should be "user@example.com"
+
+  @oidc @linking @security
+  Scenario: A link cannot be completed in a browser other than the one that started it
+    Given a user exists with email "user@example.com" and password "correct-horse-battery"
+    And I am logged in as "user@example.com" with password "correct-horse-battery"
+    And the identity provider signs in "someone@elsewhere.example" with subject "idp-2"
+    When I start linking the identity provider to my account
+    And the link is opened in another browser
+    Then the response status code should be 400
+    And the response "error" should be "invalid_oidc_state"
+    When I list my linked identities
+    Then the response should list 0 linked identities
+
+  @oidc @linking
+  Scenario: Identity linked to another account cannot be linked again
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    And I sign in with the identity provider
+    And a user exists with email "user@example.com" and password "correct-horse-battery"
+    And I am logged in as "user@example.com" with password "correct-horse-battery"
+    When I link the identity provider to my account
+    Then the response status code should be 409
+    And the response "error" should be "identity_already_linked"
+
+  @oidc @linking
+  Scenario: Unlink a provider
+    Given a user exists with email "user@example.com" and password "correct-horse-battery"
+    And I am logged in as "user@example.com" with password "correct-horse-battery"
+    And the identity provider signs in "user@elsewhere.example" with subject "idp-2"
+    When I link the identity provider to my account
+    And I unlink the identity provider
+    Then the response status code should be 204
+    When I list my linked identities
+    Then the response should list 0 linked identities
+
+  @oidc @linking
+  Scenario: The only sign-in method cannot be unlinked
+    Given the identity provider signs in "newcomer@example.com" with subject "idp-1"
+    When I sign in with the identity provider
+    And I unlink the identity provider
+    Then the response status code should be 409
+    And the response "error" should be "last_sign_in_method"
@@ -0,0 +1,38 @@
+Feature: Password Hashing
+  As the operator of the todolist application
+  I want passwords hashed with Argon2id and older hashes upgraded transparently
+  So that stored credentials keep up with current hashing recommendations
+
+  Background:
+    Given the API server is running
+    And the database is clean
+
+  @password-hashing
+  Scenario: New passwords are hashed with Argon2id
+    When I register with email "fresh@example.com" and password "correct-horse-battery"
+    Then the response status code should be 201
+    And the stored password hash for "fresh@example.com" should use "argon2id"
+    And the stored password hash for "fresh@example.com" should be up to date
+
+  @password-hashing @rehash
+  Scenario: A legacy bcrypt hash is upgraded on login
+    Given a user exists with email "legacy@example.com" whose password "correct-horse-battery" is hashed with bcrypt cost 4
+    When I login with email "legacy@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the stored password hash for "legacy@example.com" should use "argon2id"
+    When I login with email "legacy@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @password-hashing @rehash
+  Scenario: An Argon2id hash with outdated parameters is upgraded on login
+    Given a user exists with email "weak@example.com" whose password "correct-horse-battery" is hashed with argon2id memory 8192
+    When I login with email "weak@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    And the stored password hash for "weak@example.com" should be up to date
+
+  @password-hashing @rehash
+  Scenario: A failed login leaves the legacy hash untouched
+    Given a user exists with email "legacy@example.com" whose password "correct-horse-battery" is hashed with bcrypt cost 4
+    When I login with email "legacy@example.com" and password "wrongpassword"
+    Then the response status code should be 401
+    And the stored password hash for "legacy@example.com" should use "bcrypt"
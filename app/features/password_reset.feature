@@ -0,0 +1,90 @@
+Feature: Password Reset
+  As a user who forgot my password
+  I want to reset it through a link sent to my email
+  So that I can regain access to my account
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "forgetful@example.com" and password "correct-horse-battery"
+
+  @password-reset @enumeration
+  Scenario: Requesting a reset always returns 202
+    When I request a password reset for "forgetful@example.com"
+    Then the response status code should be 202
+    And 2 emails should have been sent to "forgetful@example.com"
+    When I request a password reset for "nobody@example.com"
+    Then the response status code should be 202
+    And 0 emails should have been sent to "nobody@example.com"
+
+  @password-reset @happy-path
+  Scenario: Resetting the password changes the login credentials
+    Given I request a password reset for "forgetful@example.com"
+    When I reset my password to "brandnew456" using the link sent to "forgetful@example.com"
+    Then the response status code should be 200
+    When I login with email "forgetful@example.com" and password "correct-horse-battery"
+    Then the response status code should be 401
+    When I login with email "forgetful@example.com" and password "brandnew456"
+    Then the response status code should be 200
+
+  @password-reset @sessions
+  Scenario: Resetting the password revokes existing sessions
+    Given I am logged in as "forgetful@example.com" with password "correct-horse-battery"
+    And I request a password reset for "forgetful@example.com"
+    When I reset my password to "brandnew456" using the link sent to "forgetful@example.com"
+    Then the response status code should be 200
+    When I request my profile
+    Then the response status code should be 401
+    When I refresh my token
+    Then the response status code should be 401
+
+  @password-reset @single-use
+  Scenario: Reset links can only be used once
+    Given I request a password reset for "forgetful@example.com"
+    And I reset my password to "brandnew456" using the link sent to "forgetful@example.com"
+    When I reset my password to "another789" using the link sent to "forgetful@example.com"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_reset_token"
+
+  @password-reset @single-use
+  Scenario: A successful reset invalidates other outstanding links
+    Given I request a password reset for "forgetful@example.com"
+    And I remember the link sent to "forgetful@example.com"
+    And I request a password reset for "forgetful@example.com"
+    And I reset my password to "brandnew456" using the link sent to "forgetful@example.com"
+    When I reset my password to "another789" using the remembered link
+    Then the response status code should be 400
+    And the response "error" should be "invalid_reset_token"
+
+  @password-reset @validation
+  Scenario: Reset fails with an unknown token
+    When I reset my password to "brandnew456" with token "not-a-real-token"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_reset_token"
+
+  @password-reset @validation
+  Scenario: Reset enforces the password policy
+    Given I request a password reset for "forgetful@example.com"
+    When I reset my password to "short" using the link sent to "forgetful@example.com"
+    Then the response status code should be 400
+    And the response "error" should be "weak_password"
+    And the response should list the password violation "too_short"
+
+  @password-reset @rate-limit
+  Scenario: Reset requests for one address are throttled whether or not it is registered
+    When I request 3 password resets for "forgetful@example.com"
+    And I request a password reset for "forgetful@example.com"
+    Then the response status code should be 429
+    And the response "error" should be "too_many_requests"
+    And 4 emails should have been sent to "forgetful@example.com"
+    When I request 3 password resets for "nobody@example.com"
+    And I request a password reset for "nobody@example.com"
+    Then the response status code should be 429
+    And the response "error" should be "too_many_requests"
+
+  @password-reset @rate-limit
+  Scenario: Reset requests from one client are throttled
+    When I request password resets for 10 different addresses
+    And I request a password reset for "forgetful@example.com"
+    Then the response status code should be 429
+    And the response "error" should be "too_many_requests"
@@ -0,0 +1,182 @@
+Feature: Personal Access Tokens
+  As a user of the todolist application
+  I want to create API tokens for my scripts and integrations
+  So that they do not need my password
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "user@example.com" and password "correct-horse-battery"
+    And I am logged in as "user@example.com" with password "correct-horse-battery"
+
+  # ============================================================================
+  # Managing Tokens
+  # ============================================================================
+
+  @tokens
+  Scenario: Create a personal access token
+    When I create a personal access token "backup script" with scopes "todos:read"
+    Then the response status code should be 201
+    And the response "name" should be "backup script"
+    And the response should contain "token"
+    And the personal access token should start with "tdl_pat_"
+    And the response should have no expiry time
+
+  @tokens
+  Scenario: Create an expiring personal access token
+    When I create a personal access token "ci" with scopes "todos:read,todos:write" expiring in 30 days
+    Then the response status code should be 201
+    And the response should have an expiry time
+
+  @tokens
+  Scenario: Listing tokens does not reveal them
+    Given I have a personal access token with scopes "todos:read"
+    When I list my personal access tokens
+    Then the response status code should be 200
+    And the response should list 1 personal access token
+    And the listed tokens should not reveal their value
+
+  @tokens @validation
+  Scenario: Tokens need a name
+    When I create a personal access token "" with scopes "todos:read"
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+
+  @tokens @validation
+  Scenario: Tokens need at least one scope
+    When I create a personal access token "script" with scopes ""
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+
+  @tokens @validation
+  Scenario: Unknown scopes are rejected
+    When I create a personal access token "script" with scopes "todos:read,admin"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_scope"
+
+  @tokens @validation
+  Scenario: Expiry cannot be in the past
+    When I create a personal access token "script" with scopes "todos:read" expiring in -1 days
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+
+  @tokens
+  Scenario: Revoke a personal access token
+    Given I have a personal access token with scopes "todos:read"
+    When I revoke my personal access token
+    Then the response status code should be 204
+    When I list my personal access tokens
+    Then the response should list 0 personal access tokens
+
+  @tokens
+  Scenario: Revoking an unknown token
+    When I revoke the personal access token with id "00000000-0000-0000-0000-000000000000"
+    Then the response status code should be 404
+    And the response "error" should be "token_not_found"
+
+  @tokens @security
+  Scenario: Tokens of other users cannot be revoked
+    Given I have a personal access token with scopes "todos:read"
+    And a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I revoke my personal access token
+    Then the response status code should be 404
+
+  # ============================================================================
+  # Authenticating with Tokens
+  # ============================================================================
+
+  @tokens @auth
+  Scenario: Read todos with a personal access token
+    Given a todo exists with title "Buy milk"
+    And I have a personal access token with scopes "todos:read"
+    When I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 200
+    And the response should contain 1 todos
+
+  @tokens @auth
+  Scenario: Create todos with a write token
+    Given I have a personal access token with scopes "todos:write"
+    When I authenticate with my personal access token
+    And I create a todo with title "From a script"
+    Then the response status code should be 201
+
+  @tokens @auth @scopes
+  Scenario: Read-only tokens cannot create todos
+    Given I have a personal access token with scopes "todos:read"
+    When I authenticate with my personal access token
+    And I create a todo with title "From a script"
+    Then the response status code should be 403
+    And the response "error" should be "insufficient_scope"
+
+  @tokens @auth @scopes
+  Scenario: Tokens cannot manage the account
+    Given I have a personal access token with scopes "todos:read,todos:write"
+    When I authenticate with my personal access token
+    And I request my profile
+    Then the response status code should be 403
+    And the response "error" should be "insufficient_scope"
+
+  @tokens @auth @scopes
+  Scenario: Tokens cannot create more tokens
+    Given I have a personal access token with scopes "todos:read,todos:write"
+    When I authenticate with my personal access token
+    And I create a personal access token "escalation" with scopes "todos:write"
+    Then the response status code should be 403
+    And the response "error" should be "insufficient_scope"
+
+  @tokens @auth
+  Scenario: Revoked tokens are rejected
+    Given I have a personal access token with scopes "todos:read"
+    And I revoke my personal access token
+    When I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 401
+    And the response "error" should be "token_revoked"
+
+  @tokens @auth
+  Scenario: Expired tokens are rejected
+    Given I have a personal access token with scopes "todos:read"
+    And my personal access token has expired
+    When I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 401
+    And the response "error" should be "invalid_token"
+
+  @tokens @auth
+  Scenario: Unknown tokens are rejected
+    When I request my profile with token "tdl_pat_not-a-real-token"
+    Then the response status code should be 401
+    And the response "error" should be "invalid_token"
+
+  @tokens @auth
+  Scenario: Using a token records when it was last used
+    Given I have a personal access token with scopes "todos:read"
+    When I authenticate with my personal access token
+    And I list my todos
+    And I authenticate with my session again
+    And I list my personal access tokens
+    Then the first listed token should have been used
+
+  # ============================================================================
+  # Sessions
+  # ============================================================================
+
+  @tokens @sessions
+  Scenario: Tokens survive logging out everywhere
+    Given I have a personal access token with scopes "todos:read"
+    And I logout from all devices
+    When I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 200
+
+  @tokens @sessions
+  Scenario: Resetting the password revokes tokens
+    Given I have a personal access token with scopes "todos:read"
+    And I request a password reset for "user@example.com"
+    And I reset my password to "brandnew-horse-battery" using the link sent to "user@example.com"
+    When I authenticate with my personal access token
+    And I list my todos
+    Then the response status code should be 401
+    And the response "error" should be "token_revoked"
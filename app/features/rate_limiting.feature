@@ -0,0 +1,85 @@
+Feature: Brute-force Protection
+  As the operator of the todolist application
+  I want repeated login failures and request floods to be throttled
+  So that attackers cannot guess passwords or hammer the auth endpoints
+
+  Background:
+    Given the API server is running
+    And the database is clean
+
+  # ============================================================================
+  # Account Lockout
+  # ============================================================================
+
+  @rate-limit @lockout
+  Scenario: Account is locked after repeated failed logins
+    Given a user exists with email "victim@example.com" and password "correct-horse-battery"
+    When I fail to login 3 times as "victim@example.com"
+    And I login with email "victim@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "account_locked"
+    And the response should ask me to retry within 60 seconds
+
+  @rate-limit @lockout
+  Scenario: Failures below the limit are forgotten after a successful login
+    Given a user exists with email "victim@example.com" and password "correct-horse-battery"
+    When I fail to login 2 times as "victim@example.com"
+    And I login with email "victim@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+    When I fail to login 2 times as "victim@example.com"
+    And I login with email "victim@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @rate-limit @lockout
+  Scenario: Parallel failed logins cannot check more passwords than the limit
+    Given a user exists with email "victim@example.com" and password "correct-horse-battery"
+    When I fail to login 8 times in parallel as "victim@example.com"
+    Then 3 of the parallel logins should have returned 401
+    And 5 of the parallel logins should have returned 429
+    When I login with email "victim@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "account_locked"
+
+  @rate-limit @lockout
+  Scenario: Lockout only affects the targeted account
+    Given a user exists with email "victim@example.com" and password "correct-horse-battery"
+    And a user exists with email "bystander@example.com" and password "correct-horse-battery"
+    When I fail to login 3 times as "victim@example.com"
+    And I login with email "bystander@example.com" and password "correct-horse-battery"
+    Then the response status code should be 200
+
+  @rate-limit @lockout
+  Scenario: Unknown accounts are locked like existing ones
+    When I fail to login 3 times as "nobody@example.com"
+    And I login with email "nobody@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "account_locked"
+
+  # ============================================================================
+  # Per-IP Throttling
+  # ============================================================================
+
+  @rate-limit @throttle
+  Scenario: Registration is throttled per client IP
+    Given my requests come from "203.0.113.7"
+    When I register 10 accounts
+    And I register with email "one-too-many@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "too_many_requests"
+    And the response should ask me to retry within 60 seconds
+    Given my requests come from "203.0.113.8"
+    When I register with email "one-too-many@example.com" and password "correct-horse-battery"
+    Then the response status code should be 201
+
+  @rate-limit @throttle
+  Scenario: Login is throttled per client IP across accounts
+    Given a user exists with email "victim@example.com" and password "correct-horse-battery"
+    And my requests come from "203.0.113.7"
+    When I fail to login 2 times as "first@example.com"
+    And I fail to login 2 times as "second@example.com"
+    And I fail to login 2 times as "third@example.com"
+    And I fail to login 2 times as "fourth@example.com"
+    And I fail to login 2 times as "fifth@example.com"
+    And I login with email "victim@example.com" and password "correct-horse-battery"
+    Then the response status code should be 429
+    And the response "error" should be "too_many_requests"
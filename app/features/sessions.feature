@@ -0,0 +1,105 @@
+Feature: Session Management
+  As a user of the todolist application
+  I want to see where I am signed in
+  So that I can sign out devices I no longer use
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "alice@example.com" and password "correct-horse-battery"
+
+  Scenario: List the current session
+    Given I am using the user agent "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I list my sessions
+    Then the response status code should be 200
+    And the response should list 1 session
+    And the current session should be labelled "Chrome on macOS"
+
+  Scenario: Each login starts a new session
+    Given I am using the user agent "Mozilla/5.0 (X11; Linux x86_64; rv:118.0) Gecko/20100101 Firefox/118.0"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am using the user agent "curl/8.4.0"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I list my sessions
+    Then the response status code should be 200
+    And the response should list 2 sessions
+    And the current session should be listed first
+    And the current session should be labelled "curl"
+
+  Scenario: Refreshing tokens keeps the session
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I remember my current session
+    When I refresh my token
+    And I list my sessions
+    Then the response should list 1 session
+    And the current session should be the remembered one
+
+  Scenario: Signing out another session revokes its tokens
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I sign out my other session
+    Then the response status code should be 204
+    When I request my profile with my previous token
+    Then the response status code should be 401
+    And the response "error" should be "token_revoked"
+    When I list my sessions
+    Then the response should list 1 session
+
+  Scenario: A signed out session cannot be refreshed
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I sign out my other session
+    When I switch back to my previous login
+    And I refresh my token
+    Then the response status code should be 401
+    And the response "error" should be "invalid_refresh_token"
+
+  Scenario: The current session keeps working after signing out another
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I sign out my other session
+    And I request my profile
+    Then the response status code should be 200
+
+  Scenario: Signing out an unknown session fails
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I sign out the session with id "00000000-0000-0000-0000-000000000000"
+    Then the response status code should be 404
+    And the response "error" should be "session_not_found"
+
+  Scenario: Signing out a session with a malformed id fails
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I sign out the session with id "not-a-session"
+    Then the response status code should be 404
+    And the response "error" should be "session_not_found"
+
+  Scenario: Users cannot sign out each other's sessions
+    Given a user exists with email "bob@example.com" and password "correct-horse-battery"
+    And I am logged in as "bob@example.com" with password "correct-horse-battery"
+    And I remember my current session
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I sign out the remembered session
+    Then the response status code should be 404
+    When I switch back to my previous login
+    And I request my profile
+    Then the response status code should be 200
+
+  Scenario: Logging out ends the session
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I logout
+    And I switch back to my previous login
+    And I list my sessions
+    Then the response should list 1 session
+
+  Scenario: Logging out from all devices ends every session
+    Given I am logged in as "alice@example.com" with password "correct-horse-battery"
+    And I am logged in as "alice@example.com" with password "correct-horse-battery"
+    When I logout from all devices
+    And I request my profile with my previous token
+    Then the response status code should be 401
+
+  Scenario: Listing sessions requires authentication
+    When I list my sessions
+    Then the response status code should be 401
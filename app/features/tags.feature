@@ -0,0 +1,158 @@
+Feature: Tags
+  As a logged in user of the todolist application
+  I want to manage the tags I put on my todos
+  So that I can keep my labels tidy as my todos grow
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+    And I am logged in as "owner@example.com" with password "correct-horse-battery"
+    And the following todos exist:
+      | title     | tags       |
+      | Taxes     | home,money |
+      | Report    | work       |
+      | Budget    | money,work |
+      | Groceries | home       |
+
+  # ============================================================================
+  # Listing and creating
+  # ============================================================================
+
+  @tags @happy-path
+  Scenario: Tags are created from todos and listed with their usage counts
+    When I list my tags
+    Then the response status code should be 200
+    And the listed tags should be "home:2,money:2,work:2"
+    And the listed tag "color" values should be "#6b7280,#6b7280,#6b7280"
+
+  @tags @happy-path
+  Scenario: Create a tag with a color
+    When I create a tag with:
+      """
+      {"name": "errands", "color": "#1E88E5"}
+      """
+    Then the response status code should be 201
+    And the response "name" should be "errands"
+    And the response "color" should be "#1e88e5"
+    And the response "todo_count" should be 0
+    When I list my tags
+    Then the listed tags should be "errands:0,home:2,money:2,work:2"
+
+  @tags @happy-path
+  Scenario: Get a tag with its usage count
+    When I get the tag "money"
+    Then the response status code should be 200
+    And the response "name" should be "money"
+    And the response "todo_count" should be 2
+
+  @tags @validation
+  Scenario: Tag names are unique per user
+    When I create a tag with:
+      """
+      {"name": "home"}
+      """
+    Then the response status code should be 409
+    And the response "error" should be "tag_exists"
+
+  @tags @validation
+  Scenario: Tag colors must be hex colors
+    When I create a tag with:
+      """
+      {"name": "errands", "color": "blue"}
+      """
+    Then the response status code should be 400
+    And the response "error" should be "invalid_tag_color"
+
+  @tags @validation
+  Scenario: Todo tags longer than 50 characters are rejected
+    When I create a todo with:
+      """
+      {"title": "Label me", "tags": ["aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]}
+      """
+    Then the response status code should be 400
+    And the response "error" should be "tag_name_too_long"
+
+  # ============================================================================
+  # Renaming
+  # ============================================================================
+
+  @tags @rename
+  Scenario: Renaming a tag relabels every todo
+    When I update the tag "money" with:
+      """
+      {"name": "finance", "color": "#43a047"}
+      """
+    Then the response status code should be 200
+    And the response "name" should be "finance"
+    And the response "color" should be "#43a047"
+    And the response "todo_count" should be 2
+    When I list my todos with query "tag=finance&sort=created_at&order=asc"
+    Then the listed todos should be "Taxes,Budget"
+    And the listed todo "tags" values should be "[home finance],[finance work]"
+    When I list my todos with query "tag=money"
+    Then the response should contain 0 todos
+
+  @tags @rename
+  Scenario: A tag cannot be renamed to the name of another tag
+    When I update the tag "money" with:
+      """
+      {"name": "work"}
+      """
+    Then the response status code should be 409
+    And the response "error" should be "tag_exists"
+    When I list my tags
+    Then the listed tags should be "home:2,money:2,work:2"
+
+  # ============================================================================
+  # Merging
+  # ============================================================================
+
+  @tags @merge
+  Scenario: Merging a tag moves its todos to the target and removes it
+    When I merge the tag "money" into "work"
+    Then the response status code should be 200
+    And the response "name" should be "work"
+    And the response "todo_count" should be 3
+    When I list my tags
+    Then the listed tags should be "home:2,work:3"
+    When I list my todos with query "sort=created_at&order=asc"
+    Then the listed todo "tags" values should be "[home work],[work],[work],[home]"
+
+  @tags @merge
+  Scenario: A tag cannot be merged into itself
+    When I merge the tag "money" into "money"
+    Then the response status code should be 400
+    And the response "error" should be "tag_merge_self"
+
+  # ============================================================================
+  # Deleting
+  # ============================================================================
+
+  @tags @delete
+  Scenario: Deleting a tag detaches it from its todos
+    When I delete the tag "home"
+    Then the response status code should be 204
+    When I list my tags
+    Then the listed tags should be "money:2,work:2"
+    When I list my todos with query "sort=created_at&order=asc"
+    Then the listed todo "tags" values should be "[money],[work],[money work],[]"
+
+  # ============================================================================
+  # Ownership
+  # ============================================================================
+
+  @tags @security
+  Scenario: Users cannot see each other's tags
+    When I create a tag with:
+      """
+      {"name": "private"}
+      """
+    Then the response status code should be 201
+    Given a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I get the last created tag
+    Then the response status code should be 404
+    And the response "error" should be "tag_not_found"
+    When I list my tags
+    Then the listed tags should be ""
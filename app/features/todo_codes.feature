@@ -0,0 +1,75 @@
+Feature: Todo Short Codes
+  As a logged in user of the todolist application
+  I want every todo to have a short code
+  So that I can refer to my todos without quoting their IDs
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+    And I am logged in as "owner@example.com" with password "correct-horse-battery"
+
+  # ============================================================================
+  # Allocation
+  # ============================================================================
+
+  @todos @codes @happy-path
+  Scenario: Todos are numbered in the order they are created
+    Given a todo exists with title "First"
+    When I create a todo with title "Second"
+    Then the response status code should be 201
+    And the todo code should be number 2 of the current year
+
+  @todos @codes @isolation
+  Scenario: Each user has their own sequence
+    Given a todo exists with title "Mine"
+    And a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I create a todo with title "Theirs"
+    Then the response status code should be 201
+    And the todo code should be number 1 of the current year
+
+  @todos @codes @concurrency
+  Scenario: Parallel creates never share a code
+    When I create 25 todos in parallel
+    Then the todo codes should be numbered 1 to 25 for the current year
+
+  @todos @codes
+  Scenario: Codes of deleted todos are not handed out again
+    Given a todo exists with title "Short-lived"
+    And I delete the todo
+    When I create a todo with title "Next"
+    Then the todo code should be number 2 of the current year
+
+  # ============================================================================
+  # Lookup
+  # ============================================================================
+
+  @todos @codes @happy-path
+  Scenario: Get a todo by its code
+    Given a todo exists with title "Find me"
+    When I get the todo by its code
+    Then the response status code should be 200
+    And the response "title" should be "Find me"
+
+  @todos @codes
+  Scenario: Delete a todo by its code
+    Given a todo exists with title "Remove me"
+    When I delete the todo by its code
+    Then the response status code should be 204
+    When I get the todo
+    Then the response status code should be 404
+
+  @todos @codes @error
+  Scenario: Get a todo with an unknown code
+    When I get the todo with id "26-9999"
+    Then the response status code should be 404
+    And the response "error" should be "todo_not_found"
+
+  @todos @codes @isolation
+  Scenario: Codes are scoped to their owner
+    Given a todo exists with title "Private"
+    And a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I get the todo by its code
+    Then the response status code should be 404
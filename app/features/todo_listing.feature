@@ -0,0 +1,165 @@
+Feature: Todo Listing
+  As a logged in user with many todos
+  I want to filter, sort and page through my todos
+  So that I can find the ones I need without reading them all
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+    And I am logged in as "owner@example.com" with password "correct-horse-battery"
+    And the following todos exist:
+      | title    | status      | priority | due_date             | tags       | description          |
+      | Taxes    | pending     | high     | 2020-04-15T00:00:00Z | home,money | File the tax return  |
+      | Report   | in_progress | medium   | 2099-01-10T00:00:00Z | work       | Quarterly numbers    |
+      | Dentist  | completed   | low      | 2020-02-01T00:00:00Z | health     |                      |
+      | Budget   | pending     | high     | 2099-03-01T00:00:00Z | money,work | Plan the spending    |
+      | Groceries| blocked     | low      |                      | home       | Milk and bread       |
+
+  # ============================================================================
+  # Filters
+  # ============================================================================
+
+  @todos @listing @filter
+  Scenario: Todos are listed newest first by default
+    When I list my todos
+    Then the response status code should be 200
+    And the listed todos should be "Groceries,Budget,Dentist,Report,Taxes"
+    And the response "total" should be 5
+    And the response should not contain "next_cursor"
+
+  @todos @listing @filter
+  Scenario: Filter by several statuses
+    When I list my todos with query "status=pending,blocked"
+    Then the response status code should be 200
+    And the listed todos should be "Groceries,Budget,Taxes"
+    And the response "total" should be 3
+
+  @todos @listing @filter
+  Scenario: Filter by priority
+    When I list my todos with query "priority=low"
+    Then the listed todos should be "Groceries,Dentist"
+
+  @todos @listing @filter
+  Scenario: Filter by any of several tags
+    When I list my todos with query "tag=home,work"
+    Then the listed todos should be "Groceries,Budget,Report,Taxes"
+
+  @todos @listing @filter
+  Scenario: Filter by all of several tags
+    When I list my todos with query "tag=money,work&tag_match=all"
+    Then the listed todos should be "Budget"
+
+  @todos @listing @filter
+  Scenario: Filter by due date range
+    When I list my todos with query "due_after=2020-03-01&due_before=2099-02-01"
+    Then the listed todos should be "Report,Taxes"
+
+  @todos @listing @filter
+  Scenario: Overdue todos exclude closed and undated todos
+    When I list my todos with query "overdue=true"
+    Then the listed todos should be "Taxes"
+
+  @todos @listing @filter
+  Scenario: Filter by creation date
+    When I list my todos with query "created_before=2020-01-01T00:00:00Z"
+    Then the response status code should be 200
+    And the response should contain 0 todos
+    When I list my todos with query "created_after=2020-01-01T00:00:00Z"
+    Then the response should contain 5 todos
+
+  @todos @listing @filter
+  Scenario: Free text matches the title or description ignoring case
+    When I list my todos with query "q=BREAD"
+    Then the listed todos should be "Groceries"
+    When I list my todos with query "q=the"
+    Then the listed todos should be "Budget,Taxes"
+
+  # ============================================================================
+  # Sorting
+  # ============================================================================
+
+  @todos @listing @sort
+  Scenario: Sort by priority puts high priority first
+    When I list my todos with query "sort=priority"
+    Then the listed todo "priority" values should be "high,high,medium,low,low"
+    When I list my todos with query "sort=priority&order=asc"
+    Then the listed todo "priority" values should be "low,low,medium,high,high"
+
+  @todos @listing @sort
+  Scenario: Sort by due date lists undated todos last
+    When I list my todos with query "sort=due_date"
+    Then the listed todos should be "Dentist,Taxes,Report,Budget,Groceries"
+    When I list my todos with query "sort=due_date&order=desc"
+    Then the listed todos should be "Budget,Report,Taxes,Dentist,Groceries"
+
+  @todos @listing @sort
+  Scenario: Sort by last update
+    Given I update the todo "Taxes" with:
+      """
+      {"title": "Taxes 2020"}
+      """
+    When I list my todos with query "sort=updated_at"
+    Then the listed todos should be "Taxes 2020,Groceries,Budget,Dentist,Report"
+
+  # ============================================================================
+  # Pagination
+  # ============================================================================
+
+  @todos @listing @pagination
+  Scenario: Page through todos with cursors
+    When I list my todos with query "sort=due_date&limit=2"
+    Then the response status code should be 200
+    And the listed todos should be "Dentist,Taxes"
+    And the response "total" should be 5
+    And the response should contain "next_cursor"
+    When I list the next page of my todos
+    Then the listed todos should be "Report,Budget"
+    When I list the next page of my todos
+    Then the listed todos should be "Groceries"
+    And the response should not contain "next_cursor"
+
+  @todos @listing @pagination
+  Scenario: Page through todos sorted by priority
+    When I list my todos with query "sort=priority&limit=3"
+    Then the listed todo "priority" values should be "high,high,medium"
+    When I list the next page of my todos
+    Then the listed todo "priority" values should be "low,low"
+    And the response should not contain "next_cursor"
+
+  @todos @listing @pagination @error
+  Scenario: A cursor cannot be used with a different sort order
+    Given I list my todos with query "limit=2"
+    And I set the list query to "sort=priority&limit=2"
+    When I list the next page of my todos
+    Then the response status code should be 400
+    And the response "error" should be "invalid_cursor"
+
+  @todos @listing @pagination @error
+  Scenario: A malformed cursor is rejected
+    When I list my todos with query "cursor=not-a-cursor"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_cursor"
+
+  # ============================================================================
+  # Validation
+  # ============================================================================
+
+  @todos @listing @validation
+  Scenario: Unknown sort fields are rejected
+    When I list my todos with query "sort=title"
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+    And the field "sort" should fail the "oneof" rule
+
+  @todos @listing @validation
+  Scenario: Malformed dates are rejected
+    When I list my todos with query "due_before=next-week"
+    Then the response status code should be 400
+    And the field "due_before" should fail the "datetime" rule
+
+  @todos @listing @validation
+  Scenario: Unknown statuses are rejected
+    When I list my todos with query "status=pending,archived"
+    Then the response status code should be 400
+    And the response "error" should be "invalid_status"
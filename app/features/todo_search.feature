@@ -0,0 +1,108 @@
+Feature: Todo Search
+  As a logged in user of the todolist application
+  I want to search the text of my todos
+  So that I can find a todo without remembering its exact wording
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+    And I am logged in as "owner@example.com" with password "correct-horse-battery"
+    And the following todos exist:
+      | title            | tags       | description                            |
+      | Pay taxes        | money,home | File the tax return before April       |
+      | Quarterly report | work       | Quarterly numbers for the budget board |
+      | Taxi to airport  | travel     | Book a taxi for the conference         |
+      | Đi chợ Hà Nội    | home       | Mua rau ở chợ Đồng Xuân                |
+      | Plan budget      | money      | Review the spending                    |
+
+  # ============================================================================
+  # Matching
+  # ============================================================================
+
+  @todos @search @happy-path
+  Scenario: English words match their other forms
+    When I search my todos for "tax"
+    Then the response status code should be 200
+    And the search results should be "Pay taxes"
+    And the first search result "title" highlight should be "Pay <mark>taxes</mark>"
+
+  @todos @search
+  Scenario: Matches in the title rank above matches in the description
+    When I search my todos for "budget"
+    Then the search results should be "Plan budget,Quarterly report"
+
+  @todos @search
+  Scenario: Tags are searched
+    When I search my todos for "travel"
+    Then the search results should be "Taxi to airport"
+
+  @todos @search
+  Scenario: Every word must match
+    When I search my todos for "taxes airport"
+    Then the response status code should be 200
+    And the response should contain 0 search results
+
+  @todos @search @phrase
+  Scenario: Quoted words must appear together and in order
+    When I search my todos with query "q=%22quarterly+numbers%22"
+    Then the search results should be "Quarterly report"
+    When I search my todos with query "q=%22numbers+quarterly%22"
+    Then the response should contain 0 search results
+
+  @todos @search @prefix
+  Scenario: A trailing star matches words starting with the prefix
+    When I search my todos for "quart*"
+    Then the search results should be "Quarterly report"
+    When I search my todos for "tax*"
+    Then the response should contain 2 search results
+
+  @todos @search @highlight
+  Scenario: Matches in the description are highlighted
+    When I search my todos for "april"
+    Then the first search result "description" highlight should be "File the tax return before <mark>April</mark>"
+
+  @todos @search @highlight
+  Scenario: Highlights escape the todo text
+    Given a todo exists with title "<img src=x onerror=alert(1)> & invoice"
+    When I search my todos for "invoice"
+    Then the first search result "title" highlight should be "&lt;img src=x onerror=alert(1)&gt; &amp; <mark>invoice</mark>"
+
+  @todos @search @language
+  Scenario: Vietnamese search ignores diacritics
+    When I search my todos with query "q=cho+ha+noi&lang=vi"
+    Then the search results should be "Đi chợ Hà Nội"
+    And the first search result "title" highlight should be "Đi <mark>chợ</mark> <mark>Hà</mark> <mark>Nội</mark>"
+    When I search my todos for "cho ha noi"
+    Then the response should contain 0 search results
+
+  @todos @search @isolation
+  Scenario: Search is scoped to the todo owner
+    Given a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I search my todos for "tax"
+    Then the response status code should be 200
+    And the response should contain 0 search results
+
+  # ============================================================================
+  # Validation
+  # ============================================================================
+
+  @todos @search @validation
+  Scenario: A search query is required
+    When I search my todos with query "lang=en"
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+    And the field "q" should fail the "required" rule
+
+  @todos @search @validation
+  Scenario: A query without words is rejected
+    When I search my todos for "* *"
+    Then the response status code should be 400
+    And the response "error" should be "search_query_required"
+
+  @todos @search @validation
+  Scenario: Unknown languages are rejected
+    When I search my todos with query "q=tax&lang=fr"
+    Then the response status code should be 400
+    And the field "lang" should fail the "oneof" rule
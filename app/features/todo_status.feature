@@ -0,0 +1,141 @@
+Feature: Todo Status Workflow
+  As a logged in user of the todolist application
+  I want my todos to move through a fixed set of statuses
+  So that their progress and history stay consistent
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+    And I am logged in as "owner@example.com" with password "correct-horse-battery"
+    And a todo exists with title "Ship release"
+
+  # ============================================================================
+  # Transitions
+  # ============================================================================
+
+  @todos @status @happy-path
+  Scenario: Completing a todo records when it was completed
+    When I update the todo with:
+      """
+      {"status": "completed"}
+      """
+    Then the response status code should be 200
+    And the response "status" should be "completed"
+    And the response should contain "completed_at"
+
+  @todos @status @happy-path
+  Scenario: A todo can be blocked and unblocked
+    When I update the todo with:
+      """
+      {"status": "blocked"}
+      """
+    Then the response status code should be 200
+    And the response "status" should be "blocked"
+    When I update the todo with:
+      """
+      {"status": "in_progress"}
+      """
+    Then the response status code should be 200
+    And the response "status" should be "in_progress"
+
+  @todos @status @error
+  Scenario: A blocked todo cannot be completed
+    Given I update the todo with:
+      """
+      {"status": "blocked"}
+      """
+    When I update the todo with:
+      """
+      {"status": "completed"}
+      """
+    Then the response status code should be 409
+    And the response "error" should be "invalid_status_transition"
+    And the response detail "from" should be "blocked"
+    And the response detail "to" should be "completed"
+
+  @todos @status @error
+  Scenario: A completed todo cannot be moved back through an update
+    Given I update the todo with:
+      """
+      {"status": "completed"}
+      """
+    When I update the todo with:
+      """
+      {"status": "pending"}
+      """
+    Then the response status code should be 409
+    And the response "error" should be "invalid_status_transition"
+    When I get the todo
+    Then the response "status" should be "completed"
+
+  @todos @status @error
+  Scenario: A cancelled todo cannot be started
+    Given I update the todo with:
+      """
+      {"status": "cancelled"}
+      """
+    When I update the todo with:
+      """
+      {"status": "in_progress"}
+      """
+    Then the response status code should be 409
+    And the response "error" should be "invalid_status_transition"
+
+  # ============================================================================
+  # Reopen
+  # ============================================================================
+
+  @todos @status @reopen @happy-path
+  Scenario: Reopen a completed todo
+    Given I update the todo with:
+      """
+      {"status": "completed"}
+      """
+    When I reopen the todo
+    Then the response status code should be 200
+    And the response "status" should be "pending"
+    And the response should not contain "completed_at"
+
+  @todos @status @reopen @error
+  Scenario: Only closed todos can be reopened
+    When I reopen the todo
+    Then the response status code should be 409
+    And the response "error" should be "todo_not_closed"
+
+  # ============================================================================
+  # History
+  # ============================================================================
+
+  @todos @status @history @happy-path
+  Scenario: The history lists every status change in order
+    Given I update the todo with:
+      """
+      {"status": "in_progress"}
+      """
+    And I update the todo with:
+      """
+      {"status": "completed"}
+      """
+    And I reopen the todo
+    When I get the todo history
+    Then the response status code should be 200
+    And the todo history should be "pending,in_progress,completed,pending"
+
+  @todos @status @history
+  Scenario: Updates that keep the status add no history
+    Given I update the todo with:
+      """
+      {"title": "Ship the release", "status": "pending"}
+      """
+    When I get the todo history
+    Then the response status code should be 200
+    And the todo history should be "pending"
+
+  @todos @status @history @isolation
+  Scenario: The history is scoped to the todo owner
+    Given a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I get the todo history
+    Then the response status code should be 404
+    And the response "error" should be "todo_not_found"
@@ -0,0 +1,162 @@
+Feature: Todo Management
+  As a logged in user of the todolist application
+  I want to create, view, update and delete my todos
+  So that I can keep track of my tasks
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+    And I am logged in as "owner@example.com" with password "correct-horse-battery"
+
+  # ============================================================================
+  # Create
+  # ============================================================================
+
+  @todos @create @happy-path
+  Scenario: Create a todo with only a title
+    When I create a todo with title "Buy groceries"
+    Then the response status code should be 201
+    And the response should contain "id"
+    And the response "title" should be "Buy groceries"
+    And the response "status" should be "pending"
+    And the response "priority" should be "medium"
+
+  @todos @create @happy-path
+  Scenario: Create a todo with all fields
+    When I create a todo with:
+      """
+      {
+        "title": "Write report",
+        "description": "Quarterly numbers",
+        "priority": "high",
+        "due_date": "2030-01-15T09:00:00Z",
+        "tags": ["work", " urgent ", "work", ""]
+      }
+      """
+    Then the response status code should be 201
+    And the response "description" should be "Quarterly numbers"
+    And the response "priority" should be "high"
+    And the response "due_date" should be "2030-01-15T09:00:00Z"
+    And the response "tags" list should be "work,urgent"
+
+  @todos @create @validation
+  Scenario: Create fails without a title
+    When I create a todo with title "   "
+    Then the response status code should be 400
+    And the response "error" should be "title_required"
+
+  @todos @create @validation
+  Scenario: Create fails with an unknown priority
+    When I create a todo with:
+      """
+      {"title": "Something", "priority": "urgent"}
+      """
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+    And the field "priority" should fail the "oneof" rule
+
+  @todos @create @unauthorized
+  Scenario: Todos require authentication
+    When I list my todos without authentication
+    Then the response status code should be 401
+
+  # ============================================================================
+  # Read
+  # ============================================================================
+
+  @todos @list
+  Scenario: List my todos
+    Given a todo exists with title "First"
+    And a todo exists with title "Second"
+    When I list my todos
+    Then the response status code should be 200
+    And the response should contain 2 todos
+    And the response "total" should be 2
+
+  @todos @get
+  Scenario: Get a todo by ID
+    Given a todo exists with title "Read a book"
+    When I get the todo
+    Then the response status code should be 200
+    And the response "title" should be "Read a book"
+
+  @todos @get
+  Scenario: Get a todo that does not exist
+    When I get the todo with id "5b0f8a3e-1c2d-4e5f-8a9b-0c1d2e3f4a5b"
+    Then the response status code should be 404
+    And the response "error" should be "todo_not_found"
+
+  @todos @isolation
+  Scenario: Todos are scoped to their owner
+    Given a todo exists with title "Private"
+    And a user exists with email "other@example.com" and password "correct-horse-battery"
+    And I am logged in as "other@example.com" with password "correct-horse-battery"
+    When I get the todo
+    Then the response status code should be 404
+    When I list my todos
+    Then the response should contain 0 todos
+
+  # ============================================================================
+  # Update
+  # ============================================================================
+
+  @todos @update
+  Scenario: Update a todo
+    Given a todo exists with title "Draft"
+    When I update the todo with:
+      """
+      {"title": "Final", "status": "in_progress", "tags": ["home"]}
+      """
+    Then the response status code should be 200
+    And the response "title" should be "Final"
+    And the response "status" should be "in_progress"
+    And the response "priority" should be "medium"
+    And the response "tags" list should be "home"
+
+  @todos @update
+  Scenario: Update clears the description and due date with null
+    Given a todo exists with title "Draft"
+    When I update the todo with:
+      """
+      {"description": "Some notes", "due_date": "2030-01-15T09:00:00Z"}
+      """
+    Then the response status code should be 200
+    And the response should contain "description"
+    And the response should contain "due_date"
+    When I update the todo with:
+      """
+      {"title": "Final"}
+      """
+    Then the response "description" should be "Some notes"
+    When I update the todo with:
+      """
+      {"description": null, "due_date": null}
+      """
+    Then the response status code should be 200
+    And the response "title" should be "Final"
+    And the response should not contain "description"
+    And the response should not contain "due_date"
+
+  @todos @update @validation
+  Scenario: Update fails with an unknown status
+    Given a todo exists with title "Draft"
+    When I update the todo with:
+      """
+      {"status": "archived"}
+      """
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+    And the field "status" should fail the "oneof" rule
+
+  # ============================================================================
+  # Delete
+  # ============================================================================
+
+  @todos @delete
+  Scenario: Delete a todo
+    Given a todo exists with title "Obsolete"
+    When I delete the todo
+    Then the response status code should be 204
+    When I get the todo
+    Then the response status code should be 404
@@ -0,0 +1,81 @@
+Feature: Request Validation
+  As a developer integrating with the todolist API
+  I want malformed requests rejected with the fields at fault
+  So that I can fix my client without guessing
+
+  Background:
+    Given the API server is running
+    And the database is clean
+    And a user exists with email "owner@example.com" and password "correct-horse-battery"
+
+  # ============================================================================
+  # Field Rules
+  # ============================================================================
+
+  @validation
+  Scenario: Every failing field is reported
+    When I login with email "not-an-email" and password ""
+    Then the response status code should be 400
+    And the response "error" should be "validation_error"
+    And the response "message" should be "email must be a valid email address; password is required"
+    And the field "email" should fail the "email" rule
+    And the field "password" should fail the "required" rule
+
+  @validation
+  Scenario: Email addresses are checked like the accounts that use them
+    When I register with email "  New.User@Example.COM " and password "correct-horse-battery"
+    Then the response status code should be 201
+
+  @validation
+  Scenario: Unknown fields are rejected
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I create a todo with:
+      """
+      {"title": "Some title", "priority": "low", "status": "pending"}
+      """
+    Then the response status code should be 400
+    And the response "error" should be "invalid_request"
+    And the response "message" should be "Unknown field: status"
+
+  @validation
+  Scenario: Personal access token names have a maximum length
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I create a personal access token "a-very-long-name-for-a-token-that-keeps-going-and-going-well-beyond-what-anyone-would-type-in-a-form-field" with scopes "todos:read"
+    Then the response status code should be 400
+    And the field "name" should fail the "max" rule
+
+  @validation
+  Scenario: Personal access token expiry cannot be negative
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I create a personal access token "script" with scopes "todos:read" expiring in -1 days
+    Then the response status code should be 400
+    And the field "expires_in_days" should fail the "min" rule
+
+  @validation
+  Scenario: Query parameters are validated too
+    Given an admin exists with email "admin@example.com" and password "correct-horse-battery"
+    And I am logged in as "admin@example.com" with password "correct-horse-battery"
+    When I list the users with query "limit=-1"
+    Then the response status code should be 400
+    And the field "limit" should fail the "min" rule
+
+  # ============================================================================
+  # Malformed Bodies
+  # ============================================================================
+
+  @validation
+  Scenario: Malformed JSON is rejected
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I create a todo with:
+      """
+      {"title": "Unterminated
+      """
+    Then the response status code should be 400
+    And the response "error" should be "invalid_request"
+
+  @validation
+  Scenario: Oversized bodies are rejected
+    Given I am logged in as "owner@example.com" with password "correct-horse-battery"
+    When I create a todo with a description of 2000000 characters
+    Then the response status code should be 413
+    And the response "error" should be "request_entity_too_large"
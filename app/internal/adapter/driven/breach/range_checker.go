@@ -0,0 +1,95 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// prefixLength is the number of SHA-1 hex characters that select a range
+const prefixLength = 5
+
+// RangeSource provides the hash suffixes that share a SHA-1 prefix.
+// Each line has the form SUFFIX:COUNT, as in the Have I Been Pwned range API.
+type RangeSource interface {
+	Range(ctx context.Context, prefix string) (io.ReadCloser, error)
+}
+
+// RangeChecker implements the BreachedPasswordChecker interface with the
+// k-anonymity range model: only the first five characters of the password's
+// SHA-1 hash select a range, and the rest is matched locally
+type RangeChecker struct {
+	source   RangeSource
+	minCount int
+}
+
+// NewRangeChecker creates a checker that treats a password as breached once
+// it was seen at least minCount times
+func NewRangeChecker(source RangeSource, minCount int) *RangeChecker {
+	if minCount < 1 {
+		minCount = 1
+	}
+	return &RangeChecker{source: source, minCount: minCount}
+}
+
+// IsBreached reports whether the password appears in the breach corpus
+func (c *RangeChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	rc, err := c.source.Range(ctx, prefix)
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		candidate, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		seen, err := strconv.Atoi(count)
+		if err != nil {
+			// Ranges without counts list breached suffixes only
+			seen = 1
+		}
+		return seen >= c.minCount, nil
+	}
+
+	return false, scanner.Err()
+}
+
+// DirSource serves ranges from a directory holding one file per prefix
+// (for example 21BD1 or 21BD1.txt), as written by the HIBP downloader
+type DirSource struct {
+	dir string
+}
+
+// NewDirSource creates a range source reading from dir
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{dir: dir}
+}
+
+// Range opens the file for prefix. A missing file is an empty range.
+func (s *DirSource) Range(ctx context.Context, prefix string) (io.ReadCloser, error) {
+	for _, name := range []string{prefix, prefix + ".txt"} {
+		f, err := os.Open(filepath.Join(s.dir, name))
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// LogMailer implements the Mailer interface by writing messages to a writer
+// such as a file or stdout. It is intended for local development and testing.
+type LogMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewLogMailer creates a new mailer that writes messages to w
+func NewLogMailer(w io.Writer, from string) *LogMailer {
+	return &LogMailer{w: w, from: from}
+}
+
+// Send writes the message to the underlying writer
+func (m *LogMailer) Send(ctx context.Context, msg output.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w, "=== Email %s ===\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n=== End of email ===\n",
+		time.Now().Format(time.RFC3339), m.from, msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// minPruneSize is the number of counters from which Increment starts dropping
+// expired ones itself rather than leaving them to PurgeExpired
+const minPruneSize = 1024
+
+// AttemptStore is an in-memory implementation of the AttemptStore interface.
+// Counters are not shared between instances, so it suits single-instance deployments and tests.
+type AttemptStore struct {
+	mu       sync.Mutex
+	counters map[string]entity.AttemptCounter
+	// pruneSize is the number of counters at which Increment next prunes
+	pruneSize int
+}
+
+// NewAttemptStore creates a new in-memory attempt store
+func NewAttemptStore() *AttemptStore {
+	return &AttemptStore{
+		counters:  make(map[string]entity.AttemptCounter),
+		pruneSize: minPruneSize,
+	}
+}
+
+// Get returns the counter for key, with a zero count if none is live
+func (s *AttemptStore) Get(ctx context.Context, key string) (*entity.AttemptCounter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, ok := s.counters[key]
+	if !ok || counter.IsExpired() {
+		return &entity.AttemptCounter{Key: key}, nil
+	}
+	return &counter, nil
+}
+
+// Increment records an attempt for key and returns the updated counter.
+// An expired counter starts a new window of ttl.
+func (s *AttemptStore) Increment(ctx context.Context, key string, ttl time.Duration) (*entity.AttemptCounter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	counter, ok := s.counters[key]
+	if !ok || counter.IsExpired() {
+		counter = entity.AttemptCounter{Key: key, ExpiresAt: now.Add(ttl)}
+	}
+
+	counter.Count++
+	counter.LastAttemptAt = now
+	s.counters[key] = counter
+
+	s.pruneExpired()
+
+	return &counter, nil
+}
+
+// Reset forgets the counter for key
+func (s *AttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counters, key)
+	return nil
+}
+
+// PurgeExpired deletes the counters whose window has ended
+func (s *AttemptStore) PurgeExpired(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for key, counter := range s.counters {
+		if counter.IsExpired() {
+			delete(s.counters, key)
+			purged++
+		}
+	}
+	s.pruneSize = max(2*len(s.counters), minPruneSize)
+	return purged, nil
+}
+
+// Clear forgets every counter
+func (s *AttemptStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters = make(map[string]entity.AttemptCounter)
+	s.pruneSize = minPruneSize
+}
+
+// pruneExpired drops forgotten counters once the map has grown, so that
+// requests from many distinct clients do not accumulate. The next prune waits
+// until the map has doubled from what was left, which keeps the scans to a
+// constant cost per Increment however many counters stay live.
+func (s *AttemptStore) pruneExpired() {
+	if len(s.counters) < s.pruneSize {
+		return
+	}
+	for key, counter := range s.counters {
+		if counter.IsExpired() {
+			delete(s.counters, key)
+		}
+	}
+	s.pruneSize = max(2*len(s.counters), minPruneSize)
+}
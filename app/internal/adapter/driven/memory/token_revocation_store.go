@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenRevocationStore is an in-memory implementation of the TokenRevocationStore interface.
+// It is intended for tests and single-instance development setups.
+type TokenRevocationStore struct {
+	mu            sync.RWMutex
+	revokedTokens map[string]time.Time // token ID -> expiry
+	revokedBefore map[string]time.Time // user ID -> cutoff
+}
+
+// NewTokenRevocationStore creates a new in-memory token revocation store
+func NewTokenRevocationStore() *TokenRevocationStore {
+	return &TokenRevocationStore{
+		revokedTokens: make(map[string]time.Time),
+		revokedBefore: make(map[string]time.Time),
+	}
+}
+
+// RevokeToken revokes a single access token by its ID
+func (s *TokenRevocationStore) RevokeToken(ctx context.Context, tokenID, userID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revokedTokens[tokenID] = expiresAt
+	return nil
+}
+
+// RevokeAllForUser revokes every access token issued to the user before revokedAt
+func (s *TokenRevocationStore) RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.revokedBefore[userID]; !ok || revokedAt.After(existing) {
+		s.revokedBefore[userID] = revokedAt
+	}
+	return nil
+}
+
+// IsRevoked reports whether a token with the given ID, owner and issue time is revoked
+func (s *TokenRevocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.revokedTokens[tokenID]; ok {
+		return true, nil
+	}
+	if cutoff, ok := s.revokedBefore[userID]; ok && issuedAt.Before(cutoff) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// PurgeExpired deletes the revocations of single tokens that have expired anyway
+func (s *TokenRevocationStore) PurgeExpired(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	purged := 0
+	for tokenID, expiresAt := range s.revokedTokens {
+		if expiresAt.Before(now) {
+			delete(s.revokedTokens, tokenID)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// Clear removes every revocation entry
+func (s *TokenRevocationStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revokedTokens = make(map[string]time.Time)
+	s.revokedBefore = make(map[string]time.Time)
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// AttemptStore implements the AttemptStore interface using PostgreSQL
+type AttemptStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewAttemptStore creates a new PostgreSQL attempt store
+func NewAttemptStore(pool *pgxpool.Pool) *AttemptStore {
+	return &AttemptStore{pool: pool}
+}
+
+// Get returns the counter for key, with a zero count if none is live
+func (s *AttemptStore) Get(ctx context.Context, key string) (*entity.AttemptCounter, error) {
+	query := `
+		SELECT key, count, last_attempt_at, expires_at
+		FROM auth_attempts
+		WHERE key = $1 AND expires_at > NOW()
+	`
+
+	counter := &entity.AttemptCounter{}
+	err := s.pool.QueryRow(ctx, query, key).Scan(
+		&counter.Key,
+		&counter.Count,
+		&counter.LastAttemptAt,
+		&counter.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &entity.AttemptCounter{Key: key}, nil
+		}
+		return nil, err
+	}
+
+	return counter, nil
+}
+
+// Increment records an attempt for key and returns the updated counter.
+// An expired counter restarts at one with a new window of ttl.
+func (s *AttemptStore) Increment(ctx context.Context, key string, ttl time.Duration) (*entity.AttemptCounter, error) {
+	query := `
+		INSERT INTO auth_attempts (key, count, last_attempt_at, expires_at)
+		VALUES ($1, 1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET
+			count = CASE
+				WHEN auth_attempts.expires_at <= EXCLUDED.last_attempt_at THEN 1
+				ELSE auth_attempts.count + 1
+			END,
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			expires_at = CASE
+				WHEN auth_attempts.expires_at <= EXCLUDED.last_attempt_at THEN EXCLUDED.expires_at
+				ELSE auth_attempts.expires_at
+			END
+		RETURNING key, count, last_attempt_at, expires_at
+	`
+
+	now := time.Now()
+	counter := &entity.AttemptCounter{}
+	err := s.pool.QueryRow(ctx, query, key, now, now.Add(ttl)).Scan(
+		&counter.Key,
+		&counter.Count,
+		&counter.LastAttemptAt,
+		&counter.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return counter, nil
+}
+
+// Reset forgets the counter for key
+func (s *AttemptStore) Reset(ctx context.Context, key string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM auth_attempts WHERE key = $1`, key)
+	return err
+}
+
+// PurgeExpired deletes the counters whose window has ended
+func (s *AttemptStore) PurgeExpired(ctx context.Context) (int, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM auth_attempts WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// UserIdentityRepository implements the UserIdentityRepository interface using PostgreSQL
+type UserIdentityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewUserIdentityRepository creates a new PostgreSQL user identity repository
+func NewUserIdentityRepository(pool *pgxpool.Pool) *UserIdentityRepository {
+	return &UserIdentityRepository{pool: pool}
+}
+
+// Create stores a new identity link
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	)
+
+	if isUniqueViolation(err) {
+		if containsString(err.Error(), "user_identities_user_provider_key") {
+			return entity.ErrProviderAlreadyLinked
+		}
+		return entity.ErrIdentityAlreadyLinked
+	}
+
+	return err
+}
+
+// GetByProviderSubject retrieves the identity for an external subject
+func (r *UserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	identity := &entity.UserIdentity{}
+	err := r.pool.QueryRow(ctx, query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// ListByUserID returns every identity linked to the user
+func (r *UserIdentityRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := make([]*entity.UserIdentity, 0)
+	for rows.Next() {
+		identity := &entity.UserIdentity{}
+		if err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+// Delete removes the user's identity at the provider
+func (r *UserIdentityRepository) Delete(ctx context.Context, userID, provider string) error {
+	query := `DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`
+
+	result, err := r.pool.Exec(ctx, query, userID, provider)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrIdentityNotFound
+	}
+
+	return nil
+}
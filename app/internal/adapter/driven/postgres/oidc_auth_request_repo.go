@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// OIDCAuthRequestRepository implements the OIDCAuthRequestRepository interface using PostgreSQL
+type OIDCAuthRequestRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOIDCAuthRequestRepository creates a new PostgreSQL OIDC authorization request repository
+func NewOIDCAuthRequestRepository(pool *pgxpool.Pool) *OIDCAuthRequestRepository {
+	return &OIDCAuthRequestRepository{pool: pool}
+}
+
+// Create stores a new authorization request
+func (r *OIDCAuthRequestRepository) Create(ctx context.Context, request *entity.OIDCAuthRequest) error {
+	query := `
+		INSERT INTO oidc_auth_requests (id, provider, state_hash, nonce, code_verifier, link_user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		request.ID,
+		request.Provider,
+		request.StateHash,
+		request.Nonce,
+		request.CodeVerifier,
+		request.LinkUserID,
+		request.ExpiresAt,
+		request.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByStateHash retrieves a request by the hash of its state parameter
+func (r *OIDCAuthRequestRepository) GetByStateHash(ctx context.Context, stateHash string) (*entity.OIDCAuthRequest, error) {
+	query := `
+		SELECT id, provider, state_hash, nonce, code_verifier, link_user_id, expires_at, created_at, used_at
+		FROM oidc_auth_requests
+		WHERE state_hash = $1
+	`
+
+	request := &entity.OIDCAuthRequest{}
+	err := r.pool.QueryRow(ctx, query, stateHash).Scan(
+		&request.ID,
+		&request.Provider,
+		&request.StateHash,
+		&request.Nonce,
+		&request.CodeVerifier,
+		&request.LinkUserID,
+		&request.ExpiresAt,
+		&request.CreatedAt,
+		&request.UsedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrOIDCAuthRequestNotFound
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// MarkUsed atomically marks a request as completed
+func (r *OIDCAuthRequestRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE oidc_auth_requests
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrInvalidOIDCState
+	}
+
+	return nil
+}
+
+// PurgeExpired deletes the requests that have expired
+func (r *OIDCAuthRequestRepository) PurgeExpired(ctx context.Context) (int, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM oidc_auth_requests WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// OneTimeTokenRepository implements the OneTimeTokenRepository interface using PostgreSQL
+type OneTimeTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOneTimeTokenRepository creates a new PostgreSQL one-time token repository
+func NewOneTimeTokenRepository(pool *pgxpool.Pool) *OneTimeTokenRepository {
+	return &OneTimeTokenRepository{pool: pool}
+}
+
+// Create stores a new one-time token
+func (r *OneTimeTokenRepository) Create(ctx context.Context, token *entity.OneTimeToken) error {
+	query := `
+		INSERT INTO one_time_tokens (id, user_id, purpose, email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Purpose,
+		token.Email,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByHash retrieves a token with the given purpose by the hash of its value
+func (r *OneTimeTokenRepository) GetByHash(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (*entity.OneTimeToken, error) {
+	query := `
+		SELECT id, user_id, purpose, email, token_hash, expires_at, created_at, used_at
+		FROM one_time_tokens
+		WHERE purpose = $1 AND token_hash = $2
+	`
+
+	token := &entity.OneTimeToken{}
+	err := r.pool.QueryRow(ctx, query, purpose, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.Email,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrOneTimeTokenNotFound
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// MarkUsed atomically marks a token as consumed
+func (r *OneTimeTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE one_time_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrOneTimeTokenUsed
+	}
+
+	return nil
+}
+
+// DeleteForUser deletes every token with the given purpose belonging to the user
+func (r *OneTimeTokenRepository) DeleteForUser(ctx context.Context, userID string, purpose entity.TokenPurpose) error {
+	query := `DELETE FROM one_time_tokens WHERE user_id = $1 AND purpose = $2`
+
+	_, err := r.pool.Exec(ctx, query, userID, purpose)
+	return err
+}
+
+// PurgeExpired deletes the tokens that have expired
+func (r *OneTimeTokenRepository) PurgeExpired(ctx context.Context) (int, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM one_time_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// PersonalAccessTokenRepository implements the PersonalAccessTokenRepository interface using PostgreSQL
+type PersonalAccessTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPersonalAccessTokenRepository creates a new PostgreSQL personal access token repository
+func NewPersonalAccessTokenRepository(pool *pgxpool.Pool) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{pool: pool}
+}
+
+// Create stores a new personal access token
+func (r *PersonalAccessTokenRepository) Create(ctx context.Context, token *entity.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, name, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.TokenHash,
+		token.Scopes,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByHash retrieves a token by the hash of its value
+func (r *PersonalAccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.PersonalAccessToken, error) {
+	query := `
+		SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at, revoked_at
+		FROM personal_access_tokens
+		WHERE token_hash = $1
+	`
+
+	token, err := scanPersonalAccessToken(r.pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrPersonalAccessTokenNotFound
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// ListByUserID returns the user's tokens that have not been revoked
+func (r *PersonalAccessTokenRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.PersonalAccessToken, error) {
+	query := `
+		SELECT id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at, revoked_at
+		FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]*entity.PersonalAccessToken, 0)
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// Revoke revokes one of the user's tokens
+func (r *PersonalAccessTokenRepository) Revoke(ctx context.Context, userID, id string) error {
+	query := `
+		UPDATE personal_access_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every token belonging to the user
+func (r *PersonalAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `
+		UPDATE personal_access_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
+
+// UpdateLastUsed records when a token was last used to authenticate
+func (r *PersonalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	query := `UPDATE personal_access_tokens SET last_used_at = $2 WHERE id = $1`
+
+	_, err := r.pool.Exec(ctx, query, id, usedAt)
+	return err
+}
+
+// scanPersonalAccessToken reads a token from a query row
+func scanPersonalAccessToken(row pgx.Row) (*entity.PersonalAccessToken, error) {
+	token := &entity.PersonalAccessToken{}
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenHash,
+		&token.Scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+		&token.RevokedAt,
+	)
+	return token, err
+}
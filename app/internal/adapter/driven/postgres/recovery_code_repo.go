@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// RecoveryCodeRepository implements the RecoveryCodeRepository interface using PostgreSQL
+type RecoveryCodeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRecoveryCodeRepository creates a new PostgreSQL recovery code repository
+func NewRecoveryCodeRepository(pool *pgxpool.Pool) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{pool: pool}
+}
+
+// ReplaceForUser deletes the user's recovery codes and stores the given ones
+// in a single transaction
+func (r *RecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID string, codes []*entity.RecoveryCode) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO recovery_codes (id, user_id, code_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	for _, code := range codes {
+		if _, err := tx.Exec(ctx, query, code.ID, code.UserID, code.CodeHash, code.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByHash retrieves one of the user's recovery codes by its hash
+func (r *RecoveryCodeRepository) GetByHash(ctx context.Context, userID, codeHash string) (*entity.RecoveryCode, error) {
+	query := `
+		SELECT id, user_id, code_hash, created_at, used_at
+		FROM recovery_codes
+		WHERE user_id = $1 AND code_hash = $2
+	`
+
+	code := &entity.RecoveryCode{}
+	err := r.pool.QueryRow(ctx, query, userID, codeHash).Scan(
+		&code.ID,
+		&code.UserID,
+		&code.CodeHash,
+		&code.CreatedAt,
+		&code.UsedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrRecoveryCodeNotFound
+		}
+		return nil, err
+	}
+
+	return code, nil
+}
+
+// MarkUsed atomically marks a recovery code as consumed
+func (r *RecoveryCodeRepository) MarkUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE recovery_codes
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrRecoveryCodeUsed
+	}
+
+	return nil
+}
+
+// CountUnused returns how many of the user's recovery codes are still usable
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+// DeleteForUser deletes every recovery code of the user
+func (r *RecoveryCodeRepository) DeleteForUser(ctx context.Context, userID string) error {
+	query := `DELETE FROM recovery_codes WHERE user_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// SessionRepository implements the SessionRepository interface using PostgreSQL
+type SessionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewSessionRepository creates a new PostgreSQL session repository
+func NewSessionRepository(pool *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{pool: pool}
+}
+
+// Create stores a new session
+func (r *SessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, device_label, user_agent, ip_address, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		session.ID,
+		session.UserID,
+		session.DeviceLabel,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a session
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*entity.Session, error) {
+	query := `
+		SELECT id, user_id, device_label, user_agent, ip_address, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	session, err := scanSession(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ListActiveByUserID returns the user's unrevoked sessions seen since the given time
+func (r *SessionRepository) ListActiveByUserID(ctx context.Context, userID string, seenSince time.Time) ([]*entity.Session, error) {
+	query := `
+		SELECT id, user_id, device_label, user_agent, ip_address, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND last_seen_at >= $2
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, seenSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*entity.Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Touch records activity on a session
+func (r *SessionRepository) Touch(ctx context.Context, id string, seenAt time.Time, ipAddress string) error {
+	query := `
+		UPDATE sessions
+		SET last_seen_at = GREATEST(last_seen_at, $2),
+			ip_address = COALESCE(NULLIF($3, ''), ip_address)
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, id, seenAt, ipAddress)
+	return err
+}
+
+// Revoke signs out one of the user's sessions
+func (r *SessionRepository) Revoke(ctx context.Context, userID, id string) error {
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllForUser signs out every session of the user
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	query := `
+		UPDATE sessions
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
+
+// PurgeEnded deletes the sessions that were revoked or last seen before seenBefore
+func (r *SessionRepository) PurgeEnded(ctx context.Context, seenBefore time.Time) (int, error) {
+	result, err := r.pool.Exec(ctx, `DELETE FROM sessions WHERE revoked_at IS NOT NULL OR last_seen_at < $1`, seenBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// scanSession reads a session from a query row
+func scanSession(row pgx.Row) (*entity.Session, error) {
+	session := &entity.Session{}
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.DeviceLabel,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.RevokedAt,
+	)
+	return session, err
+}
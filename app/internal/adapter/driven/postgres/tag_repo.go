@@ -0,0 +1,312 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+const tagColumns = `id, user_id, name, color, (SELECT COUNT(*) FROM todo_tags WHERE tag_id = tags.id), created_at, updated_at`
+
+// TagRepository implements the TagRepository interface using PostgreSQL.
+// todo_tags links todos to their tags; todos also keep the tag names in
+// their tags column, which feeds the search vectors, so every change to a
+// tag rewrites that column for the todos labelled with it.
+type TagRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTagRepository creates a new PostgreSQL tag repository
+func NewTagRepository(pool *pgxpool.Pool) *TagRepository {
+	return &TagRepository{pool: pool}
+}
+
+// Create creates a new tag in the database
+func (r *TagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	query := `
+		INSERT INTO tags (id, user_id, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		tag.ID,
+		tag.UserID,
+		tag.Name,
+		tag.Color,
+		tag.CreatedAt,
+		tag.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return entity.ErrTagExists
+	}
+
+	return err
+}
+
+// GetByID retrieves a tag by ID for the given user
+func (r *TagRepository) GetByID(ctx context.Context, userID, id string) (*entity.Tag, error) {
+	if uuid.Validate(id) != nil {
+		return nil, entity.ErrTagNotFound
+	}
+
+	query := `
+		SELECT ` + tagColumns + `
+		FROM tags
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag, err := scanTag(r.pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// List retrieves all tags of the given user, ordered by name
+func (r *TagRepository) List(ctx context.Context, userID string) ([]*entity.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + `
+		FROM tags
+		WHERE user_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]*entity.Tag, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// Update saves the tag's name and color and renames it on its todos
+func (r *TagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	oldName, err := lockTag(ctx, tx, tag.UserID, tag.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE tags SET name = $3, color = $4 WHERE id = $1 AND user_id = $2`
+	if _, err := tx.Exec(ctx, query, tag.ID, tag.UserID, tag.Name, tag.Color); err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrTagExists
+		}
+		return err
+	}
+
+	if oldName != tag.Name {
+		renameQuery := `
+			UPDATE todos SET tags = array_replace(tags, $2, $3)
+			WHERE id IN (SELECT todo_id FROM todo_tags WHERE tag_id = $1)
+		`
+		if _, err := tx.Exec(ctx, renameQuery, tag.ID, oldName, tag.Name); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Merge relabels the todos of the source tag with the target tag and deletes
+// the source tag
+func (r *TagRepository) Merge(ctx context.Context, userID, sourceID, targetID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	names, err := lockTags(ctx, tx, userID, sourceID, targetID)
+	if err != nil {
+		return err
+	}
+	sourceName, targetName := names[0], names[1]
+
+	// Todos that already have the target tag just lose the source tag
+	relabelQuery := `
+		UPDATE todos
+		SET tags = CASE WHEN $3 = ANY(tags) THEN array_remove(tags, $2) ELSE array_replace(tags, $2, $3) END
+		WHERE id IN (SELECT todo_id FROM todo_tags WHERE tag_id = $1)
+	`
+	if _, err := tx.Exec(ctx, relabelQuery, sourceID, sourceName, targetName); err != nil {
+		return err
+	}
+
+	linkQuery := `
+		INSERT INTO todo_tags (todo_id, tag_id)
+		SELECT todo_id, $2 FROM todo_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, linkQuery, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// The source tag's links go with it through ON DELETE CASCADE
+	if _, err := tx.Exec(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2`, sourceID, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete deletes a tag and removes it from its todos
+func (r *TagRepository) Delete(ctx context.Context, userID, id string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	name, err := lockTag(ctx, tx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	detachQuery := `
+		UPDATE todos SET tags = array_remove(tags, $2)
+		WHERE id IN (SELECT todo_id FROM todo_tags WHERE tag_id = $1)
+	`
+	if _, err := tx.Exec(ctx, detachQuery, id, name); err != nil {
+		return err
+	}
+
+	// The tag's links go with it through ON DELETE CASCADE
+	if _, err := tx.Exec(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2`, id, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockTag locks one of the user's tags for the rest of the transaction and
+// returns its current name
+func lockTag(ctx context.Context, tx pgx.Tx, userID, id string) (string, error) {
+	if uuid.Validate(id) != nil {
+		return "", entity.ErrTagNotFound
+	}
+
+	var name string
+	err := tx.QueryRow(ctx, `SELECT name FROM tags WHERE id = $1 AND user_id = $2 FOR UPDATE`, id, userID).Scan(&name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", entity.ErrTagNotFound
+	}
+
+	return name, err
+}
+
+// lockTags locks several of the user's tags for the rest of the transaction
+// and returns their current names in the order of ids. The rows are locked in
+// ID order, so that two transactions locking the same tags cannot deadlock.
+func lockTags(ctx context.Context, tx pgx.Tx, userID string, ids ...string) ([]string, error) {
+	// Rows come back with IDs in canonical form
+	canonical := make([]string, len(ids))
+	for i, id := range ids {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, entity.ErrTagNotFound
+		}
+		canonical[i] = parsed.String()
+	}
+
+	query := `
+		SELECT id, name FROM tags
+		WHERE id = ANY($1::UUID[]) AND user_id = $2
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.Query(ctx, query, canonical, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[string]string, len(ids))
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		byID[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(ids))
+	for i, id := range canonical {
+		name, ok := byID[id]
+		if !ok {
+			return nil, entity.ErrTagNotFound
+		}
+		names[i] = name
+	}
+	return names, nil
+}
+
+// syncTodoTags creates the tags a todo names that its owner does not have
+// yet and links the todo to exactly its tags
+func syncTodoTags(ctx context.Context, tx pgx.Tx, todo *entity.Todo) error {
+	createQuery := `
+		INSERT INTO tags (id, user_id, name, color)
+		SELECT uuid_generate_v4(), $1, name, $3 FROM unnest($2::TEXT[]) AS name
+		ON CONFLICT (user_id, name) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, createQuery, todo.UserID, todo.Tags, entity.DefaultTagColor); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM todo_tags WHERE todo_id = $1`, todo.ID); err != nil {
+		return err
+	}
+
+	linkQuery := `
+		INSERT INTO todo_tags (todo_id, tag_id)
+		SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3)
+	`
+	_, err := tx.Exec(ctx, linkQuery, todo.ID, todo.UserID, todo.Tags)
+	return err
+}
+
+// scanTag scans a row selected with tagColumns into a tag
+func scanTag(row pgx.Row) (*entity.Tag, error) {
+	tag := &entity.Tag{}
+	err := row.Scan(
+		&tag.ID,
+		&tag.UserID,
+		&tag.Name,
+		&tag.Color,
+		&tag.TodoCount,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,490 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+const todoColumns = `id, code, user_id, title, description, status, priority, due_date, tags, completed_at, created_at, updated_at`
+
+// TodoRepository implements the TodoRepository interface using PostgreSQL
+type TodoRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTodoRepository creates a new PostgreSQL todo repository
+func NewTodoRepository(pool *pgxpool.Pool) *TodoRepository {
+	return &TodoRepository{pool: pool}
+}
+
+// Create creates a new todo in the database. Its short code is allocated
+// from code_sequences in the same transaction; the upsert locks the user's
+// sequence row, so concurrent creates are numbered one after another and a
+// failed insert does not use up a number. The initial status change is
+// recorded in the same transaction.
+func (r *TodoRepository) Create(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sequenceQuery := `
+		INSERT INTO code_sequences (user_id, year, last_value)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (user_id, year) DO UPDATE SET last_value = code_sequences.last_value + 1
+		RETURNING last_value
+	`
+
+	var seq int
+	if err := tx.QueryRow(ctx, sequenceQuery, todo.UserID, todo.CodeYear()).Scan(&seq); err != nil {
+		return err
+	}
+	todo.AssignCode(seq)
+
+	query := `
+		INSERT INTO todos (id, code, user_id, title, description, status, priority, due_date, tags, completed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		todo.ID,
+		todo.Code,
+		todo.UserID,
+		todo.Title,
+		todo.Description,
+		todo.Status,
+		todo.Priority,
+		todo.DueDate,
+		todo.Tags,
+		todo.CompletedAt,
+		todo.CreatedAt,
+		todo.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := syncTodoTags(ctx, tx, todo); err != nil {
+		return err
+	}
+
+	if err := insertTodoStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID retrieves a todo by ID or short code for the given user
+func (r *TodoRepository) GetByID(ctx context.Context, userID, id string) (*entity.Todo, error) {
+	column, ok := todoKeyColumn(id)
+	if !ok {
+		return nil, entity.ErrTodoNotFound
+	}
+
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE ` + column + ` = $1 AND user_id = $2
+	`
+
+	todo, err := scanTodo(r.pool.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTodoNotFound
+		}
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// List returns the user's todos matching the filter, up to its limit, and the
+// number matching in total. Pages are read by keyset: the cursor condition
+// continues after the previous page's last todo in the (sort value, id) order
+// that the todos_user_* indexes cover.
+func (r *TodoRepository) List(ctx context.Context, userID string, filter output.TodoFilter) ([]*entity.Todo, int, error) {
+	q := &todoQuery{}
+	q.where("user_id = " + q.arg(userID))
+	q.addFilter(userID, filter)
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM todos WHERE ` + q.conditions()
+	if err := r.pool.QueryRow(ctx, countQuery, q.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	column := todoSortColumns[filter.Sort]
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	// Only due dates can be missing; they are listed last in either direction
+	nulls := ""
+	if filter.Sort == output.TodoSortDueDate {
+		nulls = " NULLS LAST"
+	}
+	if filter.After != nil {
+		q.addCursor(column, filter.Descending, filter.After)
+	}
+
+	query := `
+		SELECT ` + todoColumns + `
+		FROM todos
+		WHERE ` + q.conditions() + `
+		ORDER BY ` + column + ` ` + direction + nulls + `, id ` + direction + `
+		LIMIT ` + q.arg(filter.Limit)
+
+	rows, err := r.pool.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	todos := make([]*entity.Todo, 0)
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return todos, total, nil
+}
+
+// Search returns the user's todos matching a full-text search, most relevant
+// first. Each language has its own generated tsvector column: title, tags and
+// description carry weights A, B and C, so that ts_rank_cd ranks title
+// matches highest. Highlights are only computed for the returned todos, from
+// HTML-escaped text so that only the <mark> tags are markup.
+func (r *TodoRepository) Search(ctx context.Context, userID string, search output.TodoSearch) ([]*entity.TodoSearchResult, error) {
+	language := todoSearchLanguages[search.Language]
+
+	q := &todoQuery{}
+	q.where("user_id = " + q.arg(userID))
+
+	// Every term must match
+	terms := make([]string, 0, len(search.Terms))
+	for _, term := range search.Terms {
+		text := q.arg(term.Text)
+		switch {
+		case term.Phrase:
+			terms = append(terms, "phraseto_tsquery('"+language.config+"', "+text+")")
+		case term.Prefix:
+			terms = append(terms, "to_tsquery('"+language.config+"', quote_literal("+text+") || ':*')")
+		default:
+			terms = append(terms, "plainto_tsquery('"+language.config+"', "+text+")")
+		}
+	}
+	q.where(language.column + " @@ query")
+
+	query := `
+		SELECT ` + todoColumns + `, rank,
+			ts_headline('` + language.config + `', ` + escapeHTML("title") + `, query, '` + titleHeadlineOptions + `'),
+			ts_headline('` + language.config + `', ` + escapeHTML("COALESCE(description, '')") + `, query, '` + descriptionHeadlineOptions + `')
+		FROM (
+			SELECT todos.*, query, ts_rank_cd(` + language.column + `, query)::FLOAT8 AS rank
+			FROM todos, (SELECT ` + strings.Join(terms, " && ") + ` AS query) AS search
+			WHERE ` + q.conditions() + `
+			ORDER BY rank DESC, updated_at DESC, id DESC
+			LIMIT ` + q.arg(search.Limit) + `
+		) AS matches
+		ORDER BY rank DESC, updated_at DESC, id DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*entity.TodoSearchResult, 0)
+	for rows.Next() {
+		todo := &entity.Todo{}
+		result := &entity.TodoSearchResult{Todo: todo}
+		var titleHighlight, descriptionHighlight string
+		if err := rows.Scan(append(todoFields(todo), &result.Rank, &titleHighlight, &descriptionHighlight)...); err != nil {
+			return nil, err
+		}
+		if todo.Tags == nil {
+			todo.Tags = []string{}
+		}
+
+		// ts_headline returns the start of the text when nothing in it matched
+		if strings.Contains(titleHighlight, highlightStart) {
+			result.TitleHighlight = titleHighlight
+		}
+		if strings.Contains(descriptionHighlight, highlightStart) {
+			result.DescriptionHighlight = descriptionHighlight
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// Update updates an existing todo, its tag links and its status history in
+// one transaction
+func (r *TodoRepository) Update(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE todos
+		SET title = $3, description = $4, status = $5, priority = $6, due_date = $7, tags = $8, completed_at = $9, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := tx.Exec(ctx, query,
+		todo.ID,
+		todo.UserID,
+		todo.Title,
+		todo.Description,
+		todo.Status,
+		todo.Priority,
+		todo.DueDate,
+		todo.Tags,
+		todo.CompletedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrTodoNotFound
+	}
+
+	if err := syncTodoTags(ctx, tx, todo); err != nil {
+		return err
+	}
+
+	if change != nil {
+		if err := insertTodoStatusChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete deletes a todo by ID or short code for the given user
+func (r *TodoRepository) Delete(ctx context.Context, userID, id string) error {
+	column, ok := todoKeyColumn(id)
+	if !ok {
+		return entity.ErrTodoNotFound
+	}
+
+	query := `DELETE FROM todos WHERE ` + column + ` = $1 AND user_id = $2`
+
+	result, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrTodoNotFound
+	}
+
+	return nil
+}
+
+// todoSortColumns maps sort fields to the columns they order by. Priorities
+// are ordered by their generated rank column rather than alphabetically.
+var todoSortColumns = map[output.TodoSortField]string{
+	output.TodoSortCreatedAt: "created_at",
+	output.TodoSortUpdatedAt: "updated_at",
+	output.TodoSortDueDate:   "due_date",
+	output.TodoSortPriority:  "priority_rank",
+}
+
+// todoQuery collects the conditions of a todo listing and their arguments
+type todoQuery struct {
+	clauses []string
+	args    []interface{}
+}
+
+// arg adds an argument and returns its placeholder
+func (q *todoQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// where adds a condition
+func (q *todoQuery) where(condition string) {
+	q.clauses = append(q.clauses, condition)
+}
+
+// conditions returns the conditions joined for a WHERE clause
+func (q *todoQuery) conditions() string {
+	return strings.Join(q.clauses, " AND ")
+}
+
+// addFilter adds the conditions of a todo filter other than its cursor
+func (q *todoQuery) addFilter(userID string, filter output.TodoFilter) {
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		q.where("status = ANY(" + q.arg(statuses) + ")")
+	}
+	if len(filter.Priorities) > 0 {
+		priorities := make([]string, 0, len(filter.Priorities))
+		for _, priority := range filter.Priorities {
+			priorities = append(priorities, string(priority))
+		}
+		q.where("priority = ANY(" + q.arg(priorities) + ")")
+	}
+	if len(filter.Tags) > 0 {
+		// Look the tags up by name and their todos through todo_tags, both indexed
+		tagged := "SELECT todo_tags.todo_id FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id" +
+			" WHERE tags.user_id = " + q.arg(userID) + " AND tags.name = ANY(" + q.arg(filter.Tags) + ")"
+		if filter.MatchAllTags {
+			tagged += " GROUP BY todo_tags.todo_id HAVING COUNT(*) = " + q.arg(len(filter.Tags))
+		}
+		q.where("id IN (" + tagged + ")")
+	}
+	if filter.DueBefore != nil {
+		q.where("due_date < " + q.arg(*filter.DueBefore))
+	}
+	if filter.DueAfter != nil {
+		q.where("due_date > " + q.arg(*filter.DueAfter))
+	}
+	if filter.CreatedBefore != nil {
+		q.where("created_at < " + q.arg(*filter.CreatedBefore))
+	}
+	if filter.CreatedAfter != nil {
+		q.where("created_at > " + q.arg(*filter.CreatedAfter))
+	}
+	if filter.Overdue {
+		q.where("due_date < NOW() AND status NOT IN ('completed', 'cancelled')")
+	}
+	if filter.Query != "" {
+		// Escape LIKE wildcards so the query matches literally
+		pattern := q.arg("%" + likeEscaper.Replace(filter.Query) + "%")
+		q.where("(title ILIKE " + pattern + " OR description ILIKE " + pattern + ")")
+	}
+}
+
+// addCursor adds the condition that continues a listing after the cursor.
+// Todos without a due date come last in either direction, so a due date
+// cursor either continues among the dated todos and then all undated ones,
+// or, once among the undated todos, continues by ID alone.
+func (q *todoQuery) addCursor(column string, descending bool, after *output.TodoCursor) {
+	operator := ">"
+	if descending {
+		operator = "<"
+	}
+
+	var value interface{}
+	switch column {
+	case "priority_rank":
+		value = after.Priority.Rank()
+	case "due_date":
+		if after.Time == nil {
+			q.where("(due_date IS NULL AND id " + operator + " " + q.arg(after.ID) + ")")
+			return
+		}
+		value = *after.Time
+	default:
+		value = *after.Time
+	}
+
+	condition := "(" + column + ", id) " + operator + " (" + q.arg(value) + ", " + q.arg(after.ID) + ")"
+	if column == "due_date" {
+		condition = "(" + condition + " OR due_date IS NULL)"
+	}
+	q.where(condition)
+}
+
+// todoSearchLanguage names the text search configuration of a search
+// language and the generated column indexed with it
+type todoSearchLanguage struct {
+	config string
+	column string
+}
+
+// todoSearchLanguages maps search languages to their configurations. The
+// vietnamese_unaccent configuration is created by the search migration.
+var todoSearchLanguages = map[entity.SearchLanguage]todoSearchLanguage{
+	entity.SearchLanguageEnglish:    {config: "english", column: "search_en"},
+	entity.SearchLanguageVietnamese: {config: "vietnamese_unaccent", column: "search_vi"},
+}
+
+// Options for highlighting search matches with ts_headline
+const (
+	highlightStart             = "<mark>"
+	titleHeadlineOptions       = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	descriptionHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+// escapeHTML returns SQL that HTML-escapes the text of expr like
+// html.EscapeString. The ts_headline parser reads the entities as non-words,
+// so escaping does not change what is highlighted.
+func escapeHTML(expr string) string {
+	return `replace(replace(replace(replace(replace(` + expr + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+}
+
+// todoKeyColumn returns the column a todo key is looked up by: code for
+// short codes and id for UUIDs. Any other key can never match, so it is
+// rejected rather than causing a cast error in Postgres.
+func todoKeyColumn(key string) (string, bool) {
+	switch {
+	case entity.IsTodoCode(key):
+		return "code", true
+	case uuid.Validate(key) == nil:
+		return "id", true
+	default:
+		return "", false
+	}
+}
+
+// scanTodo scans a row selected with todoColumns into a todo
+func scanTodo(row pgx.Row) (*entity.Todo, error) {
+	todo := &entity.Todo{}
+	if err := row.Scan(todoFields(todo)...); err != nil {
+		return nil, err
+	}
+
+	if todo.Tags == nil {
+		todo.Tags = []string{}
+	}
+
+	return todo, nil
+}
+
+// todoFields returns the scan destinations of todoColumns
+func todoFields(todo *entity.Todo) []interface{} {
+	return []interface{}{
+		&todo.ID,
+		&todo.Code,
+		&todo.UserID,
+		&todo.Title,
+		&todo.Description,
+		&todo.Status,
+		&todo.Priority,
+		&todo.DueDate,
+		&todo.Tags,
+		&todo.CompletedAt,
+		&todo.CreatedAt,
+		&todo.UpdatedAt,
+	}
+}
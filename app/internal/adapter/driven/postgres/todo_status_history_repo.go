@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// TodoStatusHistoryRepository implements the TodoStatusHistoryRepository interface using PostgreSQL
+type TodoStatusHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTodoStatusHistoryRepository creates a new PostgreSQL todo status history repository
+func NewTodoStatusHistoryRepository(pool *pgxpool.Pool) *TodoStatusHistoryRepository {
+	return &TodoStatusHistoryRepository{pool: pool}
+}
+
+// ListByTodoID retrieves the status changes of a todo, oldest first
+func (r *TodoStatusHistoryRepository) ListByTodoID(ctx context.Context, todoID string) ([]*entity.TodoStatusChange, error) {
+	query := `
+		SELECT id, todo_id, COALESCE(from_status, ''), to_status, changed_at
+		FROM todo_status_history
+		WHERE todo_id = $1
+		ORDER BY changed_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, todoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]*entity.TodoStatusChange, 0)
+	for rows.Next() {
+		change, err := scanTodoStatusChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// insertTodoStatusChange records a status change in the transaction that
+// saves its todo; the first change of a todo has no previous status
+func insertTodoStatusChange(ctx context.Context, tx pgx.Tx, change *entity.TodoStatusChange) error {
+	query := `
+		INSERT INTO todo_status_history (id, todo_id, from_status, to_status, changed_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+	`
+
+	_, err := tx.Exec(ctx, query,
+		change.ID,
+		change.TodoID,
+		change.FromStatus,
+		change.ToStatus,
+		change.ChangedAt,
+	)
+
+	return err
+}
+
+// scanTodoStatusChange scans a todo status history row
+func scanTodoStatusChange(row pgx.Row) (*entity.TodoStatusChange, error) {
+	change := &entity.TodoStatusChange{}
+	err := row.Scan(
+		&change.ID,
+		&change.TodoID,
+		&change.FromStatus,
+		&change.ToStatus,
+		&change.ChangedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
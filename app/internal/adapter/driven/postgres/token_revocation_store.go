@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TokenRevocationStore implements the TokenRevocationStore interface using PostgreSQL
+type TokenRevocationStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewTokenRevocationStore creates a new PostgreSQL token revocation store
+func NewTokenRevocationStore(pool *pgxpool.Pool) *TokenRevocationStore {
+	return &TokenRevocationStore{pool: pool}
+}
+
+// RevokeToken revokes a single access token by its ID
+func (s *TokenRevocationStore) RevokeToken(ctx context.Context, tokenID, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := s.pool.Exec(ctx, query, tokenID, userID, expiresAt)
+	return err
+}
+
+// RevokeAllForUser revokes every access token issued to the user before revokedAt
+func (s *TokenRevocationStore) RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	query := `
+		INSERT INTO user_token_revocations (user_id, revoked_before)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id)
+		DO UPDATE SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)
+	`
+
+	_, err := s.pool.Exec(ctx, query, userID, revokedAt)
+	return err
+}
+
+// IsRevoked reports whether a token with the given ID, owner and issue time is revoked
+func (s *TokenRevocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+			OR EXISTS (
+				SELECT 1 FROM user_token_revocations
+				WHERE user_id = $2 AND $3 < revoked_before
+			)
+	`
+
+	var revoked bool
+	if err := s.pool.QueryRow(ctx, query, tokenID, userID, issuedAt).Scan(&revoked); err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+// PurgeExpired deletes the revocations of single tokens that have expired anyway
+func (s *TokenRevocationStore) PurgeExpired(ctx context.Context) (int, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
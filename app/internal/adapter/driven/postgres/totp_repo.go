@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// TOTPRepository implements the TOTPRepository interface using PostgreSQL
+type TOTPRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewTOTPRepository creates a new PostgreSQL TOTP factor repository
+func NewTOTPRepository(pool *pgxpool.Pool) *TOTPRepository {
+	return &TOTPRepository{pool: pool}
+}
+
+// Save stores the user's TOTP factor, replacing any existing one
+func (r *TOTPRepository) Save(ctx context.Context, factor *entity.TOTPFactor) error {
+	query := `
+		INSERT INTO totp_factors (user_id, secret, last_used_step, confirmed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+			last_used_step = EXCLUDED.last_used_step,
+			confirmed_at = EXCLUDED.confirmed_at,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		factor.UserID,
+		factor.Secret,
+		factor.LastUsedStep,
+		factor.ConfirmedAt,
+		factor.CreatedAt,
+		factor.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByUserID retrieves the user's TOTP factor
+func (r *TOTPRepository) GetByUserID(ctx context.Context, userID string) (*entity.TOTPFactor, error) {
+	query := `
+		SELECT user_id, secret, last_used_step, confirmed_at, created_at, updated_at
+		FROM totp_factors
+		WHERE user_id = $1
+	`
+
+	factor := &entity.TOTPFactor{}
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&factor.UserID,
+		&factor.Secret,
+		&factor.LastUsedStep,
+		&factor.ConfirmedAt,
+		&factor.CreatedAt,
+		&factor.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTOTPNotFound
+		}
+		return nil, err
+	}
+
+	return factor, nil
+}
+
+// MarkStepUsed atomically records an accepted time step, so that two requests
+// racing with the same code cannot both succeed
+func (r *TOTPRepository) MarkStepUsed(ctx context.Context, userID string, step int64) error {
+	query := `
+		UPDATE totp_factors
+		SET last_used_step = $2, updated_at = NOW()
+		WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	result, err := r.pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return entity.ErrMFACodeReused
+	}
+
+	return nil
+}
+
+// Delete removes the user's TOTP factor
+func (r *TOTPRepository) Delete(ctx context.Context, userID string) error {
+	query := `DELETE FROM totp_factors WHERE user_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
@@ -3,11 +3,14 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
 )
 
 // UserRepository implements the UserRepository interface using PostgreSQL
@@ -23,14 +26,18 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 // Create creates a new user in the database
 func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, email, password_hash, role, email_verified_at, disabled_at, deleted_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
 		user.ID,
 		user.Email,
 		user.PasswordHash,
+		user.Role,
+		user.EmailVerifiedAt,
+		user.DisabledAt,
+		user.DeletedAt,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -48,48 +55,32 @@ func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*entity.User, error) {
-	query := `
-		SELECT id, email, password_hash, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+	return r.getUser(ctx, "id = $1 AND deleted_at IS NULL", id)
+}
 
-	user := &entity.User{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+// GetByEmail retrieves a user by email, ignoring case
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.getUser(ctx, "lower(email) = lower($1) AND deleted_at IS NULL", email)
+}
 
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, entity.ErrUserNotFound
-		}
-		return nil, err
-	}
+// GetDeletedByID retrieves a deleted user by ID
+func (r *UserRepository) GetDeletedByID(ctx context.Context, id string) (*entity.User, error) {
+	return r.getUser(ctx, "id = $1 AND deleted_at IS NOT NULL", id)
+}
 
-	return user, nil
+// GetDeletedByEmail retrieves a deleted user by email, ignoring case
+func (r *UserRepository) GetDeletedByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.getUser(ctx, "lower(email) = lower($1) AND deleted_at IS NOT NULL", email)
 }
 
-// GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+// getUser retrieves the user matching a condition on one parameter
+func (r *UserRepository) getUser(ctx context.Context, condition string, arg any) (*entity.User, error) {
 	query := `
-		SELECT id, email, password_hash, created_at, updated_at
+		SELECT id, email, password_hash, role, email_verified_at, disabled_at, deleted_at, created_at, updated_at
 		FROM users
-		WHERE email = $1
-	`
-
-	user := &entity.User{}
-	err := r.pool.QueryRow(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+		WHERE ` + condition
 
+	user, err := scanUser(r.pool.QueryRow(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, entity.ErrUserNotFound
@@ -104,7 +95,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entity.
 func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
 		UPDATE users
-		SET email = $2, password_hash = $3, updated_at = NOW()
+		SET email = $2, password_hash = $3, role = $4, email_verified_at = $5, disabled_at = $6, deleted_at = $7, updated_at = NOW()
 		WHERE id = $1
 	`
 
@@ -112,9 +103,16 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 		user.ID,
 		user.Email,
 		user.PasswordHash,
+		user.Role,
+		user.EmailVerifiedAt,
+		user.DisabledAt,
+		user.DeletedAt,
 	)
 
 	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.ErrEmailExists
+		}
 		return err
 	}
 
@@ -125,7 +123,62 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
-// Delete deletes a user by ID
+// List returns a page of users matching the filter and the number matching in total
+func (r *UserRepository) List(ctx context.Context, filter output.UserFilter) ([]*entity.User, int, error) {
+	// Escape LIKE wildcards so the query matches literally
+	pattern := "%" + likeEscaper.Replace(filter.Query) + "%"
+
+	query := `
+		SELECT id, email, password_hash, role, email_verified_at, disabled_at, deleted_at, created_at, updated_at,
+			COUNT(*) OVER () AS total
+		FROM users
+		WHERE email ILIKE $1 AND ($2 = '' OR role = $2) AND (deleted_at IS NOT NULL) = $3
+		ORDER BY created_at, id
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.pool.Query(ctx, query, pattern, string(filter.Role), filter.Deleted, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+	total := 0
+	for rows.Next() {
+		user := &entity.User{}
+		if err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.PasswordHash,
+			&user.Role,
+			&user.EmailVerifiedAt,
+			&user.DisabledAt,
+			&user.DeletedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// A page past the end has no rows to carry the total
+	if len(users) == 0 && filter.Offset > 0 {
+		countQuery := `SELECT COUNT(*) FROM users WHERE email ILIKE $1 AND ($2 = '' OR role = $2) AND (deleted_at IS NOT NULL) = $3`
+		if err := r.pool.QueryRow(ctx, countQuery, pattern, string(filter.Role), filter.Deleted).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return users, total, nil
+}
+
+// Delete permanently deletes a user by ID; owned data goes with it through ON DELETE CASCADE
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
 
@@ -141,6 +194,38 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// PurgeDeleted permanently deletes the users deleted before the cutoff
+func (r *UserRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := `DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	result, err := r.pool.Exec(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// scanUser reads a user from a query row
+func scanUser(row pgx.Row) (*entity.User, error) {
+	user := &entity.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.EmailVerifiedAt,
+		&user.DisabledAt,
+		&user.DeletedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	return user, err
+}
+
 // isUniqueViolation checks if the error is a unique constraint violation
 func isUniqueViolation(err error) bool {
 	return err != nil && !errors.Is(err, pgx.ErrNoRows) &&
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// CreatePersonalAccessToken handles POST /api/v1/me/tokens
+func (h *Handlers) CreatePersonalAccessToken(c echo.Context) error {
+	var req CreatePersonalAccessTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(err)
+	}
+
+	// Validate request; a name of only spaces counts as missing
+	req.Name = strings.TrimSpace(req.Name)
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+
+	token, accessToken, err := h.authService.CreatePersonalAccessToken(c.Request().Context(), userID, req.Name, req.Scopes, ttl)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, CreatedPersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: toPersonalAccessTokenResponse(accessToken),
+		Token:                       token,
+	})
+}
+
+// ListPersonalAccessTokens handles GET /api/v1/me/tokens
+func (h *Handlers) ListPersonalAccessTokens(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	tokens, err := h.authService.ListPersonalAccessTokens(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	response := PersonalAccessTokenListResponse{
+		Tokens: make([]PersonalAccessTokenResponse, 0, len(tokens)),
+	}
+	for _, token := range tokens {
+		response.Tokens = append(response.Tokens, toPersonalAccessTokenResponse(token))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RevokePersonalAccessToken handles DELETE /api/v1/me/tokens/:id
+func (h *Handlers) RevokePersonalAccessToken(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	if err := h.authService.RevokePersonalAccessToken(c.Request().Context(), userID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toPersonalAccessTokenResponse converts a personal access token to its response
+func toPersonalAccessTokenResponse(token *entity.PersonalAccessToken) PersonalAccessTokenResponse {
+	return PersonalAccessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+)
+
+// ChangePassword handles PUT /api/v1/me/password
+func (h *Handlers) ChangePassword(c echo.Context) error {
+	var req ChangePasswordRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	claims := c.Get("claims").(*auth.Claims)
+
+	tokens, err := h.authService.ChangePassword(c.Request().Context(), claims, req.CurrentPassword, req.NewPassword, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, TokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
+
+// ChangeEmail handles PUT /api/v1/me/email
+func (h *Handlers) ChangeEmail(c echo.Context) error {
+	var req ChangeEmailRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	if err := h.authService.ChangeEmail(c.Request().Context(), userID, req.NewEmail, req.Password); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, MessageResponse{
+		Message: "Check the new address for a link to confirm the change",
+	})
+}
+
+// DeleteAccount handles DELETE /api/v1/me
+func (h *Handlers) DeleteAccount(c echo.Context) error {
+	var req DeleteAccountRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	claims := c.Get("claims").(*auth.Claims)
+
+	if err := h.authService.DeleteAccount(c.Request().Context(), claims, req.Password); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
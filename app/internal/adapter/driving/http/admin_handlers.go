@@ -0,0 +1,137 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/service"
+)
+
+// ListUsers handles GET /api/v1/admin/users
+func (h *Handlers) ListUsers(c echo.Context) error {
+	var req ListUsersRequest
+	if err := c.Bind(&req); err != nil {
+		return errInvalidQuery
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	actorID := c.Get("user_id").(string)
+
+	page, err := h.authService.ListUsers(c.Request().Context(), actorID, service.ListUsersInput{
+		Query:   req.Query,
+		Role:    req.Role,
+		Deleted: req.Deleted,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := AdminUserListResponse{
+		Users:  make([]AdminUserResponse, 0, len(page.Users)),
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	for _, user := range page.Users {
+		resp.Users = append(resp.Users, toAdminUserResponse(user))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetUser handles GET /api/v1/admin/users/:id
+func (h *Handlers) GetUser(c echo.Context) error {
+	actorID := c.Get("user_id").(string)
+
+	user, err := h.authService.GetUser(c.Request().Context(), actorID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// SetUserRole handles PUT /api/v1/admin/users/:id/role
+func (h *Handlers) SetUserRole(c echo.Context) error {
+	var req SetUserRoleRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	actorID := c.Get("user_id").(string)
+
+	user, err := h.authService.SetUserRole(c.Request().Context(), actorID, c.Param("id"), req.Role)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// DisableUser handles POST /api/v1/admin/users/:id/disable
+func (h *Handlers) DisableUser(c echo.Context) error {
+	actorID := c.Get("user_id").(string)
+
+	user, err := h.authService.DisableUser(c.Request().Context(), actorID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// EnableUser handles POST /api/v1/admin/users/:id/enable
+func (h *Handlers) EnableUser(c echo.Context) error {
+	actorID := c.Get("user_id").(string)
+
+	user, err := h.authService.EnableUser(c.Request().Context(), actorID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// DeleteUser handles DELETE /api/v1/admin/users/:id
+func (h *Handlers) DeleteUser(c echo.Context) error {
+	actorID := c.Get("user_id").(string)
+
+	if err := h.authService.DeleteUser(c.Request().Context(), actorID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreUser handles POST /api/v1/admin/users/:id/restore
+func (h *Handlers) RestoreUser(c echo.Context) error {
+	actorID := c.Get("user_id").(string)
+
+	user, err := h.authService.RestoreUser(c.Request().Context(), actorID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// toAdminUserResponse converts a user to its admin response
+func toAdminUserResponse(user *entity.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		Role:          string(user.Role),
+		EmailVerified: user.IsEmailVerified(),
+		Disabled:      user.IsDisabled(),
+		DisabledAt:    user.DisabledAt,
+		DeletedAt:     user.DeletedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
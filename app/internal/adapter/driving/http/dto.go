@@ -1,11 +1,14 @@
 package http
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // RegisterRequest represents the registration request body
 type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 
 // LoginRequest represents the login request body
@@ -14,23 +17,254 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// RefreshRequest represents the refresh token request body
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest represents the optional logout request body
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// VerifyEmailRequest represents the email verification request body
+type VerifyEmailRequest struct {
+	Token string `json:"token" query:"token" form:"token" validate:"required"`
+}
+
+// ResendVerificationRequest represents the resend verification email request body
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ForgotPasswordRequest represents the password reset request body
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the password reset confirmation body. The
+// reset page posts it as a form.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" form:"token" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required"`
+}
+
+// ChangePasswordRequest represents the change password request body
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+// ChangeEmailRequest represents the change email request body
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// DeleteAccountRequest represents the delete account request body
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// VerifyMFARequest represents the second step of a login with two-factor authentication
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// ConfirmTOTPRequest represents a TOTP enrollment confirmation request
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MFASettingsRequest represents a request that changes two-factor settings.
+// Code is a current TOTP code or an unused recovery code.
+type MFASettingsRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
 // TokenResponse represents the JWT token response
 type TokenResponse struct {
-	Token     string `json:"token"`
-	ExpiresIn int    `json:"expires_in"` // seconds
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"` // seconds
+}
+
+// MFAChallengeResponse is returned by login when a second factor is required
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"` // seconds
+}
+
+// TOTPEnrollmentResponse represents a started TOTP enrollment
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse lists newly issued recovery codes
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAStatusResponse represents the two-factor authentication setup of a user
+type MFAStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// IdentityProvidersResponse lists the identity providers users may sign in with
+type IdentityProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// AuthorizationURLResponse holds the identity provider URL to send the user to
+type AuthorizationURLResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// IdentityResponse represents an identity provider account linked to the user
+type IdentityResponse struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IdentityListResponse represents the identities linked to the user
+type IdentityListResponse struct {
+	Identities []IdentityResponse `json:"identities"`
+}
+
+// CreatePersonalAccessTokenRequest represents the create personal access token request body
+type CreatePersonalAccessTokenRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+	// ExpiresInDays is the token lifetime; zero or omitted creates a token that never expires
+	ExpiresInDays int `json:"expires_in_days" validate:"min=0"`
+}
+
+// PersonalAccessTokenResponse represents a personal access token in API responses
+type PersonalAccessTokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedPersonalAccessTokenResponse carries a new token; the token value is only ever shown here
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
+
+// PersonalAccessTokenListResponse represents the user's personal access tokens
+type PersonalAccessTokenListResponse struct {
+	Tokens []PersonalAccessTokenResponse `json:"tokens"`
+}
+
+// SessionResponse represents a signed-in session in API responses
+type SessionResponse struct {
+	ID          string    `json:"id"`
+	DeviceLabel string    `json:"device_label"`
+	UserAgent   string    `json:"user_agent"`
+	IPAddress   string    `json:"ip_address"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	Current     bool      `json:"current"`
+}
+
+// SessionListResponse represents the user's signed-in sessions
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+// ListUsersRequest represents the query parameters of the admin user listing
+type ListUsersRequest struct {
+	Query   string `query:"q"`
+	Role    string `query:"role"`
+	Deleted bool   `query:"deleted"`
+	Limit   int    `query:"limit" validate:"min=0"`
+	Offset  int    `query:"offset" validate:"min=0"`
+}
+
+// SetUserRoleRequest represents the change role request body
+type SetUserRoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// AdminUserResponse represents a user in admin API responses
+type AdminUserResponse struct {
+	ID            string     `json:"id"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	EmailVerified bool       `json:"email_verified"`
+	Disabled      bool       `json:"disabled"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// AdminUserListResponse represents a page of users in admin API responses
+type AdminUserListResponse struct {
+	Users  []AdminUserResponse `json:"users"`
+	Total  int                 `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
 }
 
 // UserResponse represents a user in API responses
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an error response.
+// Details lists the failing fields of a request that failed validation;
+// Violations lists why a password was rejected by the password policy.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message,omitempty"`
+	Error      string                      `json:"error"`
+	Message    string                      `json:"message,omitempty"`
+	Details    any                         `json:"details,omitempty"`
+	Violations []PasswordViolationResponse `json:"violations,omitempty"`
+}
+
+// ProblemResponse represents an error response as RFC 7807 problem details,
+// extended with the error code and any details of the plain error response
+type ProblemResponse struct {
+	Type       string                      `json:"type"`
+	Title      string                      `json:"title"`
+	Status     int                         `json:"status"`
+	Detail     string                      `json:"detail,omitempty"`
+	Instance   string                      `json:"instance,omitempty"`
+	Code       string                      `json:"code"`
+	Details    any                         `json:"details,omitempty"`
+	Violations []PasswordViolationResponse `json:"violations,omitempty"`
+}
+
+// FieldErrorResponse represents a request field that failed validation
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordViolationResponse represents a single password policy violation
+type PasswordViolationResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// MessageResponse represents a plain informational response
+type MessageResponse struct {
+	Message string `json:"message"`
 }
 
 // HealthResponse represents the health check response
@@ -43,4 +277,169 @@ type HealthResponse struct {
 type MeResponse struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
+}
+
+// CreateTodoRequest represents the create todo request body
+type CreateTodoRequest struct {
+	Title       string     `json:"title" validate:"required,max=500"`
+	Description *string    `json:"description"`
+	Priority    string     `json:"priority" validate:"omitempty,oneof=low medium high"`
+	DueDate     *time.Time `json:"due_date"`
+	Tags        []string   `json:"tags"`
+}
+
+// UpdateTodoRequest represents the update todo request body.
+// Omitted fields are left unchanged; description and due date are cleared
+// with an explicit null.
+type UpdateTodoRequest struct {
+	Title       *string             `json:"title" validate:"omitempty,max=500"`
+	Description Nullable[string]    `json:"description"`
+	Status      *string             `json:"status" validate:"omitempty,oneof=pending in_progress blocked completed cancelled"`
+	Priority    *string             `json:"priority" validate:"omitempty,oneof=low medium high"`
+	DueDate     Nullable[time.Time] `json:"due_date"`
+	Tags        []string            `json:"tags"`
+}
+
+// TodoResponse represents a todo in API responses
+type TodoResponse struct {
+	ID          string     `json:"id"`
+	Code        string     `json:"code"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Tags        []string   `json:"tags"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ListTodosRequest represents the query parameters of the todo listing.
+// Status, priority and tag take comma-separated lists.
+type ListTodosRequest struct {
+	Status        string `query:"status"`
+	Priority      string `query:"priority"`
+	Tag           string `query:"tag"`
+	TagMatch      string `query:"tag_match" validate:"omitempty,oneof=any all"`
+	DueBefore     string `query:"due_before" validate:"omitempty,datetime"`
+	DueAfter      string `query:"due_after" validate:"omitempty,datetime"`
+	CreatedBefore string `query:"created_before" validate:"omitempty,datetime"`
+	CreatedAfter  string `query:"created_after" validate:"omitempty,datetime"`
+	Overdue       bool   `query:"overdue"`
+	Query         string `query:"q" validate:"max=200"`
+	Sort          string `query:"sort" validate:"omitempty,oneof=created_at updated_at due_date priority"`
+	Order         string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor        string `query:"cursor"`
+	Limit         int    `query:"limit" validate:"min=0"`
+}
+
+// TodoListResponse represents a page of todos in API responses
+type TodoListResponse struct {
+	Todos      []TodoResponse `json:"todos"`
+	Total      int            `json:"total"`
+	Limit      int            `json:"limit"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// SearchTodosRequest represents the query parameters of a todo search
+type SearchTodosRequest struct {
+	Query    string `query:"q" validate:"required,max=200"`
+	Language string `query:"lang" validate:"omitempty,oneof=en vi"`
+	Limit    int    `query:"limit" validate:"min=0"`
+}
+
+// TodoHighlightsResponse shows where a todo matched a search as HTML-escaped
+// text with matched words wrapped in <mark> tags
+type TodoHighlightsResponse struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TodoSearchResultResponse represents a todo matching a search
+type TodoSearchResultResponse struct {
+	Todo       TodoResponse           `json:"todo"`
+	Rank       float64                `json:"rank"`
+	Highlights TodoHighlightsResponse `json:"highlights"`
+}
+
+// TodoSearchResponse represents the todos matching a search, most relevant first
+type TodoSearchResponse struct {
+	Results []TodoSearchResultResponse `json:"results"`
+}
+
+// CreateTagRequest represents the create tag request body
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color"`
+}
+
+// UpdateTagRequest represents the update tag request body.
+// Omitted fields are left unchanged.
+type UpdateTagRequest struct {
+	Name  *string `json:"name" validate:"omitempty,max=50"`
+	Color *string `json:"color"`
+}
+
+// MergeTagRequest represents the merge tag request body
+type MergeTagRequest struct {
+	Into string `json:"into" validate:"required"`
+}
+
+// TagResponse represents a tag in API responses
+type TagResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	TodoCount int       `json:"todo_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TagListResponse represents the user's tags in API responses
+type TagListResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+// TodoStatusChangeResponse represents a todo status change in API responses.
+// The first change of a todo has no previous status.
+type TodoStatusChangeResponse struct {
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// TodoHistoryResponse represents the status history of a todo, oldest first
+type TodoHistoryResponse struct {
+	History []TodoStatusChangeResponse `json:"history"`
+}
+
+// Nullable is an optional request field that tells an explicit null apart
+// from a missing field: Set reports whether the field was sent, and Value is
+// nil when it was sent as null
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON records that the field was sent, and its value unless it is null
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
+
+// IsNull reports whether the field was sent as an explicit null
+func (n Nullable[T]) IsNull() bool {
+	return n.Set && n.Value == nil
 }
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// mimeProblemJSON is the media type of RFC 7807 problem details
+const mimeProblemJSON = "application/problem+json"
+
+// Errors raised by the HTTP adapter itself rather than the domain
+var (
+	errInvalidRequest     = entity.NewError(entity.KindInvalid, "invalid_request", "Invalid request body")
+	errInvalidQuery       = errInvalidRequest.WithMessage("Invalid query parameters")
+	errMissingToken       = entity.NewError(entity.KindUnauthorized, "missing_token", "Authorization header is required")
+	errInvalidTokenFormat = entity.NewError(entity.KindUnauthorized, "invalid_token_format", "Authorization header must be in format: Bearer <token>")
+	errInsufficientScope  = entity.NewError(entity.KindForbidden, "insufficient_scope", "Personal access tokens cannot be used for this endpoint")
+)
+
+// validationError reports a request that failed the handler's own checks
+func validationError(message string) error {
+	return entity.ErrValidation.WithMessage(message)
+}
+
+// HTTPErrorHandler renders every error returned by a handler or middleware.
+// Domain errors are reported with their code and the status for their kind;
+// anything unrecognised is logged and reported as an internal error so that
+// its text never reaches the client. Clients that accept
+// application/problem+json get RFC 7807 problem details instead.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, resp := toErrorResponse(err, c)
+
+	var retry *entity.RetryAfterError
+	if errors.As(err, &retry) {
+		seconds := int(math.Ceil(retry.RetryAfter.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+
+	switch {
+	case c.Request().Method == http.MethodHead:
+		err = c.NoContent(status)
+	case acceptsProblemJSON(c.Request()):
+		c.Response().Header().Set(echo.HeaderContentType, mimeProblemJSON)
+		err = c.JSON(status, ProblemResponse{
+			Type:       "about:blank",
+			Title:      http.StatusText(status),
+			Status:     status,
+			Detail:     resp.Message,
+			Instance:   c.Request().URL.Path,
+			Code:       resp.Error,
+			Details:    resp.Details,
+			Violations: resp.Violations,
+		})
+	default:
+		err = c.JSON(status, resp)
+	}
+	if err != nil {
+		log.Printf("Failed to write error response: %v", err)
+	}
+}
+
+// toErrorResponse converts an error to its status code and response body
+func toErrorResponse(err error, c echo.Context) (int, ErrorResponse) {
+	var weak *entity.PasswordPolicyError
+	if errors.As(err, &weak) {
+		resp := ErrorResponse{
+			Error:      entity.ErrWeakPassword.Code,
+			Violations: make([]PasswordViolationResponse, 0, len(weak.Violations)),
+		}
+		messages := make([]string, 0, len(weak.Violations))
+		for _, v := range weak.Violations {
+			resp.Violations = append(resp.Violations, PasswordViolationResponse{
+				Code:    v.Code,
+				Message: v.Message,
+			})
+			messages = append(messages, v.Message)
+		}
+		resp.Message = strings.Join(messages, "; ")
+		return http.StatusBadRequest, resp
+	}
+
+	var domainErr *entity.Error
+	if errors.As(err, &domainErr) && domainErr.Kind != entity.KindInternal {
+		return statusForKind(domainErr.Kind), ErrorResponse{
+			Error:   domainErr.Code,
+			Message: domainErr.Message,
+			Details: domainErr.Details,
+		}
+	}
+
+	// Routing errors such as unknown paths and methods come from Echo
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code < http.StatusInternalServerError {
+		message := http.StatusText(httpErr.Code)
+		if m, ok := httpErr.Message.(string); ok {
+			message = m
+		}
+		return httpErr.Code, ErrorResponse{
+			Error:   strings.ReplaceAll(strings.ToLower(http.StatusText(httpErr.Code)), " ", "_"),
+			Message: message,
+		}
+	}
+
+	log.Printf("%s %s failed: %v", c.Request().Method, c.Request().URL.Path, err)
+	return http.StatusInternalServerError, ErrorResponse{
+		Error:   entity.ErrInternal.Code,
+		Message: entity.ErrInternal.Message,
+	}
+}
+
+// statusForKind returns the HTTP status reported for a kind of domain error
+func statusForKind(kind entity.ErrorKind) int {
+	switch kind {
+	case entity.KindInvalid:
+		return http.StatusBadRequest
+	case entity.KindUnauthorized:
+		return http.StatusUnauthorized
+	case entity.KindForbidden:
+		return http.StatusForbidden
+	case entity.KindNotFound:
+		return http.StatusNotFound
+	case entity.KindConflict:
+		return http.StatusConflict
+	case entity.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// acceptsProblemJSON reports whether the client asked for problem details
+func acceptsProblemJSON(r *http.Request) bool {
+	for _, accept := range r.Header.Values(echo.HeaderAccept) {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.EqualFold(strings.TrimSpace(mediaType), mimeProblemJSON) {
+				return true
+			}
+		}
+	}
+	return false
+}
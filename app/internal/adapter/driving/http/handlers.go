@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/twaydev/golang-todolist/app/internal/auth"
 	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
 	"github.com/twaydev/golang-todolist/app/internal/domain/service"
 )
@@ -13,12 +14,16 @@ import (
 // Handlers holds the HTTP handlers
 type Handlers struct {
 	authService *service.AuthService
+	todoService *service.TodoService
+	tagService  *service.TagService
 }
 
 // NewHandlers creates a new handlers instance
-func NewHandlers(authService *service.AuthService) *Handlers {
+func NewHandlers(authService *service.AuthService, todoService *service.TodoService, tagService *service.TagService) *Handlers {
 	return &Handlers{
 		authService: authService,
+		todoService: todoService,
+		tagService:  tagService,
 	}
 }
 
@@ -30,104 +35,204 @@ func (h *Handlers) HealthCheck(c echo.Context) error {
 	})
 }
 
+// JWKS handles GET /.well-known/jwks.json
+func (h *Handlers) JWKS(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
 // Register handles POST /auth/register
 func (h *Handlers) Register(c echo.Context) error {
 	var req RegisterRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request body",
-		})
-	}
-
-	// Validate request
-	if req.Email == "" || req.Password == "" {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "validation_error",
-			Message: "Email and password are required",
-		})
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
 	user, err := h.authService.Register(c.Request().Context(), req.Email, req.Password)
 	if err != nil {
-		switch err {
-		case entity.ErrInvalidEmail:
-			return c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   "invalid_email",
-				Message: "Invalid email format",
-			})
-		case entity.ErrPasswordTooShort:
-			return c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   "password_too_short",
-				Message: "Password must be at least 8 characters",
-			})
-		case entity.ErrEmailExists:
-			return c.JSON(http.StatusConflict, ErrorResponse{
-				Error:   "email_exists",
-				Message: "Email already registered",
-			})
-		default:
-			return c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "internal_error",
-				Message: "An error occurred during registration",
-			})
-		}
+		return err
 	}
 
 	return c.JSON(http.StatusCreated, UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt,
+	})
+}
+
+// VerifyEmail handles GET and POST /auth/verify-email. Only POST confirms an
+// email change, so that fetching the emailed link cannot change the address.
+func (h *Handlers) VerifyEmail(c echo.Context) error {
+	var req VerifyEmailRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	verify := h.authService.VerifyEmail
+	if c.Request().Method == http.MethodGet {
+		verify = h.authService.VerifyEmailLink
+	}
+	if err := verify(c.Request().Context(), req.Token); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, MessageResponse{
+		Message: "Email verified",
+	})
+}
+
+// ResendVerification handles POST /auth/verify-email/resend
+func (h *Handlers) ResendVerification(c echo.Context) error {
+	var req ResendVerificationRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.authService.ResendVerificationEmail(c.Request().Context(), req.Email); err != nil {
+		return err
+	}
+
+	// Same response whether or not the address is registered
+	return c.JSON(http.StatusAccepted, MessageResponse{
+		Message: "If the address needs verification, an email has been sent",
+	})
+}
+
+// ForgotPassword handles POST /auth/password/forgot
+func (h *Handlers) ForgotPassword(c echo.Context) error {
+	var req ForgotPasswordRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request().Context(), req.Email); err != nil {
+		return err
+	}
+
+	// Same response whether or not the address is registered
+	return c.JSON(http.StatusAccepted, MessageResponse{
+		Message: "If the address is registered, a password reset email has been sent",
+	})
+}
+
+// ResetPassword handles POST /auth/password/reset
+func (h *Handlers) ResetPassword(c echo.Context) error {
+	var req ResetPasswordRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.authService.ResetPassword(c.Request().Context(), req.Token, req.Password); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, MessageResponse{
+		Message: "Password has been reset",
 	})
 }
 
 // Login handles POST /auth/login
 func (h *Handlers) Login(c echo.Context) error {
 	var req LoginRequest
-	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request body",
-		})
+	if err := bindRequest(c, &req); err != nil {
+		return err
 	}
 
-	// Validate request
-	if req.Email == "" || req.Password == "" {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "validation_error",
-			Message: "Email and password are required",
+	result, err := h.authService.Login(c.Request().Context(), req.Email, req.Password, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return loginResultResponse(c, result)
+}
+
+// loginResultResponse returns the tokens of a completed login, or the MFA
+// challenge to be completed at POST /auth/mfa/verify
+func loginResultResponse(c echo.Context, result *service.LoginResult) error {
+	if result.MFAChallenge != nil {
+		return c.JSON(http.StatusOK, MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.MFAChallenge.Token,
+			ExpiresIn:   result.MFAChallenge.ExpiresIn,
 		})
 	}
 
-	token, err := h.authService.Login(c.Request().Context(), req.Email, req.Password)
+	return c.JSON(http.StatusOK, TokenResponse{
+		Token:        result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
+		ExpiresIn:    result.Tokens.ExpiresIn,
+	})
+}
+
+// clientInfo describes the client that sent the request
+func clientInfo(c echo.Context) service.ClientInfo {
+	return service.ClientInfo{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+}
+
+// Refresh handles POST /auth/refresh
+func (h *Handlers) Refresh(c echo.Context) error {
+	var req RefreshRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authService.Refresh(c.Request().Context(), req.RefreshToken, clientInfo(c))
 	if err != nil {
-		switch err {
-		case entity.ErrUserNotFound, entity.ErrInvalidPassword:
-			return c.JSON(http.StatusUnauthorized, ErrorResponse{
-				Error:   "invalid_credentials",
-				Message: "Invalid email or password",
-			})
-		default:
-			return c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "internal_error",
-				Message: "An error occurred during login",
-			})
-		}
+		return err
 	}
 
 	return c.JSON(http.StatusOK, TokenResponse{
-		Token:     token,
-		ExpiresIn: 24 * 60 * 60, // 24 hours in seconds
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 	})
 }
 
+// Logout handles POST /auth/logout
+func (h *Handlers) Logout(c echo.Context) error {
+	var req LogoutRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	claims := c.Get("claims").(*auth.Claims)
+
+	if err := h.authService.Logout(c.Request().Context(), claims, req.RefreshToken); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// LogoutAll handles POST /auth/logout-all
+func (h *Handlers) LogoutAll(c echo.Context) error {
+	claims := c.Get("claims").(*auth.Claims)
+
+	if err := h.authService.LogoutAll(c.Request().Context(), claims); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // GetMe handles GET /api/v1/me
 func (h *Handlers) GetMe(c echo.Context) error {
 	userID := c.Get("user_id").(string)
 	email := c.Get("email").(string)
+	claims := c.Get("claims").(*auth.Claims)
+
+	// Tokens issued before roles existed carry none
+	role := claims.Role
+	if role == "" {
+		role = string(entity.RoleUser)
+	}
 
 	return c.JSON(http.StatusOK, MeResponse{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 	})
 }
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// VerifyMFA handles POST /auth/mfa/verify
+func (h *Handlers) VerifyMFA(c echo.Context) error {
+	var req VerifyMFARequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	tokens, err := h.authService.VerifyMFA(c.Request().Context(), req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, TokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
+
+// GetMFAStatus handles GET /api/v1/me/mfa
+func (h *Handlers) GetMFAStatus(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	status, err := h.authService.GetMFAStatus(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, MFAStatusResponse{
+		Enabled:                status.Enabled,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	})
+}
+
+// EnrollTOTP handles POST /api/v1/me/mfa/totp
+func (h *Handlers) EnrollTOTP(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	enrollment, err := h.authService.EnrollTOTP(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, TOTPEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.KeyURI,
+	})
+}
+
+// ConfirmTOTP handles POST /api/v1/me/mfa/totp/confirm
+func (h *Handlers) ConfirmTOTP(c echo.Context) error {
+	var req ConfirmTOTPRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	codes, err := h.authService.ConfirmTOTP(c.Request().Context(), userID, req.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA handles DELETE /api/v1/me/mfa
+func (h *Handlers) DisableMFA(c echo.Context) error {
+	var req MFASettingsRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	if err := h.authService.DisableMFA(c.Request().Context(), userID, req.Password, req.Code); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/me/mfa/recovery-codes
+func (h *Handlers) RegenerateRecoveryCodes(c echo.Context) error {
+	var req MFASettingsRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	codes, err := h.authService.RegenerateRecoveryCodes(c.Request().Context(), userID, req.Password, req.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
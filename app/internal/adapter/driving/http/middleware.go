@@ -1,52 +1,91 @@
 package http
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
 	"github.com/twaydev/golang-todolist/app/internal/domain/service"
 )
 
-// JWTMiddleware creates a middleware that validates JWT tokens
-func JWTMiddleware(authService *service.AuthService) echo.MiddlewareFunc {
+// JWTMiddleware creates a middleware that validates JWT tokens.
+// Personal access tokens are also accepted when scopes are given, provided
+// they were granted every one of them; routes without scopes are reserved
+// for signed-in sessions.
+func JWTMiddleware(authService *service.AuthService, scopes ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Get Authorization header
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
-				return c.JSON(http.StatusUnauthorized, ErrorResponse{
-					Error:   "missing_token",
-					Message: "Authorization header is required",
-				})
+				return errMissingToken
 			}
 
 			// Check Bearer prefix
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-				return c.JSON(http.StatusUnauthorized, ErrorResponse{
-					Error:   "invalid_token_format",
-					Message: "Authorization header must be in format: Bearer <token>",
-				})
+				return errInvalidTokenFormat
 			}
 
 			token := parts[1]
 
+			if auth.IsPersonalAccessToken(token) {
+				return authenticatePersonalAccessToken(c, next, authService, token, scopes)
+			}
+
 			// Validate token
-			claims, err := authService.ValidateToken(token)
+			claims, err := authService.ValidateToken(c.Request().Context(), token)
 			if err != nil {
-				return c.JSON(http.StatusUnauthorized, ErrorResponse{
-					Error:   "invalid_token",
-					Message: "Token is invalid or expired",
-				})
+				return err
 			}
 
 			// Set user info in context
 			c.Set("user_id", claims.UserID)
 			c.Set("email", claims.Email)
+			c.Set("claims", claims)
+
+			return next(c)
+		}
+	}
+}
+
+// RequirePermission creates a middleware that only admits signed-in sessions
+// whose role grants the permission. It must run after JWTMiddleware.
+func RequirePermission(permission entity.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get("claims").(*auth.Claims)
+			if !ok || !entity.Role(claims.Role).HasPermission(permission) {
+				return entity.ErrPermissionDenied
+			}
 
 			return next(c)
 		}
 	}
 }
+
+// authenticatePersonalAccessToken validates a personal access token and checks
+// that it was granted every scope the route requires
+func authenticatePersonalAccessToken(c echo.Context, next echo.HandlerFunc, authService *service.AuthService, token string, scopes []string) error {
+	accessToken, err := authService.ValidatePersonalAccessToken(c.Request().Context(), token)
+	if err != nil {
+		return err
+	}
+
+	if len(scopes) == 0 {
+		return errInsufficientScope
+	}
+	for _, scope := range scopes {
+		if !accessToken.HasScope(scope) {
+			return errInsufficientScope.WithMessage("Token requires the " + scope + " scope")
+		}
+	}
+
+	// Set user info in context
+	c.Set("user_id", accessToken.UserID)
+	c.Set("personal_access_token", accessToken)
+
+	return next(c)
+}
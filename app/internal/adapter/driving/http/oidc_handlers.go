@@ -0,0 +1,135 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/service"
+)
+
+// oidcStateCookie holds the state of the authorization request the browser
+// started, which the callback must carry back
+const oidcStateCookie = "oidc_state"
+
+// ListIdentityProviders handles GET /auth/oidc/providers
+func (h *Handlers) ListIdentityProviders(c echo.Context) error {
+	return c.JSON(http.StatusOK, IdentityProvidersResponse{
+		Providers: h.authService.IdentityProviders(),
+	})
+}
+
+// StartOIDCLogin handles GET /auth/oidc/:provider/authorize by redirecting
+// the browser to the identity provider
+func (h *Handlers) StartOIDCLogin(c echo.Context) error {
+	authorization, err := h.authService.StartOIDCLogin(c.Request().Context(), c.Param("provider"))
+	if err != nil {
+		return err
+	}
+
+	setOIDCStateCookie(c, authorization.State, int(service.OIDCAuthRequestExpiry.Seconds()))
+	return c.Redirect(http.StatusFound, authorization.URL)
+}
+
+// OIDCCallback handles GET /auth/oidc/:provider/callback
+func (h *Handlers) OIDCCallback(c echo.Context) error {
+	// The provider reports a refused or failed sign-in in the error parameter
+	if c.QueryParam("error") != "" {
+		return entity.ErrOIDCAuthFailed.WithMessage("Sign-in was cancelled or denied by the identity provider")
+	}
+
+	// Validate request
+	state, code := c.QueryParam("state"), c.QueryParam("code")
+	if state == "" || code == "" {
+		return validationError("State and code are required")
+	}
+
+	var browserState string
+	if cookie, err := c.Cookie(oidcStateCookie); err == nil {
+		browserState = cookie.Value
+	}
+	setOIDCStateCookie(c, "", -1)
+
+	result, err := h.authService.CompleteOIDC(c.Request().Context(), c.Param("provider"), state, browserState, code, clientInfo(c))
+	if err != nil {
+		return err
+	}
+
+	if result.LinkedIdentity != nil {
+		return c.JSON(http.StatusOK, toIdentityResponse(result.LinkedIdentity))
+	}
+
+	return loginResultResponse(c, result.Login)
+}
+
+// ListIdentities handles GET /api/v1/me/identities
+func (h *Handlers) ListIdentities(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	identities, err := h.authService.ListIdentities(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	response := IdentityListResponse{
+		Identities: make([]IdentityResponse, 0, len(identities)),
+	}
+	for _, identity := range identities {
+		response.Identities = append(response.Identities, toIdentityResponse(identity))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// StartOIDCLink handles POST /api/v1/me/identities/:provider. The client
+// sends the user to the returned URL in the same browser; the callback then
+// links the identity.
+func (h *Handlers) StartOIDCLink(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	authorization, err := h.authService.StartOIDCLink(c.Request().Context(), userID, c.Param("provider"))
+	if err != nil {
+		return err
+	}
+
+	setOIDCStateCookie(c, authorization.State, int(service.OIDCAuthRequestExpiry.Seconds()))
+	return c.JSON(http.StatusOK, AuthorizationURLResponse{AuthorizationURL: authorization.URL})
+}
+
+// UnlinkIdentity handles DELETE /api/v1/me/identities/:provider
+func (h *Handlers) UnlinkIdentity(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	if err := h.authService.UnlinkIdentity(c.Request().Context(), userID, c.Param("provider")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toIdentityResponse converts a linked identity to its response
+func toIdentityResponse(identity *entity.UserIdentity) IdentityResponse {
+	return IdentityResponse{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+// setOIDCStateCookie remembers the state of an authorization request in the
+// browser until the callback of the provider, or forgets it for a negative
+// maxAge. The callback is a cross-site navigation from the provider, so the
+// cookie must be SameSite=Lax rather than Strict to come along.
+func setOIDCStateCookie(c echo.Context, state string, maxAge int) {
+	c.SetCookie(&http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/auth/oidc/" + c.Param("provider") + "/callback",
+		MaxAge:   maxAge,
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// The API serves HTML only for the links it emails, so that they work in a
+// browser without a separate frontend. Each page is a form that posts the
+// token from the link to the matching API endpoint.
+
+// resetPasswordPage is opened by the link in password reset emails
+var resetPasswordPage = template.Must(template.New("reset-password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Reset your password</title>
+</head>
+<body>
+<h1>Reset your password</h1>
+<form method="post" action="/auth/password/reset">
+<input type="hidden" name="token" value="{{.Token}}">
+<label>New password <input type="password" name="password" autocomplete="new-password" required></label>
+<button type="submit">Reset password</button>
+</form>
+</body>
+</html>
+`))
+
+// confirmEmailChangePage is opened by the link in email change confirmation
+// emails. Opening the link must not apply the change by itself, since mail
+// scanners fetch links too.
+var confirmEmailChangePage = template.Must(template.New("confirm-email-change").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Confirm your new email address</title>
+</head>
+<body>
+<h1>Confirm your new email address</h1>
+<p>Confirming signs you out everywhere; sign in again with the new address.</p>
+<form method="post" action="/auth/verify-email">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Confirm email change</button>
+</form>
+</body>
+</html>
+`))
+
+// tokenPage holds what a page needs to post the token from its link
+type tokenPage struct {
+	Token string
+}
+
+// ResetPasswordPage handles GET /auth/password/reset, the page opened by the
+// link in password reset emails
+func (h *Handlers) ResetPasswordPage(c echo.Context) error {
+	return renderTokenPage(c, resetPasswordPage)
+}
+
+// ConfirmEmailChangePage handles GET /auth/verify-email/confirm, the page
+// opened by the link in email change confirmation emails
+func (h *Handlers) ConfirmEmailChangePage(c echo.Context) error {
+	return renderTokenPage(c, confirmEmailChangePage)
+}
+
+// renderTokenPage renders a page for the token in the link that opened it.
+// The token is kept out of caches and Referer headers.
+func renderTokenPage(c echo.Context, page *template.Template) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return validationError("Token is required")
+	}
+
+	var body bytes.Buffer
+	if err := page.Execute(&body, tokenPage{Token: token}); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Referrer-Policy", "no-referrer")
+	return c.HTMLBlob(http.StatusOK, body.Bytes())
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/service"
+)
+
+// RateLimitMiddleware creates a middleware that throttles requests to action per client IP
+func RateLimitMiddleware(authService *service.AuthService, action string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := authService.ThrottleClient(c.Request().Context(), action, c.RealIP()); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
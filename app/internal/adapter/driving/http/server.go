@@ -4,13 +4,23 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
 	"github.com/twaydev/golang-todolist/app/internal/domain/service"
 )
 
+// maxRequestBodySize bounds request bodies; larger requests are rejected with 413
+const maxRequestBodySize = "1M"
+
 // NewServer creates and configures a new Echo server
-func NewServer(authService *service.AuthService) *echo.Echo {
+func NewServer(authService *service.AuthService, todoService *service.TodoService, tagService *service.TagService) *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.Validator = NewRequestValidator()
+	e.JSONSerializer = StrictJSONSerializer{}
+	// Only trust X-Forwarded-For set by proxies on private networks, so that
+	// clients cannot pick their own IP to dodge rate limits
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
 
 	// Middleware
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
@@ -23,6 +33,7 @@ func NewServer(authService *service.AuthService) *echo.Echo {
 		},
 	}))
 	e.Use(middleware.Recover())
+	e.Use(middleware.BodyLimit(maxRequestBodySize))
 	e.Use(middleware.RequestID())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
@@ -31,20 +42,99 @@ func NewServer(authService *service.AuthService) *echo.Echo {
 	}))
 
 	// Initialize handlers
-	handlers := NewHandlers(authService)
+	handlers := NewHandlers(authService, todoService, tagService)
 
 	// Public routes
 	e.GET("/health", handlers.HealthCheck)
+	e.GET("/.well-known/jwks.json", handlers.JWKS)
 
 	// Auth routes (public)
 	auth := e.Group("/auth")
-	auth.POST("/register", handlers.Register)
-	auth.POST("/login", handlers.Login)
+	auth.POST("/register", handlers.Register, RateLimitMiddleware(authService, "register"))
+	auth.POST("/login", handlers.Login, RateLimitMiddleware(authService, "login"))
+	auth.POST("/mfa/verify", handlers.VerifyMFA, RateLimitMiddleware(authService, "mfa"))
+	auth.POST("/refresh", handlers.Refresh)
+	auth.GET("/oidc/providers", handlers.ListIdentityProviders)
+	auth.GET("/oidc/:provider/authorize", handlers.StartOIDCLogin, RateLimitMiddleware(authService, "oidc"))
+	auth.GET("/oidc/:provider/callback", handlers.OIDCCallback, RateLimitMiddleware(authService, "oidc"))
+	auth.GET("/verify-email", handlers.VerifyEmail)
+	auth.POST("/verify-email", handlers.VerifyEmail)
+	auth.GET("/verify-email/confirm", handlers.ConfirmEmailChangePage)
+	auth.POST("/verify-email/resend", handlers.ResendVerification, RateLimitMiddleware(authService, "verification"))
+	auth.POST("/password/forgot", handlers.ForgotPassword, RateLimitMiddleware(authService, "password-forgot"))
+	auth.GET("/password/reset", handlers.ResetPasswordPage)
+	auth.POST("/password/reset", handlers.ResetPassword, RateLimitMiddleware(authService, "password-reset"))
+
+	// Auth routes (authenticated)
+	auth.POST("/logout", handlers.Logout, JWTMiddleware(authService))
+	auth.POST("/logout-all", handlers.LogoutAll, JWTMiddleware(authService))
 
 	// Protected routes
 	api := e.Group("/api/v1")
-	api.Use(JWTMiddleware(authService))
-	api.GET("/me", handlers.GetMe)
+
+	// Account routes are reserved for signed-in sessions
+	me := api.Group("/me", JWTMiddleware(authService))
+	me.GET("", handlers.GetMe)
+	me.DELETE("", handlers.DeleteAccount)
+	me.PUT("/password", handlers.ChangePassword)
+	me.PUT("/email", handlers.ChangeEmail)
+
+	// Two-factor authentication routes
+	mfa := me.Group("/mfa")
+	mfa.GET("", handlers.GetMFAStatus)
+	mfa.DELETE("", handlers.DisableMFA)
+	mfa.POST("/totp", handlers.EnrollTOTP)
+	mfa.POST("/totp/confirm", handlers.ConfirmTOTP)
+	mfa.POST("/recovery-codes", handlers.RegenerateRecoveryCodes)
+
+	// Identity provider routes
+	identities := me.Group("/identities")
+	identities.GET("", handlers.ListIdentities)
+	identities.POST("/:provider", handlers.StartOIDCLink)
+	identities.DELETE("/:provider", handlers.UnlinkIdentity)
+
+	// Session routes
+	sessions := me.Group("/sessions")
+	sessions.GET("", handlers.ListSessions)
+	sessions.DELETE("/:id", handlers.RevokeSession)
+
+	// Personal access token routes
+	tokens := me.Group("/tokens")
+	tokens.POST("", handlers.CreatePersonalAccessToken)
+	tokens.GET("", handlers.ListPersonalAccessTokens)
+	tokens.DELETE("/:id", handlers.RevokePersonalAccessToken)
+
+	// User management routes
+	admin := api.Group("/admin", JWTMiddleware(authService), RequirePermission(entity.PermissionManageUsers))
+	admin.GET("/users", handlers.ListUsers)
+	admin.GET("/users/:id", handlers.GetUser)
+	admin.PUT("/users/:id/role", handlers.SetUserRole)
+	admin.POST("/users/:id/disable", handlers.DisableUser)
+	admin.POST("/users/:id/enable", handlers.EnableUser)
+	admin.DELETE("/users/:id", handlers.DeleteUser)
+	admin.POST("/users/:id/restore", handlers.RestoreUser)
+
+	// Todo routes also accept personal access tokens with the matching scope
+	readTodos := JWTMiddleware(authService, entity.ScopeTodosRead)
+	writeTodos := JWTMiddleware(authService, entity.ScopeTodosWrite)
+	todos := api.Group("/todos")
+	todos.POST("", handlers.CreateTodo, writeTodos)
+	todos.GET("", handlers.ListTodos, readTodos)
+	todos.GET("/search", handlers.SearchTodos, readTodos)
+	todos.GET("/:id", handlers.GetTodo, readTodos)
+	todos.PUT("/:id", handlers.UpdateTodo, writeTodos)
+	todos.DELETE("/:id", handlers.DeleteTodo, writeTodos)
+	todos.POST("/:id/reopen", handlers.ReopenTodo, writeTodos)
+	todos.GET("/:id/history", handlers.GetTodoHistory, readTodos)
+
+	// Tags belong to todos and share their scopes
+	tags := api.Group("/tags")
+	tags.GET("", handlers.ListTags, readTodos)
+	tags.POST("", handlers.CreateTag, writeTodos)
+	tags.GET("/:id", handlers.GetTag, readTodos)
+	tags.PUT("/:id", handlers.UpdateTag, writeTodos)
+	tags.POST("/:id/merge", handlers.MergeTag, writeTodos)
+	tags.DELETE("/:id", handlers.DeleteTag, writeTodos)
 
 	return e
 }
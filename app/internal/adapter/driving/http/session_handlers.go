@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+)
+
+// ListSessions handles GET /api/v1/me/sessions
+func (h *Handlers) ListSessions(c echo.Context) error {
+	claims := c.Get("claims").(*auth.Claims)
+
+	sessions, err := h.authService.ListSessions(c.Request().Context(), claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	response := SessionListResponse{
+		Sessions: make([]SessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, SessionResponse{
+			ID:          session.ID,
+			DeviceLabel: session.DeviceLabel,
+			UserAgent:   session.UserAgent,
+			IPAddress:   session.IPAddress,
+			CreatedAt:   session.CreatedAt,
+			LastSeenAt:  session.LastSeenAt,
+			Current:     session.ID == claims.SessionID,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RevokeSession handles DELETE /api/v1/me/sessions/:id
+func (h *Handlers) RevokeSession(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	if err := h.authService.RevokeSession(c.Request().Context(), userID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/service"
+)
+
+// ListTags handles GET /api/v1/tags
+func (h *Handlers) ListTags(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	tags, err := h.tagService.ListTags(c.Request().Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	resp := TagListResponse{
+		Tags: make([]TagResponse, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		resp.Tags = append(resp.Tags, newTagResponse(tag))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateTag handles POST /api/v1/tags
+func (h *Handlers) CreateTag(c echo.Context) error {
+	var req CreateTagRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	tag, err := h.tagService.CreateTag(c.Request().Context(), userID, service.CreateTagInput{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, newTagResponse(tag))
+}
+
+// GetTag handles GET /api/v1/tags/:id
+func (h *Handlers) GetTag(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	tag, err := h.tagService.GetTag(c.Request().Context(), userID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newTagResponse(tag))
+}
+
+// UpdateTag handles PUT /api/v1/tags/:id
+func (h *Handlers) UpdateTag(c echo.Context) error {
+	var req UpdateTagRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	tag, err := h.tagService.UpdateTag(c.Request().Context(), userID, c.Param("id"), service.UpdateTagInput{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newTagResponse(tag))
+}
+
+// MergeTag handles POST /api/v1/tags/:id/merge
+func (h *Handlers) MergeTag(c echo.Context) error {
+	var req MergeTagRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	tag, err := h.tagService.MergeTags(c.Request().Context(), userID, c.Param("id"), req.Into)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newTagResponse(tag))
+}
+
+// DeleteTag handles DELETE /api/v1/tags/:id
+func (h *Handlers) DeleteTag(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	if err := h.tagService.DeleteTag(c.Request().Context(), userID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// newTagResponse converts a tag entity to its API representation
+func newTagResponse(tag *entity.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		TodoCount: tag.TodoCount,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
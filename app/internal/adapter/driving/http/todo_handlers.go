@@ -0,0 +1,250 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/service"
+)
+
+// CreateTodo handles POST /api/v1/todos
+func (h *Handlers) CreateTodo(c echo.Context) error {
+	var req CreateTodoRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	todo, err := h.todoService.CreateTodo(c.Request().Context(), userID, service.CreateTodoInput{
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    entity.Priority(req.Priority),
+		DueDate:     req.DueDate,
+		Tags:        req.Tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, newTodoResponse(todo))
+}
+
+// ListTodos handles GET /api/v1/todos
+func (h *Handlers) ListTodos(c echo.Context) error {
+	var req ListTodosRequest
+	if err := c.Bind(&req); err != nil {
+		return errInvalidQuery
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	page, err := h.todoService.ListTodos(c.Request().Context(), userID, service.ListTodosInput{
+		Statuses:      splitList(req.Status),
+		Priorities:    splitList(req.Priority),
+		Tags:          splitList(req.Tag),
+		MatchAllTags:  req.TagMatch == "all",
+		DueBefore:     queryTime(req.DueBefore),
+		DueAfter:      queryTime(req.DueAfter),
+		CreatedBefore: queryTime(req.CreatedBefore),
+		CreatedAfter:  queryTime(req.CreatedAfter),
+		Overdue:       req.Overdue,
+		Query:         req.Query,
+		Sort:          req.Sort,
+		Order:         req.Order,
+		Cursor:        req.Cursor,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := TodoListResponse{
+		Todos:      make([]TodoResponse, 0, len(page.Todos)),
+		Total:      page.Total,
+		Limit:      page.Limit,
+		NextCursor: page.NextCursor,
+	}
+	for _, todo := range page.Todos {
+		resp.Todos = append(resp.Todos, newTodoResponse(todo))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SearchTodos handles GET /api/v1/todos/search
+func (h *Handlers) SearchTodos(c echo.Context) error {
+	var req SearchTodosRequest
+	if err := c.Bind(&req); err != nil {
+		return errInvalidQuery
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	results, err := h.todoService.SearchTodos(c.Request().Context(), userID, service.SearchTodosInput{
+		Query:    req.Query,
+		Language: req.Language,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := TodoSearchResponse{
+		Results: make([]TodoSearchResultResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		resp.Results = append(resp.Results, TodoSearchResultResponse{
+			Todo: newTodoResponse(result.Todo),
+			Rank: result.Rank,
+			Highlights: TodoHighlightsResponse{
+				Title:       result.TitleHighlight,
+				Description: result.DescriptionHighlight,
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetTodo handles GET /api/v1/todos/:id
+func (h *Handlers) GetTodo(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	todo, err := h.todoService.GetTodo(c.Request().Context(), userID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newTodoResponse(todo))
+}
+
+// UpdateTodo handles PUT /api/v1/todos/:id
+func (h *Handlers) UpdateTodo(c echo.Context) error {
+	var req UpdateTodoRequest
+	if err := bindRequest(c, &req); err != nil {
+		return err
+	}
+
+	userID := c.Get("user_id").(string)
+
+	input := service.UpdateTodoInput{
+		Title:            req.Title,
+		Description:      req.Description.Value,
+		ClearDescription: req.Description.IsNull(),
+		DueDate:          req.DueDate.Value,
+		ClearDueDate:     req.DueDate.IsNull(),
+		Tags:             req.Tags,
+	}
+	if req.Status != nil {
+		status := entity.Status(*req.Status)
+		input.Status = &status
+	}
+	if req.Priority != nil {
+		priority := entity.Priority(*req.Priority)
+		input.Priority = &priority
+	}
+
+	todo, err := h.todoService.UpdateTodo(c.Request().Context(), userID, c.Param("id"), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newTodoResponse(todo))
+}
+
+// DeleteTodo handles DELETE /api/v1/todos/:id
+func (h *Handlers) DeleteTodo(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	if err := h.todoService.DeleteTodo(c.Request().Context(), userID, c.Param("id")); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ReopenTodo handles POST /api/v1/todos/:id/reopen
+func (h *Handlers) ReopenTodo(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	todo, err := h.todoService.ReopenTodo(c.Request().Context(), userID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newTodoResponse(todo))
+}
+
+// GetTodoHistory handles GET /api/v1/todos/:id/history
+func (h *Handlers) GetTodoHistory(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	changes, err := h.todoService.GetTodoHistory(c.Request().Context(), userID, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	resp := TodoHistoryResponse{
+		History: make([]TodoStatusChangeResponse, 0, len(changes)),
+	}
+	for _, change := range changes {
+		resp.History = append(resp.History, TodoStatusChangeResponse{
+			FromStatus: string(change.FromStatus),
+			ToStatus:   string(change.ToStatus),
+			ChangedAt:  change.ChangedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// splitList splits a comma-separated query parameter, dropping blank items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// queryTime parses an optional time query parameter that already passed the datetime rule
+func queryTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := parseDateTime(value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// newTodoResponse converts a todo entity to its API representation
+func newTodoResponse(todo *entity.Todo) TodoResponse {
+	return TodoResponse{
+		ID:          todo.ID,
+		Code:        todo.Code,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      string(todo.Status),
+		Priority:    string(todo.Priority),
+		DueDate:     todo.DueDate,
+		Tags:        todo.Tags,
+		CompletedAt: todo.CompletedAt,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// RequestValidator enforces the validate tags of request DTOs with
+// go-playground/validator. On top of its rules, email accepts the addresses
+// the domain can normalize and datetime takes an RFC 3339 timestamp or a
+// YYYY-MM-DD date. Every failing field is reported, not just the first.
+type RequestValidator struct {
+	validate *validator.Validate
+}
+
+// NewRequestValidator creates a new request validator
+func NewRequestValidator() *RequestValidator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(fieldName)
+
+	// Neither rule can fail to register: the names are not reserved
+	_ = validate.RegisterValidation("email", func(fl validator.FieldLevel) bool {
+		_, err := entity.NormalizeEmail(fl.Field().String())
+		return err == nil
+	})
+	_ = validate.RegisterValidation("datetime", func(fl validator.FieldLevel) bool {
+		_, err := parseDateTime(fl.Field().String())
+		return err == nil
+	})
+
+	return &RequestValidator{validate: validate}
+}
+
+// Validate checks a request DTO, or a pointer to one, against its tags
+func (v *RequestValidator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return fmt.Errorf("cannot validate %T: %w", i, err)
+	}
+
+	failures := make([]FieldErrorResponse, 0, len(validationErrs))
+	messages := make([]string, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		failure := FieldErrorResponse{
+			Field:   fieldPath(fieldErr),
+			Rule:    fieldErr.Tag(),
+			Message: failureMessage(fieldErr),
+		}
+		failures = append(failures, failure)
+		messages = append(messages, failure.Field+" "+failure.Message)
+	}
+	return entity.ErrValidation.WithMessage(strings.Join(messages, "; ")).WithDetails(failures)
+}
+
+// failureMessage explains why a field broke its rule
+func failureMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + fieldErr.Param() + sizeUnit(fieldErr.Kind(), fieldErr.Param())
+	case "max":
+		return "must be at most " + fieldErr.Param() + sizeUnit(fieldErr.Kind(), fieldErr.Param())
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	case "datetime":
+		return "must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+	default:
+		return "must satisfy the " + fieldErr.Tag() + " rule"
+	}
+}
+
+// sizeUnit returns the unit a min or max limit is reported in for a kind of field
+func sizeUnit(kind reflect.Kind, limit string) string {
+	unit := ""
+	switch kind {
+	case reflect.String:
+		unit = " character"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " item"
+	default:
+		return ""
+	}
+	if limit != "1" {
+		unit += "s"
+	}
+	return unit
+}
+
+// fieldPath returns the path clients use for a failing field, such as
+// items[0].title for a field of a nested struct
+func fieldPath(fieldErr validator.FieldError) string {
+	// The namespace starts with the name of the validated struct
+	_, path, ok := strings.Cut(fieldErr.Namespace(), ".")
+	if !ok {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// parseDateTime reads an RFC 3339 timestamp or a YYYY-MM-DD date, which
+// stands for midnight UTC at its start
+func parseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// fieldName returns the name clients use for a field
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "query"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// StrictJSONSerializer decodes request bodies like Echo's default serializer
+// but rejects fields the request DTO does not declare, so that typos are
+// reported instead of silently ignored
+type StrictJSONSerializer struct {
+	echo.DefaultJSONSerializer
+}
+
+// Deserialize reads a JSON request body into i
+func (s StrictJSONSerializer) Deserialize(c echo.Context, i interface{}) error {
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(i)
+	if err == nil {
+		return nil
+	}
+
+	// Bodies over the size limit fail while being read
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return errInvalidRequest.WithMessage("Unknown field: " + strings.Trim(field, `"`))
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
+// bindRequest binds the request into req and validates it against its tags
+func bindRequest(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return bindError(err)
+	}
+	return c.Validate(req)
+}
+
+// bindError reports why a request could not be bound
+func bindError(err error) error {
+	var domainErr *entity.Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+
+	// Oversized bodies keep their own status
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code != http.StatusBadRequest {
+		return httpErr
+	}
+
+	return errInvalidRequest
+}
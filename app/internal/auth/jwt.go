@@ -5,27 +5,39 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidToken = entity.NewError(entity.KindUnauthorized, "invalid_token", "Token is invalid or expired")
+	ErrExpiredToken = entity.NewError(entity.KindUnauthorized, "invalid_token", "Token is invalid or expired")
+	ErrRevokedToken = entity.NewError(entity.KindUnauthorized, "token_revoked", "Token has been revoked")
 )
 
-// Claims represents the JWT claims
+// Claims represents the JWT claims. Its times are whole seconds, as most
+// verifiers expect of NumericDate values.
+// RegisteredClaims.ID carries the unique token ID (jti) used for revocation;
+// SessionID names the signed-in session the token belongs to.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// JWTManager handles JWT token operations
+// JWTManager handles JWT token operations.
+// It signs either with a shared HMAC secret (HS256) or, when created with a
+// key set, with asymmetric keys (RS256/EdDSA) selected by the kid header.
 type JWTManager struct {
 	secretKey []byte
+	keys      *KeySet
 	expiry    time.Duration
 }
 
-// NewJWTManager creates a new JWT manager
+// NewJWTManager creates a new JWT manager that signs with a shared HMAC secret
 func NewJWTManager(secretKey string, expiryHours int) *JWTManager {
 	return &JWTManager{
 		secretKey: []byte(secretKey),
@@ -33,30 +45,58 @@ func NewJWTManager(secretKey string, expiryHours int) *JWTManager {
 	}
 }
 
-// GenerateToken creates a new JWT token for a user
-func (m *JWTManager) GenerateToken(userID, email string) (string, error) {
+// NewJWTManagerWithKeys creates a new JWT manager that signs with asymmetric keys
+func NewJWTManagerWithKeys(keys *KeySet, expiryHours int) *JWTManager {
+	return &JWTManager{
+		keys:   keys,
+		expiry: time.Duration(expiryHours) * time.Hour,
+	}
+}
+
+// JWKS returns the public verification keys.
+// The set is empty when tokens are signed with a shared secret.
+func (m *JWTManager) JWKS() JWKS {
+	if m.keys == nil {
+		return JWKS{Keys: []JWK{}}
+	}
+	return m.keys.JWKS()
+}
+
+// Expiry returns the lifetime of issued tokens
+func (m *JWTManager) Expiry() time.Duration {
+	return m.expiry
+}
+
+// GenerateToken creates a new JWT token for a user with the given role in a session
+func (m *JWTManager) GenerateToken(userID, email, role, sessionID string) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secretKey)
+	if m.keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(m.secretKey)
+	}
+
+	active := m.keys.Active()
+	token := jwt.NewWithClaims(active.Method, claims)
+	token.Header["kid"] = active.ID
+	return token.SignedString(active.PrivateKey)
 }
 
 // ValidateToken validates a JWT token and returns the claims
 func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return m.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.keyFunc)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -72,3 +112,21 @@ func (m *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// keyFunc resolves the verification key for a token, rejecting any algorithm
+// other than the one configured for its key
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	if m.keys == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return m.secretKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys.Lookup(kid)
+	if !ok || token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	return key.PublicKey, nil
+}
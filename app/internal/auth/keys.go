@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported key type")
+	ErrInvalidKeySpec = errors.New("key spec must be in format kid=path")
+)
+
+// SigningKey is an asymmetric key identified by a key ID (kid).
+// PrivateKey is nil for keys that are only kept to verify older tokens.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// NewSigningKey creates a signing key from an RSA or Ed25519 private key
+func NewSigningKey(kid string, privateKey crypto.Signer) (*SigningKey, error) {
+	method, err := signingMethodFor(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:         kid,
+		Method:     method,
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.Public(),
+	}, nil
+}
+
+// NewVerificationKey creates a verify-only key from an RSA or Ed25519 public key
+func NewVerificationKey(kid string, publicKey crypto.PublicKey) (*SigningKey, error) {
+	method, err := signingMethodFor(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:        kid,
+		Method:    method,
+		PublicKey: publicKey,
+	}, nil
+}
+
+// KeySet holds the key used to sign new tokens and every key accepted for verification
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	order  []string
+}
+
+// NewKeySet creates a key set that signs with active and also verifies with others.
+// Rotating keys means moving the previous active key into others until every
+// token it signed has expired.
+func NewKeySet(active *SigningKey, others ...*SigningKey) (*KeySet, error) {
+	if active == nil || active.PrivateKey == nil {
+		return nil, errors.New("active key must have a private key")
+	}
+
+	ks := &KeySet{
+		active: active,
+		keys:   make(map[string]*SigningKey),
+	}
+
+	for _, key := range append([]*SigningKey{active}, others...) {
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+		ks.order = append(ks.order, key.ID)
+	}
+
+	return ks, nil
+}
+
+// Active returns the key used to sign new tokens
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+// Lookup returns the key with the given ID
+func (ks *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// JWKS returns the public keys of the set as a JSON Web Key Set
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		set.Keys = append(set.Keys, newJWK(ks.keys[kid]))
+	}
+	return set
+}
+
+// LoadKeySet loads a key set from comma-separated "kid=path" specs.
+// The first signing key is the active one; the remaining signing keys and
+// every verification key (PEM public keys) are accepted for verification only.
+func LoadKeySet(signingSpec, verificationSpec string) (*KeySet, error) {
+	signingFiles, err := parseKeySpecs(signingSpec)
+	if err != nil {
+		return nil, err
+	}
+	if len(signingFiles) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+
+	verificationFiles, err := parseKeySpecs(verificationSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*SigningKey, 0, len(signingFiles)+len(verificationFiles))
+	for _, spec := range signingFiles {
+		key, err := loadPrivateKeyFile(spec.kid, spec.path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	for _, spec := range verificationFiles {
+		key, err := loadPublicKeyFile(spec.kid, spec.path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys[0], keys[1:]...)
+}
+
+type keySpec struct {
+	kid  string
+	path string
+}
+
+func parseKeySpecs(spec string) ([]keySpec, error) {
+	specs := make([]keySpec, 0)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(part, "=")
+		if !ok || kid == "" || path == "" {
+			return nil, ErrInvalidKeySpec
+		}
+		specs = append(specs, keySpec{kid: strings.TrimSpace(kid), path: strings.TrimSpace(path)})
+	}
+	return specs, nil
+}
+
+func loadPrivateKeyFile(kid, path string) (*SigningKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	return NewSigningKey(kid, signer)
+}
+
+func loadPublicKeyFile(kid, path string) (*SigningKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed any
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+
+	return NewVerificationKey(kid, parsed)
+}
+
+func readPEMFile(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	return block, nil
+}
+
+func signingMethodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// JWK represents a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS represents a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func newJWK(key *SigningKey) JWK {
+	jwk := JWK{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Method.Alg(),
+	}
+
+	switch pub := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return jwk
+}
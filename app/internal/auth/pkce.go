@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// PKCEChallenge returns the S256 code challenge for a PKCE code verifier (RFC 7636)
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// recoveryCodeAlphabet avoids characters that are easily confused when typed
+const recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// recoveryCodeLength is the number of characters in a recovery code, excluding the separator
+const recoveryCodeLength = 10
+
+// GenerateRecoveryCode creates a random recovery code formatted as XXXXX-XXXXX and its storage hash
+func GenerateRecoveryCode() (code string, hash string, err error) {
+	buf := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	// The alphabet has 32 characters, so masking keeps the distribution uniform
+	chars := make([]byte, recoveryCodeLength)
+	for i, b := range buf {
+		chars[i] = recoveryCodeAlphabet[b&31]
+	}
+
+	code = string(chars[:recoveryCodeLength/2]) + "-" + string(chars[recoveryCodeLength/2:])
+	return code, HashRecoveryCode(code), nil
+}
+
+// HashRecoveryCode returns the hash used to store and look up a recovery code.
+// Case, spaces and dashes are ignored so codes can be typed loosely.
+func HashRecoveryCode(code string) string {
+	return HashOpaqueToken(NormalizeRecoveryCode(code))
+}
+
+// NormalizeRecoveryCode uppercases a recovery code and strips separators
+func NormalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// opaqueTokenBytes is the amount of entropy in generated opaque tokens
+const opaqueTokenBytes = 32
+
+// GenerateOpaqueToken creates a random URL-safe token and its storage hash.
+// Only the hash should be persisted; the token is handed to the client once.
+func GenerateOpaqueToken() (token string, hash string, err error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken returns the SHA-256 hex digest used to store and look up an opaque token
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PersonalAccessTokenPrefix marks personal access tokens so that they can be
+// told apart from JWTs and recognized by secret scanners
+const PersonalAccessTokenPrefix = "tdl_pat_"
+
+// GeneratePersonalAccessToken creates a random personal access token and its storage hash
+func GeneratePersonalAccessToken() (token string, hash string, err error) {
+	secret, _, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	token = PersonalAccessTokenPrefix + secret
+	return token, HashOpaqueToken(token), nil
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
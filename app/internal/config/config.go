@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config holds the application configuration
@@ -13,18 +14,174 @@ type Config struct {
 	DatabaseURL    string
 	JWTSecret      string
 	JWTExpiryHours int
+
+	// Asymmetric signing keys as comma-separated "kid=path" PEM files.
+	// When set they replace JWTSecret; the first signing key is active.
+	JWTSigningKeys      string
+	JWTVerificationKeys string
+
+	RefreshTokenExpiryHours int
+
+	// Emailed tokens
+	AppBaseURL                   string
+	RequireEmailVerification     bool
+	EmailVerificationExpiryHours int
+	PasswordResetExpiryMinutes   int
+
+	// Mail delivery; when SMTPHost is empty emails are written to MailLogFile (or stdout)
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	MailLogFile  string
+
+	// Brute-force protection; AttemptStore is "postgres" or "memory"
+	AttemptStore               string
+	LoginMaxFailures           int
+	LoginLockoutSeconds        int
+	LoginMaxLockoutMinutes     int
+	LoginFailureWindowMinutes  int
+	AuthRateLimitPerIP         int
+	AuthRateLimitWindowSeconds int
+	// Requests per address to endpoints that send email, such as password resets
+	EmailRateLimitPerAddress    int
+	EmailRateLimitWindowMinutes int
+
+	// Password hashing; PasswordHashAlgorithm is "argon2id" or "bcrypt".
+	// Hashes made with the other algorithm or older parameters are upgraded on login.
+	PasswordHashAlgorithm string
+	BcryptCost            int
+	Argon2MemoryKiB       int
+	Argon2Iterations      int
+	Argon2Parallelism     int
+
+	// Password policy; PasswordBlocklistFile adds one rejected password per line.
+	// BreachedPasswordsDir holds SHA-1 hash-prefix range files; empty disables the check.
+	PasswordMinLength        int
+	PasswordMaxLength        int
+	PasswordRequireUppercase bool
+	PasswordRequireLowercase bool
+	PasswordRequireDigit     bool
+	PasswordRequireSymbol    bool
+	PasswordBlocklistFile    string
+	BreachedPasswordsDir     string
+	BreachedPasswordMinCount int
+
+	// Two-factor authentication; MFAIssuer is shown in authenticator apps
+	MFAIssuer                 string
+	MFAChallengeExpiryMinutes int
+	MFAMaxAttempts            int
+	MFAMaxFailures            int
+
+	// Deleted accounts can be restored for AccountDeletionGraceDays (0 deletes
+	// them immediately); a job purges expired ones, and expired auth records,
+	// every AccountPurgeIntervalMinutes
+	AccountDeletionGraceDays    int
+	AccountPurgeIntervalMinutes int
+
+	// OpenID Connect identity providers, from OIDC_PROVIDERS
+	OIDCProviders []OIDCProviderConfig
+}
+
+// OIDCProviderConfig holds the registration of the application at an identity provider
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 // Load loads configuration from environment variables
 func Load() *Config {
-	return &Config{
+	cfg := &Config{
 		Port:           getEnv("PORT", "8080"),
 		Environment:    getEnv("ENV", "development"),
 		LogLevel:       getEnv("LOG_LEVEL", "info"),
 		DatabaseURL:    getEnv("DATABASE_URL", ""),
 		JWTSecret:      getEnv("JWT_SECRET", "default-secret-change-in-production"),
 		JWTExpiryHours: getEnvInt("JWT_EXPIRY_HOURS", 24),
+
+		JWTSigningKeys:      getEnv("JWT_SIGNING_KEYS", ""),
+		JWTVerificationKeys: getEnv("JWT_VERIFICATION_KEYS", ""),
+
+		RefreshTokenExpiryHours: getEnvInt("REFRESH_TOKEN_EXPIRY_HOURS", 720),
+
+		AppBaseURL:                   getEnv("APP_BASE_URL", "http://localhost:8080"),
+		RequireEmailVerification:     getEnvBool("REQUIRE_EMAIL_VERIFICATION", true),
+		EmailVerificationExpiryHours: getEnvInt("EMAIL_VERIFICATION_EXPIRY_HOURS", 24),
+		PasswordResetExpiryMinutes:   getEnvInt("PASSWORD_RESET_EXPIRY_MINUTES", 30),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@localhost"),
+		MailLogFile:  getEnv("MAIL_LOG_FILE", ""),
+
+		AttemptStore:                getEnv("ATTEMPT_STORE", "postgres"),
+		LoginMaxFailures:            getEnvInt("LOGIN_MAX_FAILURES", 5),
+		LoginLockoutSeconds:         getEnvInt("LOGIN_LOCKOUT_SECONDS", 60),
+		LoginMaxLockoutMinutes:      getEnvInt("LOGIN_MAX_LOCKOUT_MINUTES", 60),
+		LoginFailureWindowMinutes:   getEnvInt("LOGIN_FAILURE_WINDOW_MINUTES", 15),
+		AuthRateLimitPerIP:          getEnvInt("AUTH_RATE_LIMIT_PER_IP", 20),
+		AuthRateLimitWindowSeconds:  getEnvInt("AUTH_RATE_LIMIT_WINDOW_SECONDS", 60),
+		EmailRateLimitPerAddress:    getEnvInt("EMAIL_RATE_LIMIT_PER_ADDRESS", 5),
+		EmailRateLimitWindowMinutes: getEnvInt("EMAIL_RATE_LIMIT_WINDOW_MINUTES", 60),
+
+		PasswordHashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+		BcryptCost:            getEnvInt("BCRYPT_COST", 10),
+		Argon2MemoryKiB:       getEnvInt("ARGON2_MEMORY_KIB", 64*1024),
+		Argon2Iterations:      getEnvInt("ARGON2_ITERATIONS", 3),
+		Argon2Parallelism:     getEnvInt("ARGON2_PARALLELISM", 2),
+
+		PasswordMinLength:        getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordMaxLength:        getEnvInt("PASSWORD_MAX_LENGTH", 128),
+		PasswordRequireUppercase: getEnvBool("PASSWORD_REQUIRE_UPPERCASE", false),
+		PasswordRequireLowercase: getEnvBool("PASSWORD_REQUIRE_LOWERCASE", false),
+		PasswordRequireDigit:     getEnvBool("PASSWORD_REQUIRE_DIGIT", false),
+		PasswordRequireSymbol:    getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordBlocklistFile:    getEnv("PASSWORD_BLOCKLIST_FILE", ""),
+		BreachedPasswordsDir:     getEnv("BREACHED_PASSWORDS_DIR", ""),
+		BreachedPasswordMinCount: getEnvInt("BREACHED_PASSWORD_MIN_COUNT", 1),
+
+		MFAIssuer:                 getEnv("MFA_ISSUER", "Todolist"),
+		MFAChallengeExpiryMinutes: getEnvInt("MFA_CHALLENGE_EXPIRY_MINUTES", 5),
+		MFAMaxAttempts:            getEnvInt("MFA_MAX_ATTEMPTS", 5),
+		MFAMaxFailures:            getEnvInt("MFA_MAX_FAILURES", 10),
+
+		AccountDeletionGraceDays:    getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 30),
+		AccountPurgeIntervalMinutes: getEnvInt("ACCOUNT_PURGE_INTERVAL_MINUTES", 60),
+	}
+
+	cfg.OIDCProviders = loadOIDCProviders(cfg.AppBaseURL)
+
+	return cfg
+}
+
+// loadOIDCProviders reads OIDC_<NAME>_* settings for every provider named in
+// the comma-separated OIDC_PROVIDERS list
+func loadOIDCProviders(appBaseURL string) []OIDCProviderConfig {
+	providers := make([]OIDCProviderConfig, 0)
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", strings.TrimSuffix(appBaseURL, "/")+"/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		})
 	}
+	return providers
 }
 
 // IsDevelopment returns true if running in development mode
@@ -32,6 +189,11 @@ func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
 
+// IsTest returns true if running in test mode
+func (c *Config) IsTest() bool {
+	return c.Environment == "test"
+}
+
 // IsProduction returns true if running in production mode
 func (c *Config) IsProduction() bool {
 	return c.Environment == "production"
@@ -52,3 +214,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
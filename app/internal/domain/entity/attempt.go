@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrAccountLocked   = NewError(KindTooManyRequests, "account_locked", "Too many failed login attempts; try again later")
+	ErrTooManyRequests = NewError(KindTooManyRequests, "too_many_requests", "Too many requests; try again later")
+)
+
+// RetryAfterError reports a throttled action and when it may be retried.
+// It wraps ErrAccountLocked or ErrTooManyRequests.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v: retry after %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// AttemptCounter counts recent attempts for a throttling key such as an
+// email address or a client IP
+type AttemptCounter struct {
+	Key           string
+	Count         int
+	LastAttemptAt time.Time
+	ExpiresAt     time.Time
+}
+
+// IsExpired returns true if the counter has been forgotten
+func (a *AttemptCounter) IsExpired() bool {
+	return !time.Now().Before(a.ExpiresAt)
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// emailRegex accepts top-level domains of letters, or in punycode for
+// internationalized ones such as .рф (xn--p1ai)
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.([a-zA-Z]{2,}|[xX][nN]--[a-zA-Z0-9-]+)$`)
+
+// NormalizeEmail returns the canonical form of an email address: trimmed,
+// in Unicode NFC, with a lowercase domain. Internationalized domains are
+// converted to punycode. The local part keeps its case since some mail
+// servers treat it as case-sensitive; addresses are compared ignoring case.
+func NormalizeEmail(email string) (string, error) {
+	email = norm.NFC.String(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "", ErrInvalidEmail
+	}
+	local, domain := email[:at], strings.ToLower(email[at+1:])
+
+	if !isASCII(domain) {
+		ascii, err := idna.Lookup.ToASCII(domain)
+		if err != nil {
+			return "", ErrInvalidEmail
+		}
+		domain = ascii
+	}
+
+	normalized := local + "@" + domain
+	if err := ValidateEmail(normalized); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// ValidateEmail checks if an email address is well formed
+func ValidateEmail(email string) error {
+	if !emailRegex.MatchString(email) {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
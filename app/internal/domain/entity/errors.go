@@ -0,0 +1,84 @@
+package entity
+
+// ErrorKind classifies a domain error by what went wrong. Adapters use it as
+// a hint for the status they report, such as 404 for KindNotFound over HTTP.
+type ErrorKind string
+
+const (
+	KindInvalid         ErrorKind = "invalid"
+	KindUnauthorized    ErrorKind = "unauthorized"
+	KindForbidden       ErrorKind = "forbidden"
+	KindNotFound        ErrorKind = "not_found"
+	KindConflict        ErrorKind = "conflict"
+	KindTooManyRequests ErrorKind = "too_many_requests"
+	KindInternal        ErrorKind = "internal"
+)
+
+// Error is a domain error that clients may see: Code is a stable
+// machine-readable identifier and Message a human-readable explanation.
+// Errors that never leave the domain, such as a repository reporting a
+// missing row that the service handles, stay plain errors.
+//
+// Sentinels are compared with errors.Is, which also matches copies made
+// with the With* methods.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	// Details carries optional structured information for clients
+	Details any
+
+	// parent is the sentinel this error was derived from
+	parent *Error
+}
+
+// NewError creates a domain error
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error this one was derived from, if any
+func (e *Error) Unwrap() error {
+	if e.parent == nil {
+		return nil
+	}
+	return e.parent
+}
+
+// WithMessage returns a copy of the error with a more specific message
+func (e *Error) WithMessage(message string) *Error {
+	derived := e.derive()
+	derived.Message = message
+	return derived
+}
+
+// WithDetails returns a copy of the error carrying details for clients
+func (e *Error) WithDetails(details any) *Error {
+	derived := e.derive()
+	derived.Details = details
+	return derived
+}
+
+// WithKind returns a copy of the error reclassified for a context where it
+// means something else, such as a wrong code failing a sign-in
+func (e *Error) WithKind(kind ErrorKind) *Error {
+	derived := e.derive()
+	derived.Kind = kind
+	return derived
+}
+
+func (e *Error) derive() *Error {
+	derived := *e
+	derived.parent = e
+	return &derived
+}
+
+// Errors shared by every part of the domain
+var (
+	ErrValidation = NewError(KindInvalid, "validation_error", "The request is invalid")
+	ErrInternal   = NewError(KindInternal, "internal_error", "An unexpected error occurred")
+)
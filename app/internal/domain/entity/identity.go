@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdentityProviderNotFound = NewError(KindNotFound, "provider_not_found", "Identity provider not found")
+	ErrIdentityNotFound         = NewError(KindNotFound, "identity_not_found", "No identity linked for this provider")
+	ErrIdentityAlreadyLinked    = NewError(KindConflict, "identity_already_linked", "This identity is already linked to an account")
+	ErrProviderAlreadyLinked    = NewError(KindConflict, "provider_already_linked", "Your account is already linked to this provider")
+	ErrLastSignInMethod         = NewError(KindConflict, "last_sign_in_method", "Set a password before unlinking your only identity provider")
+	ErrOIDCAuthRequestNotFound  = errors.New("oidc authorization request not found")
+	ErrInvalidOIDCState         = NewError(KindInvalid, "invalid_oidc_state", "Sign-in request is invalid or expired, please start again")
+	ErrOIDCAuthFailed           = NewError(KindUnauthorized, "oidc_auth_failed", "Sign-in with the identity provider failed")
+	ErrOIDCEmailNotVerified     = NewError(KindForbidden, "email_not_verified", "The identity provider has not verified your email address")
+	ErrOIDCAccountExists        = NewError(KindConflict, "account_exists", "An account with this email already exists; log in with your password and link the provider from your account")
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider
+type UserIdentity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string // the provider's stable user identifier (sub claim)
+	Email     string // email reported by the provider when the link was made
+	CreatedAt time.Time
+}
+
+// NewUserIdentity creates a link between a user and an external identity
+func NewUserIdentity(userID string, external *ExternalIdentity) *UserIdentity {
+	return &UserIdentity{
+		UserID:    userID,
+		Provider:  external.Provider,
+		Subject:   external.Subject,
+		Email:     external.Email,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ExternalIdentity is the user an identity provider authenticated, taken from
+// a validated ID token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// OIDCAuthRequest tracks an authorization request sent to an identity provider
+// until its callback arrives. Only the hash of the state is stored; the nonce
+// and PKCE code verifier are needed in clear to complete the flow.
+type OIDCAuthRequest struct {
+	ID           string
+	Provider     string
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	// LinkUserID is set when a signed-in user links the identity to their account
+	LinkUserID *string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UsedAt     *time.Time
+}
+
+// NewOIDCAuthRequest creates an authorization request that expires after ttl
+func NewOIDCAuthRequest(provider, stateHash, nonce, codeVerifier string, ttl time.Duration) *OIDCAuthRequest {
+	now := time.Now()
+	return &OIDCAuthRequest{
+		Provider:     provider,
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+}
+
+// IsExpired returns true if the request is past its expiry time
+func (r *OIDCAuthRequest) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// IsUsed returns true if the request has already been completed
+func (r *OIDCAuthRequest) IsUsed() bool {
+	return r.UsedAt != nil
+}
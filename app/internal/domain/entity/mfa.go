@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrTOTPNotFound         = errors.New("totp factor not found")
+	ErrMFAAlreadyEnabled    = NewError(KindConflict, "mfa_already_enabled", "Two-factor authentication is already enabled")
+	ErrMFANotEnabled        = NewError(KindInvalid, "mfa_not_enabled", "Two-factor authentication is not enabled")
+	ErrInvalidMFACode       = NewError(KindInvalid, "invalid_mfa_code", "Invalid authentication code")
+	ErrMFACodeReused        = errors.New("two-factor authentication code already used")
+	ErrInvalidMFAChallenge  = NewError(KindUnauthorized, "invalid_mfa_challenge", "MFA challenge is invalid or expired, please log in again")
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+	ErrRecoveryCodeUsed     = errors.New("recovery code already used")
+)
+
+// TOTPFactor is a user's time-based one-time password authenticator.
+// It only protects logins once the user has confirmed it with a valid code.
+type TOTPFactor struct {
+	UserID string
+	Secret string // base32 encoded shared secret
+	// LastUsedStep is the latest time step a code was accepted for; codes for
+	// it or earlier steps are rejected to prevent replay
+	LastUsedStep int64
+	ConfirmedAt  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewTOTPFactor creates an unconfirmed TOTP factor with the given secret
+func NewTOTPFactor(userID, secret string) *TOTPFactor {
+	now := time.Now()
+	return &TOTPFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsConfirmed returns true if the user finished enrolling the factor
+func (f *TOTPFactor) IsConfirmed() bool {
+	return f.ConfirmedAt != nil
+}
+
+// Confirm marks the factor as enrolled
+func (f *TOTPFactor) Confirm() {
+	now := time.Now()
+	f.ConfirmedAt = &now
+	f.UpdatedAt = now
+}
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the
+// authenticator is unavailable. Only the hash of the code is stored.
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewRecoveryCode creates a new unused recovery code
+func NewRecoveryCode(userID, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		UserID:    userID,
+		CodeHash:  codeHash,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsUsed returns true if the code has already been consumed
+func (c *RecoveryCode) IsUsed() bool {
+	return c.UsedAt != nil
+}
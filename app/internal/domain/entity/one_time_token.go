@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrOneTimeTokenNotFound     = errors.New("one-time token not found")
+	ErrOneTimeTokenUsed         = errors.New("one-time token already used")
+	ErrInvalidVerificationToken = NewError(KindInvalid, "invalid_verification_token", "Verification link is invalid or expired")
+	ErrInvalidResetToken        = NewError(KindInvalid, "invalid_reset_token", "Password reset link is invalid or expired")
+	ErrEmailChangeNotConfirmed  = NewError(KindInvalid, "email_change_not_confirmed", "Confirm the email change on the page the emailed link opens")
+)
+
+// TokenPurpose identifies what a one-time token may be used for
+type TokenPurpose string
+
+const (
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeMFAChallenge      TokenPurpose = "mfa_challenge"
+)
+
+// OneTimeToken represents a single-use token sent to a user out of band, e.g. by email.
+// Only the hash of the token value is stored.
+type OneTimeToken struct {
+	ID        string
+	UserID    string
+	Purpose   TokenPurpose
+	Email     string // address the token was sent to
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewOneTimeToken creates a new one-time token that expires after ttl
+func NewOneTimeToken(userID string, purpose TokenPurpose, email, tokenHash string, ttl time.Duration) *OneTimeToken {
+	now := time.Now()
+	return &OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired returns true if the token is past its expiry time
+func (t *OneTimeToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsUsed returns true if the token has already been consumed
+func (t *OneTimeToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
@@ -0,0 +1,207 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrWeakPassword = NewError(KindInvalid, "weak_password", "Password does not meet the password policy")
+
+// Password policy violation codes
+const (
+	ViolationTooShort         = "too_short"
+	ViolationTooLong          = "too_long"
+	ViolationMissingUppercase = "missing_uppercase"
+	ViolationMissingLowercase = "missing_lowercase"
+	ViolationMissingDigit     = "missing_digit"
+	ViolationMissingSymbol    = "missing_symbol"
+	ViolationCommonPassword   = "common_password"
+	ViolationSimilarToEmail   = "similar_to_email"
+	ViolationBreached         = "breached"
+)
+
+// PasswordViolation describes one way a password breaks the policy
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every violation of a rejected password.
+// It wraps ErrWeakPassword.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	codes := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		codes = append(codes, v.Code)
+	}
+	return fmt.Sprintf("%v: %s", ErrWeakPassword, strings.Join(codes, ", "))
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// PasswordPolicy defines the rules new passwords must follow.
+// Lengths are counted in characters (runes), not bytes.
+type PasswordPolicy struct {
+	MinLength        int
+	MaxLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// Blocklist holds additional rejected passwords on top of the built-in common passwords
+	Blocklist []string
+	// RejectEmailSimilarity rejects passwords that contain the user's email or its local part
+	RejectEmailSimilarity bool
+}
+
+// DefaultPasswordPolicy returns the policy used when nothing else is configured
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:             8,
+		MaxLength:             128,
+		RejectEmailSimilarity: true,
+	}
+}
+
+// Validate checks a password for the user with the given email and returns
+// a *PasswordPolicyError listing every violation, or nil
+func (p PasswordPolicy) Validate(password, email string) error {
+	if violations := p.Check(password, email); len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Check returns every violation of the policy by password
+func (p PasswordPolicy) Check(password, email string) []PasswordViolation {
+	violations := make([]PasswordViolation, 0)
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters", p.MinLength),
+		})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationTooLong,
+			Message: fmt.Sprintf("Password must be at most %d characters", p.MaxLength),
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUppercase && !hasUpper {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationMissingUppercase,
+			Message: "Password must contain an uppercase letter",
+		})
+	}
+	if p.RequireLowercase && !hasLower {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationMissingLowercase,
+			Message: "Password must contain a lowercase letter",
+		})
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationMissingDigit,
+			Message: "Password must contain a digit",
+		})
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationMissingSymbol,
+			Message: "Password must contain a symbol",
+		})
+	}
+
+	if p.isBlocked(password) {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationCommonPassword,
+			Message: "Password is too common",
+		})
+	}
+
+	if p.RejectEmailSimilarity && isSimilarToEmail(password, email) {
+		violations = append(violations, PasswordViolation{
+			Code:    ViolationSimilarToEmail,
+			Message: "Password must not contain your email address",
+		})
+	}
+
+	return violations
+}
+
+// isBlocked reports whether password is a common or blocklisted password, ignoring case
+func (p PasswordPolicy) isBlocked(password string) bool {
+	lower := strings.ToLower(password)
+	if _, ok := commonPasswords[lower]; ok {
+		return true
+	}
+	for _, blocked := range p.Blocklist {
+		if strings.ToLower(blocked) == lower {
+			return true
+		}
+	}
+	return false
+}
+
+// isSimilarToEmail reports whether password contains the email or its local part
+func isSimilarToEmail(password, email string) bool {
+	if email == "" {
+		return false
+	}
+
+	lowerPassword := strings.ToLower(password)
+	lowerEmail := strings.ToLower(email)
+	if strings.Contains(lowerPassword, lowerEmail) {
+		return true
+	}
+
+	// Very short local parts would match too many unrelated passwords
+	local, _, _ := strings.Cut(lowerEmail, "@")
+	return utf8.RuneCountInString(local) >= 4 && strings.Contains(lowerPassword, local)
+}
+
+// commonPasswords are rejected by every policy
+var commonPasswords = toSet(
+	"123456", "123456789", "12345678", "1234567890", "12345", "1234567", "123123",
+	"111111", "000000", "654321", "666666", "121212", "112233", "987654321",
+	"password", "password1", "password12", "password123", "password1234", "passw0rd", "p@ssw0rd",
+	"qwerty", "qwerty123", "qwertyuiop", "1q2w3e4r", "1q2w3e4r5t", "1qaz2wsx", "zaq12wsx",
+	"abc123", "abcd1234", "a1b2c3d4", "asdfghjkl", "asdf1234", "iloveyou", "welcome",
+	"welcome1", "welcome123", "letmein", "letmein1", "admin", "admin123", "administrator",
+	"monkey", "dragon", "football", "baseball", "superman", "batman", "trustno1",
+	"sunshine", "princess", "starwars", "whatever", "shadow", "master", "michael",
+	"changeme", "secret", "default", "login", "test1234", "testtest", "guest",
+	"qazwsxedc", "zxcvbnm", "zxcvbnm123", "11111111", "88888888", "12341234",
+	"todolist", "todolist123",
+)
+
+func toSet(values ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
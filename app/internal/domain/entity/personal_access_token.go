@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPersonalAccessTokenNotFound = NewError(KindNotFound, "token_not_found", "Personal access token not found")
+	ErrInvalidTokenScope           = NewError(KindInvalid, "invalid_scope", "Scopes must be one or more of: "+strings.Join(PersonalAccessTokenScopes, ", "))
+	ErrInvalidTokenExpiry          = NewError(KindInvalid, "validation_error", "Expiry must not be negative")
+	ErrTooManyPersonalAccessTokens = NewError(KindConflict, "too_many_tokens", "Revoke an existing personal access token before creating another")
+)
+
+// Scopes a personal access token may be granted
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+)
+
+// PersonalAccessTokenScopes lists every scope a personal access token may be granted
+var PersonalAccessTokenScopes = []string{ScopeTodosRead, ScopeTodosWrite}
+
+// PersonalAccessToken is a long-lived credential a user mints for scripts and
+// integrations. Only the hash of the token is stored; it grants nothing beyond
+// its scopes.
+type PersonalAccessToken struct {
+	ID         string
+	UserID     string
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  *time.Time // nil for tokens that never expire
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// NewPersonalAccessToken creates a new personal access token.
+// A zero ttl creates a token that never expires.
+func NewPersonalAccessToken(userID, name, tokenHash string, scopes []string, ttl time.Duration) (*PersonalAccessToken, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidTokenScope
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(PersonalAccessTokenScopes, scope) {
+			return nil, ErrInvalidTokenScope.WithDetails(map[string]any{
+				"scope":          scope,
+				"allowed_scopes": PersonalAccessTokenScopes,
+			})
+		}
+	}
+	if ttl < 0 {
+		return nil, ErrInvalidTokenExpiry
+	}
+
+	now := time.Now()
+	token := &PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+
+	return token, nil
+}
+
+// IsExpired returns true if the token has an expiry time and is past it
+func (t *PersonalAccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// IsRevoked returns true if the user has revoked the token
+func (t *PersonalAccessToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// HasScope returns true if the token was granted the scope
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
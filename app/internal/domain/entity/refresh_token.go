@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = NewError(KindUnauthorized, "invalid_refresh_token", "Refresh token is invalid or expired")
+	ErrRefreshTokenExpired = NewError(KindUnauthorized, "invalid_refresh_token", "Refresh token is invalid or expired")
+	ErrRefreshTokenReused  = NewError(KindUnauthorized, "refresh_token_reused", "Refresh token was already used; please login again")
+)
+
+// RefreshToken represents an opaque, single-use refresh token.
+// Tokens issued by rotating one another share a FamilyID so that a replayed
+// token can revoke every descendant at once.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// NewRefreshToken creates a new refresh token that expires after ttl
+func NewRefreshToken(userID, familyID, tokenHash string, ttl time.Duration) *RefreshToken {
+	now := time.Now()
+	return &RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired returns true if the token is past its expiry time
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsUsed returns true if the token has already been exchanged
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+// IsRevoked returns true if the token or its family has been revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"slices"
+)
+
+var (
+	ErrInvalidRole      = NewError(KindInvalid, "invalid_role", "Role must be user or admin")
+	ErrPermissionDenied = NewError(KindForbidden, "forbidden", "You do not have permission to access this resource")
+)
+
+// Role determines what a user may do beyond managing their own data
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// Permission names an action restricted to some roles
+type Permission string
+
+const (
+	// PermissionManageUsers allows listing, disabling and deleting other users
+	PermissionManageUsers Permission = "users:manage"
+)
+
+// rolePermissions lists the permissions granted to each role
+var rolePermissions = map[Role][]Permission{
+	RoleUser:  {},
+	RoleAdmin: {PermissionManageUsers},
+}
+
+// ParseRole converts a role name to a Role
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if _, ok := rolePermissions[role]; !ok {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// HasPermission returns true if the role grants the permission
+func (r Role) HasPermission(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+var ErrSessionNotFound = NewError(KindNotFound, "session_not_found", "Session not found")
+
+// Session is a signed-in device. It lives as long as the refresh token family
+// started by the login, whose family ID doubles as the session ID, and access
+// tokens name it in their sid claim.
+type Session struct {
+	ID          string
+	UserID      string
+	DeviceLabel string // human-readable description of the client, such as "Firefox on Linux"
+	UserAgent   string
+	IPAddress   string // address of the most recent sign-in or refresh
+	CreatedAt   time.Time
+	LastSeenAt  time.Time
+	RevokedAt   *time.Time
+}
+
+// NewSession creates a session for a new sign-in from the given client
+func NewSession(userID, userAgent, ipAddress string) *Session {
+	now := time.Now()
+	return &Session{
+		UserID:      userID,
+		DeviceLabel: DeviceLabel(userAgent),
+		UserAgent:   userAgent,
+		IPAddress:   ipAddress,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+	}
+}
+
+// IsRevoked returns true if the session was signed out
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// browsers and operating systems recognized in user agents, in match order.
+// Edge and Chrome also claim to be Safari, so they come first.
+var (
+	userAgentBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"Wget/", "Wget"},
+		{"python-requests/", "Python"},
+		{"Go-http-client/", "Go"},
+		{"okhttp/", "OkHttp"},
+	}
+	userAgentSystems = []struct{ token, name string }{
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DeviceLabel describes the client behind a user agent, such as "Chrome on macOS"
+func DeviceLabel(userAgent string) string {
+	browser := ""
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	system := ""
+	for _, s := range userAgentSystems {
+		if strings.Contains(userAgent, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "Unknown device"
+	}
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrTagNotFound     = NewError(KindNotFound, "tag_not_found", "Tag not found")
+	ErrTagExists       = NewError(KindConflict, "tag_exists", "A tag with this name already exists; merge the tags instead")
+	ErrTagNameRequired = NewError(KindInvalid, "tag_name_required", "Tag name is required")
+	ErrTagNameTooLong  = NewError(KindInvalid, "tag_name_too_long", "Tag names must be 50 characters or less")
+	ErrInvalidTagColor = NewError(KindInvalid, "invalid_tag_color", "Tag color must be a hex color such as #1e88e5")
+	ErrTagMergeSelf    = NewError(KindInvalid, "tag_merge_self", "A tag cannot be merged into itself")
+)
+
+// MaxTagNameLength is the maximum number of characters allowed in a tag name
+const MaxTagNameLength = 50
+
+// DefaultTagColor is the color of tags created without one, including tags
+// created by labelling a todo with a new name
+const DefaultTagColor = "#6b7280"
+
+var tagColorRegex = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// Tag is a label a user puts on their todos. Todos refer to their tags by
+// name, which is unique per user.
+type Tag struct {
+	ID     string
+	UserID string
+	Name   string
+	Color  string
+	// TodoCount is the number of todos labelled with the tag when it was loaded
+	TodoCount int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTag creates a new tag with validation; an empty color uses the default
+func NewTag(userID, name, color string) (*Tag, error) {
+	now := time.Now()
+	tag := &Tag{
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tag.Rename(name); err != nil {
+		return nil, err
+	}
+	if color == "" {
+		color = DefaultTagColor
+	}
+	if err := tag.SetColor(color); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// Rename changes the tag name after trimming and validating it
+func (t *Tag) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if err := ValidateTagName(name); err != nil {
+		return err
+	}
+	t.Name = name
+	return nil
+}
+
+// SetColor changes the tag color; colors are stored in lowercase
+func (t *Tag) SetColor(color string) error {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if !tagColorRegex.MatchString(color) {
+		return ErrInvalidTagColor
+	}
+	t.Color = color
+	return nil
+}
+
+// ValidateTagName checks a trimmed tag name
+func ValidateTagName(name string) error {
+	if name == "" {
+		return ErrTagNameRequired
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return ErrTagNameTooLong
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrTodoNotFound    = NewError(KindNotFound, "todo_not_found", "Todo not found")
+	ErrTitleRequired   = NewError(KindInvalid, "title_required", "Title is required")
+	ErrTitleTooLong    = NewError(KindInvalid, "title_too_long", "Title must be 500 characters or less")
+	ErrInvalidPriority = NewError(KindInvalid, "invalid_priority", "Priority must be one of: low, medium, high")
+	ErrInvalidStatus   = NewError(KindInvalid, "invalid_status", "Status must be one of: pending, in_progress, blocked, completed, cancelled")
+
+	ErrInvalidStatusTransition = NewError(KindConflict, "invalid_status_transition", "This status change is not allowed")
+	ErrTodoNotClosed           = NewError(KindConflict, "todo_not_closed", "Only completed or cancelled todos can be reopened")
+	ErrInvalidCursor           = NewError(KindInvalid, "invalid_cursor", "Cursor is invalid or belongs to a different sort order")
+)
+
+// MaxTitleLength is the maximum number of characters allowed in a todo title
+const MaxTitleLength = 500
+
+// todoCodeRegex matches short todo codes: the two-digit year a todo was
+// created in and its sequence number within that year, e.g. 26-0042
+var todoCodeRegex = regexp.MustCompile(`^[0-9]{2}-[0-9]{4,}$`)
+
+// FormatTodoCode returns the short code of the seq-th todo a user created in the given year
+func FormatTodoCode(year, seq int) string {
+	return fmt.Sprintf("%02d-%04d", year%100, seq)
+}
+
+// IsTodoCode reports whether s is a short todo code rather than a todo ID
+func IsTodoCode(s string) bool {
+	return todoCodeRegex.MatchString(s)
+}
+
+// Priority represents the importance of a todo
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+)
+
+// IsValid returns true if the priority is a known value
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rank orders priorities from low to high
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityMedium:
+		return 2
+	case PriorityHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Status represents the progress of a todo
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusInProgress Status = "in_progress"
+	StatusBlocked    Status = "blocked"
+	StatusCompleted  Status = "completed"
+	StatusCancelled  Status = "cancelled"
+)
+
+// statusTransitions lists the statuses a todo may move to from each status.
+// Closed todos may only leave their status by being reopened.
+var statusTransitions = map[Status][]Status{
+	StatusPending:    {StatusInProgress, StatusBlocked, StatusCompleted, StatusCancelled},
+	StatusInProgress: {StatusPending, StatusBlocked, StatusCompleted, StatusCancelled},
+	StatusBlocked:    {StatusPending, StatusInProgress, StatusCancelled},
+	StatusCompleted:  {},
+	StatusCancelled:  {},
+}
+
+// IsValid returns true if the status is a known value
+func (s Status) IsValid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// IsClosed returns true if no more work is expected on a todo with this status
+func (s Status) IsClosed() bool {
+	return s == StatusCompleted || s == StatusCancelled
+}
+
+// CanTransitionTo returns true if a todo may move from this status to next
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(statusTransitions[s], next)
+}
+
+// TodoStatusChange records a todo moving from one status to another.
+// The first change of a todo has no FromStatus.
+type TodoStatusChange struct {
+	ID         string
+	TodoID     string
+	FromStatus Status
+	ToStatus   Status
+	ChangedAt  time.Time
+}
+
+// Todo represents a task owned by a user
+type Todo struct {
+	ID          string
+	Code        string
+	UserID      string
+	Title       string
+	Description *string
+	Status      Status
+	Priority    Priority
+	DueDate     *time.Time
+	Tags        []string
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewTodo creates a new pending todo with validation
+func NewTodo(userID, title string) (*Todo, error) {
+	todo := &Todo{
+		UserID:    userID,
+		Title:     strings.TrimSpace(title),
+		Status:    StatusPending,
+		Priority:  PriorityMedium,
+		Tags:      []string{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := todo.Validate(); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// CodeYear returns the year whose sequence the todo's short code is allocated from
+func (t *Todo) CodeYear() int {
+	return t.CreatedAt.UTC().Year()
+}
+
+// AssignCode sets the todo's short code from its sequence number within its code year
+func (t *Todo) AssignCode(seq int) {
+	t.Code = FormatTodoCode(t.CodeYear(), seq)
+}
+
+// Validate checks if the todo meets all business rules
+func (t *Todo) Validate() error {
+	if t.Title == "" {
+		return ErrTitleRequired
+	}
+	if utf8.RuneCountInString(t.Title) > MaxTitleLength {
+		return ErrTitleTooLong
+	}
+	if !t.Priority.IsValid() {
+		return ErrInvalidPriority
+	}
+	if !t.Status.IsValid() {
+		return ErrInvalidStatus
+	}
+	for _, tag := range t.Tags {
+		if err := ValidateTagName(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitialStatusChange returns the change that records the status a todo was created with
+func (t *Todo) InitialStatusChange() *TodoStatusChange {
+	return &TodoStatusChange{
+		TodoID:    t.ID,
+		ToStatus:  t.Status,
+		ChangedAt: t.CreatedAt,
+	}
+}
+
+// TransitionTo moves the todo to the next status if the move is allowed,
+// stamping CompletedAt when it is completed. It returns the change to
+// record, or nil when the todo already has that status.
+func (t *Todo) TransitionTo(next Status) (*TodoStatusChange, error) {
+	if !next.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	if next == t.Status {
+		return nil, nil
+	}
+	if !t.Status.CanTransitionTo(next) {
+		return nil, ErrInvalidStatusTransition.
+			WithMessage(fmt.Sprintf("Cannot change status from %s to %s", t.Status, next)).
+			WithDetails(map[string]any{
+				"from":    t.Status,
+				"to":      next,
+				"allowed": statusTransitions[t.Status],
+			})
+	}
+
+	return t.changeStatus(next), nil
+}
+
+// Reopen moves a completed or cancelled todo back to pending
+func (t *Todo) Reopen() (*TodoStatusChange, error) {
+	if !t.Status.IsClosed() {
+		return nil, ErrTodoNotClosed
+	}
+	return t.changeStatus(StatusPending), nil
+}
+
+// changeStatus sets the status and returns the change to record
+func (t *Todo) changeStatus(next Status) *TodoStatusChange {
+	now := time.Now()
+	change := &TodoStatusChange{
+		TodoID:     t.ID,
+		FromStatus: t.Status,
+		ToStatus:   next,
+		ChangedAt:  now,
+	}
+
+	t.Status = next
+	if next == StatusCompleted {
+		t.CompletedAt = &now
+	} else {
+		t.CompletedAt = nil
+	}
+
+	return change
+}
+
+// SetTags replaces the todo tags, trimming whitespace and dropping blanks and duplicates
+func (t *Todo) SetTags(tags []string) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	t.Tags = normalized
+}
+
+// HasTag returns true if the todo is labelled with the given tag
+func (t *Todo) HasTag(tag string) bool {
+	for _, existing := range t.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOverdue returns true if the todo has passed its due date and is still open
+func (t *Todo) IsOverdue() bool {
+	if t.DueDate == nil {
+		return false
+	}
+	return time.Now().After(*t.DueDate) && !t.Status.IsClosed()
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"strings"
+)
+
+var (
+	ErrSearchQueryRequired   = NewError(KindInvalid, "search_query_required", "Search query is required")
+	ErrInvalidSearchLanguage = NewError(KindInvalid, "invalid_search_language", "Language must be one of: en, vi")
+)
+
+// SearchLanguage selects how todo text is split into words and matched
+type SearchLanguage string
+
+const (
+	// SearchLanguageEnglish stems words, so "taxes" also matches "tax"
+	SearchLanguageEnglish SearchLanguage = "en"
+	// SearchLanguageVietnamese ignores diacritics, so "ha noi" also matches "Hà Nội"
+	SearchLanguageVietnamese SearchLanguage = "vi"
+)
+
+// IsValid returns true if the search language is a known value
+func (l SearchLanguage) IsValid() bool {
+	switch l {
+	case SearchLanguageEnglish, SearchLanguageVietnamese:
+		return true
+	default:
+		return false
+	}
+}
+
+// SearchTerm is one part of a search query. A todo matches a query when it
+// matches every term.
+type SearchTerm struct {
+	// Text is a single word, or the words of a phrase
+	Text string
+	// Phrase requires the words to appear next to each other and in order
+	Phrase bool
+	// Prefix matches any word that starts with the text
+	Prefix bool
+}
+
+// ParseSearchQuery splits a search query into terms. Text in double quotes
+// is a phrase, and a word ending in * matches any word it starts.
+func ParseSearchQuery(query string) ([]SearchTerm, error) {
+	var terms []SearchTerm
+
+	// Every other part lies between quotes; an unclosed quote runs to the end
+	for i, part := range strings.Split(query, `"`) {
+		words := strings.Fields(part)
+		if i%2 == 1 {
+			if len(words) > 0 {
+				terms = append(terms, SearchTerm{Text: strings.Join(words, " "), Phrase: true})
+			}
+			continue
+		}
+
+		for _, word := range words {
+			text := strings.TrimRight(word, "*")
+			if text == "" {
+				continue
+			}
+			terms = append(terms, SearchTerm{Text: text, Prefix: text != word})
+		}
+	}
+
+	if len(terms) == 0 {
+		return nil, ErrSearchQueryRequired
+	}
+	return terms, nil
+}
+
+// TodoSearchResult is a todo that matched a search, with its relevance and
+// the parts of its text that matched
+type TodoSearchResult struct {
+	Todo *Todo
+	// Rank orders results by relevance; matches in the title count most,
+	// then tags, then the description
+	Rank float64
+	// TitleHighlight and DescriptionHighlight are HTML-escaped text with
+	// matched words wrapped in <mark> tags. A highlight is empty when its
+	// field did not match.
+	TitleHighlight       string
+	DescriptionHighlight string
+}
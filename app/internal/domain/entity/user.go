@@ -1,47 +1,103 @@
 package entity
 
 import (
-	"errors"
-	"regexp"
 	"time"
 )
 
 var (
-	ErrInvalidEmail    = errors.New("invalid email format")
-	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
-	ErrUserNotFound    = errors.New("user not found")
-	ErrEmailExists     = errors.New("email already exists")
-	ErrInvalidPassword = errors.New("invalid password")
+	ErrInvalidEmail       = NewError(KindInvalid, "invalid_email", "Invalid email format")
+	ErrUserNotFound       = NewError(KindNotFound, "user_not_found", "User not found")
+	ErrEmailExists        = NewError(KindConflict, "email_exists", "Email already registered")
+	ErrInvalidCredentials = NewError(KindUnauthorized, "invalid_credentials", "Invalid email or password")
+	ErrInvalidPassword    = NewError(KindForbidden, "invalid_password", "Current password is incorrect")
+	ErrEmailNotVerified   = NewError(KindForbidden, "email_not_verified", "Please verify your email address before logging in")
+	ErrAccountDisabled    = NewError(KindForbidden, "account_disabled", "This account has been disabled")
+	ErrCannotModifySelf   = NewError(KindConflict, "cannot_modify_self", "Administrators cannot change, disable or delete their own account here")
+	ErrUserNotDeleted     = NewError(KindConflict, "user_not_deleted", "Only deleted users can be restored")
 )
 
-var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-
 // User represents a user in the system
 type User struct {
-	ID           string
-	Email        string
-	PasswordHash string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              string
+	Email           string
+	PasswordHash    string
+	Role            Role
+	EmailVerifiedAt *time.Time
+	DisabledAt      *time.Time
+	DeletedAt       *time.Time // set while a deleted account can still be restored
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
-// NewUser creates a new user with validation
+// NewUser creates a new user with validation; the email is normalized
 func NewUser(email string) (*User, error) {
-	if !emailRegex.MatchString(email) {
-		return nil, ErrInvalidEmail
+	normalized, err := NormalizeEmail(email)
+	if err != nil {
+		return nil, err
 	}
 
 	return &User{
-		Email:     email,
+		Email:     normalized,
+		Role:      RoleUser,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}, nil
 }
 
-// ValidatePassword checks if a password meets requirements
-func ValidatePassword(password string) error {
-	if len(password) < 8 {
-		return ErrPasswordTooShort
-	}
-	return nil
+// HasPassword returns false for users who only sign in with an identity provider
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
+// IsEmailVerified returns true if the user has confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// MarkEmailVerified records that the user confirmed their email address
+func (u *User) MarkEmailVerified() {
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+}
+
+// IsDisabled returns true if an administrator has disabled the account
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// Disable blocks the user from signing in
+func (u *User) Disable() {
+	now := time.Now()
+	u.DisabledAt = &now
+	u.UpdatedAt = now
+}
+
+// Enable lets a disabled user sign in again
+func (u *User) Enable() {
+	u.DisabledAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+// IsDeleted returns true if the account was deleted and awaits being purged
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// MarkDeleted deletes the account; it can be restored until it is purged
+func (u *User) MarkDeleted() {
+	now := time.Now()
+	u.DeletedAt = &now
+	u.UpdatedAt = now
+}
+
+// Restore undoes the deletion of the account
+func (u *User) Restore() {
+	u.DeletedAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+// HasPermission returns true if the user's role grants the permission
+func (u *User) HasPermission(permission Permission) bool {
+	return u.Role.HasPermission(permission)
 }
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// AttemptStore defines the interface for counting attempts per throttling key.
+// A counter covers a fixed window of ttl from its first attempt and is
+// forgotten when the window ends; later attempts do not extend it.
+type AttemptStore interface {
+	// Get returns the counter for key, with a zero count if none is live
+	Get(ctx context.Context, key string) (*entity.AttemptCounter, error)
+
+	// Increment atomically records an attempt for key and returns the
+	// updated counter, starting a new window if none is live
+	Increment(ctx context.Context, key string, ttl time.Duration) (*entity.AttemptCounter, error)
+
+	// Reset forgets the counter for key
+	Reset(ctx context.Context, key string) error
+
+	// PurgeExpired deletes the counters whose window has ended and returns
+	// how many were removed
+	PurgeExpired(ctx context.Context) (int, error)
+}
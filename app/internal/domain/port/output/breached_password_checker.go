@@ -0,0 +1,9 @@
+package output
+
+import "context"
+
+// BreachedPasswordChecker defines the interface for checking passwords against known data breaches
+type BreachedPasswordChecker interface {
+	// IsBreached reports whether the password appears in a breach corpus
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
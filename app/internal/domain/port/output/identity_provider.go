@@ -0,0 +1,28 @@
+package output
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// AuthorizationRequest holds the parameters of an authorization code request
+// that protect it against forgery and code interception
+type AuthorizationRequest struct {
+	State         string
+	Nonce         string
+	CodeChallenge string // S256 PKCE challenge
+}
+
+// IdentityProvider defines the interface for an external OpenID Connect provider
+type IdentityProvider interface {
+	// Name identifies the provider in URLs and stored identities
+	Name() string
+
+	// AuthCodeURL returns the URL the user is sent to in order to sign in
+	AuthCodeURL(ctx context.Context, req AuthorizationRequest) (string, error)
+
+	// Exchange redeems an authorization code and returns the user from the
+	// validated ID token, which must carry the given nonce
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.ExternalIdentity, error)
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// UserIdentityRepository defines the interface for external identity persistence
+type UserIdentityRepository interface {
+	// Create stores a new identity link.
+	// Returns entity.ErrIdentityAlreadyLinked if the external subject is linked to
+	// any user and entity.ErrProviderAlreadyLinked if the user already has an
+	// identity at the provider.
+	Create(ctx context.Context, identity *entity.UserIdentity) error
+
+	// GetByProviderSubject retrieves the identity for an external subject.
+	// Returns entity.ErrIdentityNotFound if it is not linked.
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+
+	// ListByUserID returns every identity linked to the user
+	ListByUserID(ctx context.Context, userID string) ([]*entity.UserIdentity, error)
+
+	// Delete removes the user's identity at the provider.
+	// Returns entity.ErrIdentityNotFound if there is none.
+	Delete(ctx context.Context, userID, provider string) error
+}
+
+// OIDCAuthRequestRepository defines the interface for pending OIDC authorization requests
+type OIDCAuthRequestRepository interface {
+	// Create stores a new authorization request
+	Create(ctx context.Context, request *entity.OIDCAuthRequest) error
+
+	// GetByStateHash retrieves a request by the hash of its state parameter.
+	// Returns entity.ErrOIDCAuthRequestNotFound if there is none.
+	GetByStateHash(ctx context.Context, stateHash string) (*entity.OIDCAuthRequest, error)
+
+	// MarkUsed atomically marks a request as completed.
+	// Returns entity.ErrInvalidOIDCState if it was already used.
+	MarkUsed(ctx context.Context, id string) error
+
+	// PurgeExpired deletes the requests that have expired and returns how many were removed
+	PurgeExpired(ctx context.Context) (int, error)
+}
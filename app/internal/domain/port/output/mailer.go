@@ -0,0 +1,16 @@
+package output
+
+import "context"
+
+// Message represents an outgoing plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the interface for sending email
+type Mailer interface {
+	// Send delivers a message
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// TOTPRepository defines the interface for TOTP factor persistence
+type TOTPRepository interface {
+	// Save stores the user's TOTP factor, replacing any existing one
+	Save(ctx context.Context, factor *entity.TOTPFactor) error
+
+	// GetByUserID retrieves the user's TOTP factor.
+	// Returns entity.ErrTOTPNotFound if the user has none.
+	GetByUserID(ctx context.Context, userID string) (*entity.TOTPFactor, error)
+
+	// MarkStepUsed atomically records that a code for step was accepted.
+	// Returns entity.ErrMFACodeReused unless step is newer than the last used step.
+	MarkStepUsed(ctx context.Context, userID string, step int64) error
+
+	// Delete removes the user's TOTP factor
+	Delete(ctx context.Context, userID string) error
+}
+
+// RecoveryCodeRepository defines the interface for MFA recovery code persistence
+type RecoveryCodeRepository interface {
+	// ReplaceForUser deletes the user's recovery codes and stores the given ones
+	ReplaceForUser(ctx context.Context, userID string, codes []*entity.RecoveryCode) error
+
+	// GetByHash retrieves one of the user's recovery codes by its hash.
+	// Returns entity.ErrRecoveryCodeNotFound if there is none.
+	GetByHash(ctx context.Context, userID, codeHash string) (*entity.RecoveryCode, error)
+
+	// MarkUsed atomically marks a recovery code as consumed.
+	// Returns entity.ErrRecoveryCodeUsed if it was already used.
+	MarkUsed(ctx context.Context, id string) error
+
+	// CountUnused returns how many of the user's recovery codes are still usable
+	CountUnused(ctx context.Context, userID string) (int, error)
+
+	// DeleteForUser deletes every recovery code of the user
+	DeleteForUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,26 @@
+package output
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// OneTimeTokenRepository defines the interface for one-time token persistence
+type OneTimeTokenRepository interface {
+	// Create stores a new one-time token
+	Create(ctx context.Context, token *entity.OneTimeToken) error
+
+	// GetByHash retrieves a token with the given purpose by the hash of its value
+	GetByHash(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (*entity.OneTimeToken, error)
+
+	// MarkUsed atomically marks a token as consumed.
+	// Returns entity.ErrOneTimeTokenUsed if it was already used.
+	MarkUsed(ctx context.Context, id string) error
+
+	// DeleteForUser deletes every token with the given purpose belonging to the user
+	DeleteForUser(ctx context.Context, userID string, purpose entity.TokenPurpose) error
+
+	// PurgeExpired deletes the tokens that have expired and returns how many were removed
+	PurgeExpired(ctx context.Context) (int, error)
+}
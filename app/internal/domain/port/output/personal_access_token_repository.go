@@ -0,0 +1,31 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// PersonalAccessTokenRepository defines the interface for personal access token persistence
+type PersonalAccessTokenRepository interface {
+	// Create stores a new personal access token
+	Create(ctx context.Context, token *entity.PersonalAccessToken) error
+
+	// GetByHash retrieves a token by the hash of its value.
+	// Returns entity.ErrPersonalAccessTokenNotFound if there is none.
+	GetByHash(ctx context.Context, tokenHash string) (*entity.PersonalAccessToken, error)
+
+	// ListByUserID returns the user's tokens that have not been revoked
+	ListByUserID(ctx context.Context, userID string) ([]*entity.PersonalAccessToken, error)
+
+	// Revoke revokes one of the user's tokens.
+	// Returns entity.ErrPersonalAccessTokenNotFound if the user has no such unrevoked token.
+	Revoke(ctx context.Context, userID, id string) error
+
+	// RevokeAllForUser revokes every token belonging to the user
+	RevokeAllForUser(ctx context.Context, userID string) error
+
+	// UpdateLastUsed records when a token was last used to authenticate
+	UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// RefreshTokenRepository defines the interface for refresh token persistence
+type RefreshTokenRepository interface {
+	// Create stores a new refresh token
+	Create(ctx context.Context, token *entity.RefreshToken) error
+
+	// GetByHash retrieves a refresh token by the hash of its value
+	GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+
+	// MarkUsed atomically marks a token as exchanged.
+	// Returns entity.ErrRefreshTokenReused if it was already used.
+	MarkUsed(ctx context.Context, id string) error
+
+	// RevokeFamily revokes every token in a rotation family
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeAllForUser revokes every refresh token belonging to the user
+	RevokeAllForUser(ctx context.Context, userID string) error
+
+	// PurgeExpired deletes the refresh tokens that have expired and returns
+	// how many were removed
+	PurgeExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// SessionRepository defines the interface for signed-in session persistence
+type SessionRepository interface {
+	// Create stores a new session
+	Create(ctx context.Context, session *entity.Session) error
+
+	// GetByID retrieves a session.
+	// Returns entity.ErrSessionNotFound if there is none.
+	GetByID(ctx context.Context, id string) (*entity.Session, error)
+
+	// ListActiveByUserID returns the user's unrevoked sessions seen since the
+	// given time, most recently seen first
+	ListActiveByUserID(ctx context.Context, userID string, seenSince time.Time) ([]*entity.Session, error)
+
+	// Touch records activity on a session. An empty IP address keeps the stored one.
+	Touch(ctx context.Context, id string, seenAt time.Time, ipAddress string) error
+
+	// Revoke signs out one of the user's sessions.
+	// Returns entity.ErrSessionNotFound if the user has no such unrevoked session.
+	Revoke(ctx context.Context, userID, id string) error
+
+	// RevokeAllForUser signs out every session of the user
+	RevokeAllForUser(ctx context.Context, userID string) error
+
+	// PurgeEnded deletes the sessions that were revoked or last seen before
+	// the given time and returns how many were removed
+	PurgeEnded(ctx context.Context, seenBefore time.Time) (int, error)
+}
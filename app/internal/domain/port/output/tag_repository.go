@@ -0,0 +1,34 @@
+package output
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// TagRepository defines the interface for tag persistence. Every lookup is
+// scoped to the owning user ID, and loaded tags carry their todo count.
+//
+// Todos name their tags, so changing a tag also changes the todos labelled
+// with it; each such change is applied atomically.
+type TagRepository interface {
+	// Create creates a new tag; ErrTagExists if the user has one with its name
+	Create(ctx context.Context, tag *entity.Tag) error
+
+	// GetByID retrieves a tag by ID for the given user
+	GetByID(ctx context.Context, userID, id string) (*entity.Tag, error)
+
+	// List retrieves all tags of the given user, ordered by name
+	List(ctx context.Context, userID string) ([]*entity.Tag, error)
+
+	// Update saves the tag's name and color, renaming it on every todo
+	// labelled with it; ErrTagExists if the user has another tag with its name
+	Update(ctx context.Context, tag *entity.Tag) error
+
+	// Merge relabels the todos of the source tag with the target tag and
+	// deletes the source tag
+	Merge(ctx context.Context, userID, sourceID, targetID string) error
+
+	// Delete deletes a tag and removes it from every todo labelled with it
+	Delete(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"context"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// TodoSortField names the field todos are ordered by. Todos with the same
+// value are ordered by ID in the same direction, so every todo has a fixed
+// position that a cursor can point at.
+type TodoSortField string
+
+const (
+	TodoSortCreatedAt TodoSortField = "created_at"
+	TodoSortUpdatedAt TodoSortField = "updated_at"
+	// TodoSortDueDate lists todos without a due date last in either direction
+	TodoSortDueDate TodoSortField = "due_date"
+	// TodoSortPriority orders by rank, so descending lists high priority first
+	TodoSortPriority TodoSortField = "priority"
+)
+
+// IsValid returns true if the sort field is a known value
+func (f TodoSortField) IsValid() bool {
+	switch f {
+	case TodoSortCreatedAt, TodoSortUpdatedAt, TodoSortDueDate, TodoSortPriority:
+		return true
+	default:
+		return false
+	}
+}
+
+// TodoCursor is the position of the last todo of a page; the next page
+// starts after it
+type TodoCursor struct {
+	ID string
+	// Time is the sort value when sorting by a timestamp; nil for a todo
+	// without a due date
+	Time *time.Time
+	// Priority is the sort value when sorting by priority
+	Priority entity.Priority
+}
+
+// TodoFilter selects and orders todos when listing them. Empty fields do not
+// restrict the list; time bounds are exclusive.
+type TodoFilter struct {
+	Statuses   []entity.Status
+	Priorities []entity.Priority
+	// Tags matches todos with any of the tags, or all of them with MatchAllTags
+	Tags         []string
+	MatchAllTags bool
+
+	DueBefore     *time.Time
+	DueAfter      *time.Time
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	// Overdue restricts the list to open todos whose due date has passed
+	Overdue bool
+	// Query matches todos whose title or description contains it, ignoring case
+	Query string
+
+	Sort       TodoSortField
+	Descending bool
+	// After continues a listing after the given position when set
+	After *TodoCursor
+	Limit int
+}
+
+// TodoRepository defines the interface for todo persistence.
+// Every lookup is scoped to the owning user ID and accepts either the
+// todo ID or its short code.
+type TodoRepository interface {
+	// Create creates a new todo and assigns it the next short code of its
+	// owner's code year. Concurrent creates never share a code. Tags the
+	// todo names that the user does not have yet are created. The initial
+	// status change is recorded with the todo, so neither is saved without
+	// the other.
+	Create(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error
+
+	// GetByID retrieves a todo by ID or short code for the given user
+	GetByID(ctx context.Context, userID, id string) (*entity.Todo, error)
+
+	// List returns the user's todos matching the filter, up to its limit,
+	// and the number matching in total regardless of the cursor
+	List(ctx context.Context, userID string, filter TodoFilter) ([]*entity.Todo, int, error)
+
+	// Search returns the user's todos matching a full-text search, most
+	// relevant first, up to its limit
+	Search(ctx context.Context, userID string, search TodoSearch) ([]*entity.TodoSearchResult, error)
+
+	// Update updates an existing todo, creating tags it names like Create.
+	// A non-nil status change is recorded with the update like in Create.
+	Update(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error
+
+	// Delete deletes a todo by ID or short code for the given user
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// TodoSearch describes a full-text search of a user's todos
+type TodoSearch struct {
+	// Terms must all match the title, description or tags
+	Terms    []entity.SearchTerm
+	Language entity.SearchLanguage
+	Limit    int
+}
+
+// TodoStatusHistoryRepository defines the interface for reading todo status
+// history; changes are recorded by TodoRepository together with the todo
+type TodoStatusHistoryRepository interface {
+	// ListByTodoID retrieves the status changes of a todo, oldest first
+	ListByTodoID(ctx context.Context, todoID string) ([]*entity.TodoStatusChange, error)
+}
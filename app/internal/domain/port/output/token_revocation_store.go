@@ -0,0 +1,23 @@
+package output
+
+import (
+	"context"
+	"time"
+)
+
+// TokenRevocationStore defines the interface for tracking revoked access tokens
+type TokenRevocationStore interface {
+	// RevokeToken revokes a single access token by its ID (jti).
+	// The entry only needs to be kept until expiresAt.
+	RevokeToken(ctx context.Context, tokenID, userID string, expiresAt time.Time) error
+
+	// RevokeAllForUser revokes every access token issued to the user before revokedAt
+	RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error
+
+	// IsRevoked reports whether a token with the given ID, owner and issue time is revoked
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+
+	// PurgeExpired deletes the revocations of single tokens that have expired
+	// anyway and returns how many were removed
+	PurgeExpired(ctx context.Context) (int, error)
+}
@@ -2,11 +2,25 @@ package output
 
 import (
 	"context"
+	"time"
 
 	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
 )
 
-// UserRepository defines the interface for user persistence
+// UserFilter selects users when listing them
+type UserFilter struct {
+	// Query matches users whose email contains it, ignoring case
+	Query string
+	// Role restricts the list to users with the role when set
+	Role entity.Role
+	// Deleted lists deleted accounts awaiting purge instead of active ones
+	Deleted bool
+	Limit   int
+	Offset  int
+}
+
+// UserRepository defines the interface for user persistence. Lookups ignore
+// deleted users unless their name says otherwise.
 type UserRepository interface {
 	// Create creates a new user
 	Create(ctx context.Context, user *entity.User) error
@@ -14,12 +28,26 @@ type UserRepository interface {
 	// GetByID retrieves a user by ID
 	GetByID(ctx context.Context, id string) (*entity.User, error)
 
-	// GetByEmail retrieves a user by email
+	// GetByEmail retrieves a user by email, ignoring case
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 
-	// Update updates an existing user
+	// GetDeletedByID retrieves a deleted user that has not been purged yet
+	GetDeletedByID(ctx context.Context, id string) (*entity.User, error)
+
+	// GetDeletedByEmail retrieves a deleted user that has not been purged yet by email
+	GetDeletedByEmail(ctx context.Context, email string) (*entity.User, error)
+
+	// Update updates an existing user, including a deleted one
 	Update(ctx context.Context, user *entity.User) error
 
-	// Delete deletes a user by ID
+	// List returns a page of users matching the filter, oldest first, and the
+	// number of users matching it in total
+	List(ctx context.Context, filter UserFilter) ([]*entity.User, int, error)
+
+	// Delete permanently deletes a user and everything they own by ID
 	Delete(ctx context.Context, id string) error
+
+	// PurgeDeleted permanently deletes the users deleted before the cutoff
+	// and returns how many were removed
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
 }
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+const (
+	// maxPersonalAccessTokens caps the unrevoked tokens a user may hold
+	maxPersonalAccessTokens = 50
+
+	// lastUsedResolution limits how often the last use of a personal access
+	// token or session is written back
+	lastUsedResolution = time.Minute
+)
+
+// CreatePersonalAccessToken mints a named token with the given scopes for the
+// user. A zero ttl creates a token that never expires. The token is returned
+// once; only its hash is stored.
+func (s *AuthService) CreatePersonalAccessToken(ctx context.Context, userID, name string, scopes []string, ttl time.Duration) (string, *entity.PersonalAccessToken, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return "", nil, entity.ErrUserNotFound
+	}
+
+	existing, err := s.accessTokenRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(existing) >= maxPersonalAccessTokens {
+		return "", nil, entity.ErrTooManyPersonalAccessTokens
+	}
+
+	token, tokenHash, err := auth.GeneratePersonalAccessToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	accessToken, err := entity.NewPersonalAccessToken(userID, name, tokenHash, scopes, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	accessToken.ID = uuid.New().String()
+
+	if err := s.accessTokenRepo.Create(ctx, accessToken); err != nil {
+		return "", nil, err
+	}
+
+	return token, accessToken, nil
+}
+
+// ListPersonalAccessTokens returns the user's unrevoked tokens, newest first
+func (s *AuthService) ListPersonalAccessTokens(ctx context.Context, userID string) ([]*entity.PersonalAccessToken, error) {
+	return s.accessTokenRepo.ListByUserID(ctx, userID)
+}
+
+// RevokePersonalAccessToken revokes one of the user's tokens
+func (s *AuthService) RevokePersonalAccessToken(ctx context.Context, userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return entity.ErrPersonalAccessTokenNotFound
+	}
+	return s.accessTokenRepo.Revoke(ctx, userID, id)
+}
+
+// ValidatePersonalAccessToken checks a personal access token presented as a
+// bearer credential. It fails with the same errors as ValidateToken, or with
+// entity.ErrAccountDisabled while the owner is disabled.
+func (s *AuthService) ValidatePersonalAccessToken(ctx context.Context, token string) (*entity.PersonalAccessToken, error) {
+	stored, err := s.accessTokenRepo.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if err == entity.ErrPersonalAccessTokenNotFound {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if stored.IsRevoked() {
+		return nil, auth.ErrRevokedToken
+	}
+	if stored.IsExpired() {
+		return nil, auth.ErrExpiredToken
+	}
+
+	// Tokens stop working while their owner is disabled
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		if err == entity.ErrUserNotFound {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	now := time.Now()
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedResolution {
+		// Usage tracking is informational and must not fail the request
+		if err := s.accessTokenRepo.UpdateLastUsed(ctx, stored.ID, now); err != nil {
+			log.Printf("Failed to record use of personal access token %s: %v", stored.ID, err)
+		}
+		stored.LastUsedAt = &now
+	}
+
+	return stored, nil
+}
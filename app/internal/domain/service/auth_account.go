@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// ChangePassword replaces the password of the signed-in user after checking the
+// current one. Every existing session is revoked and a new one is started so
+// the caller stays signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, claims *auth.Claims, currentPassword, newPassword string, client ClientInfo) (*TokenPair, error) {
+	user, err := s.authenticateUser(ctx, claims.UserID, currentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate password
+	if err := s.validatePassword(ctx, newPassword, user.Email); err != nil {
+		return nil, err
+	}
+
+	// Hash password
+	hashedPassword, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.LogoutAll(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, user, client)
+}
+
+// ChangeEmail starts an email change for the signed-in user by sending a
+// verification link to the new address. The current address stays in use
+// until the link is opened.
+func (s *AuthService) ChangeEmail(ctx context.Context, userID, newEmail, password string) error {
+	user, err := s.authenticateUser(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+
+	newEmail, err = entity.NormalizeEmail(newEmail)
+	if err != nil {
+		return err
+	}
+
+	// Check if email already exists
+	existingUser, _ := s.userRepo.GetByEmail(ctx, newEmail)
+	if existingUser != nil {
+		return entity.ErrEmailExists
+	}
+
+	return s.sendVerificationEmail(ctx, user, newEmail)
+}
+
+// DeleteAccount deletes the signed-in user and everything they own once the
+// deletion grace period ends. Outstanding tokens are revoked first so they
+// cannot outlive the account.
+func (s *AuthService) DeleteAccount(ctx context.Context, claims *auth.Claims, password string) error {
+	user, err := s.authenticateUser(ctx, claims.UserID, password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.LogoutAll(ctx, claims); err != nil {
+		return err
+	}
+
+	return s.deleteUser(ctx, user)
+}
+
+// authenticateUser loads a user and checks their password
+func (s *AuthService) authenticateUser(ctx context.Context, userID, password string) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	// Verify password
+	if err := s.verifyPassword(user, password); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
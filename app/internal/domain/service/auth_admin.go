@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// Page sizes for listing users
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// ListUsersInput holds the search and paging options for listing users
+type ListUsersInput struct {
+	Query string // part of the email address, ignoring case
+	Role  string // only users with this role when set
+	// Deleted lists deleted accounts awaiting purge instead of active ones
+	Deleted bool
+	Limit   int
+	Offset  int
+}
+
+// UserPage is one page of a user listing
+type UserPage struct {
+	Users  []*entity.User
+	Total  int
+	Limit  int
+	Offset int
+}
+
+// Every admin operation re-checks the acting user's current role, so that an
+// administrator who was demoted cannot act on an access token issued earlier.
+
+// ListUsers searches the users on behalf of an administrator
+func (s *AuthService) ListUsers(ctx context.Context, actorID string, input ListUsersInput) (*UserPage, error) {
+	if _, err := s.authorizeAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	filter := output.UserFilter{
+		Query:   strings.TrimSpace(input.Query),
+		Deleted: input.Deleted,
+		Limit:   input.Limit,
+		Offset:  max(input.Offset, 0),
+	}
+	if input.Role != "" {
+		role, err := entity.ParseRole(input.Role)
+		if err != nil {
+			return nil, err
+		}
+		filter.Role = role
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	filter.Limit = min(filter.Limit, maxUserPageSize)
+
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserPage{
+		Users:  users,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// GetUser retrieves any user on behalf of an administrator
+func (s *AuthService) GetUser(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	if _, err := s.authorizeAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return s.findUser(ctx, userID)
+}
+
+// SetUserRole changes the role of another user. The user's sessions are
+// revoked so that new access tokens carry the new role.
+func (s *AuthService) SetUserRole(ctx context.Context, actorID, userID, roleName string) (*entity.User, error) {
+	role, err := entity.ParseRole(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.adminTarget(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+
+	user.Role = role
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DisableUser blocks another user from signing in and revokes their sessions.
+// Their personal access tokens stop working until the user is enabled again.
+func (s *AuthService) DisableUser(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	user, err := s.adminTarget(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDisabled() {
+		return user, nil
+	}
+
+	user.Disable()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// EnableUser lets a disabled user sign in again
+func (s *AuthService) EnableUser(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	user, err := s.adminTarget(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsDisabled() {
+		return user, nil
+	}
+
+	user.Enable()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// DeleteUser deletes another user and everything they own once the deletion
+// grace period ends
+func (s *AuthService) DeleteUser(ctx context.Context, actorID, userID string) error {
+	user, err := s.adminTarget(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.deleteUser(ctx, user)
+}
+
+// RestoreUser undoes the deletion of another user within the grace period.
+// Their sessions and personal access tokens stay revoked.
+func (s *AuthService) RestoreUser(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	if _, err := s.authorizeAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	user, err := s.userRepo.GetDeletedByID(ctx, userID)
+	if err == entity.ErrUserNotFound {
+		if _, err := s.userRepo.GetByID(ctx, userID); err == nil {
+			return nil, entity.ErrUserNotDeleted
+		}
+		return nil, entity.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Accounts past the grace period are only waiting for the purge job
+	if !s.isRestorable(user) {
+		return nil, entity.ErrUserNotFound
+	}
+
+	if err := s.restoreUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// authorizeAdmin loads the acting user and checks they may manage users
+func (s *AuthService) authorizeAdmin(ctx context.Context, actorID string) (*entity.User, error) {
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, entity.ErrPermissionDenied
+	}
+
+	if actor.IsDisabled() || !actor.HasPermission(entity.PermissionManageUsers) {
+		return nil, entity.ErrPermissionDenied
+	}
+
+	return actor, nil
+}
+
+// adminTarget authorizes the acting user and loads the user they act on.
+// Administrators manage their own account through the regular endpoints so
+// that they cannot lock themselves out.
+func (s *AuthService) adminTarget(ctx context.Context, actorID, userID string) (*entity.User, error) {
+	if _, err := s.authorizeAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	if userID == actorID {
+		return nil, entity.ErrCannotModifySelf
+	}
+
+	return s.findUser(ctx, userID)
+}
+
+// findUser loads a user by an ID taken from a request path
+func (s *AuthService) findUser(ctx context.Context, userID string) (*entity.User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, entity.ErrUserNotFound
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
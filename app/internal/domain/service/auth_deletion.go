@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// Deleted accounts are kept for AuthConfig.AccountDeletionGracePeriod. Until
+// then the owner restores theirs by signing in again, or an administrator
+// restores it; afterwards PurgeDeletedAccounts removes it with all its data.
+
+// PurgeDeletedAccounts permanently deletes the accounts whose grace period
+// has ended and returns how many were removed
+func (s *AuthService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	return s.userRepo.PurgeDeleted(ctx, time.Now().Add(-s.cfg.AccountDeletionGracePeriod))
+}
+
+// deleteUser deletes an account whose sessions were already revoked. Personal
+// access tokens are revoked too so that restoring the account does not revive them.
+func (s *AuthService) deleteUser(ctx context.Context, user *entity.User) error {
+	if s.cfg.AccountDeletionGracePeriod <= 0 {
+		return s.userRepo.Delete(ctx, user.ID)
+	}
+
+	if err := s.accessTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	user.MarkDeleted()
+	return s.userRepo.Update(ctx, user)
+}
+
+// signIn starts a session for a user who passed every factor of a login. A
+// deleted account is restored only now, so that a login failing at the
+// second factor leaves it deleted.
+func (s *AuthService) signIn(ctx context.Context, user *entity.User, client ClientInfo) (*TokenPair, error) {
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDeleted() {
+		if err := s.restoreUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+// restoreUser undoes the deletion of an account
+func (s *AuthService) restoreUser(ctx context.Context, user *entity.User) error {
+	user.Restore()
+	return s.userRepo.Update(ctx, user)
+}
+
+// isRestorable reports whether a deleted account is still within its grace period
+func (s *AuthService) isRestorable(user *entity.User) bool {
+	return user.IsDeleted() && time.Since(*user.DeletedAt) < s.cfg.AccountDeletionGracePeriod
+}
+
+// restorableUserByEmail finds a deleted account that its owner may restore by
+// signing in. Disabled accounts stay deleted until an administrator acts.
+func (s *AuthService) restorableUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	user, err := s.userRepo.GetDeletedByEmail(ctx, email)
+	if err != nil || !s.isRestorable(user) || user.IsDisabled() {
+		return nil, entity.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// restorableUserByID finds a deleted account by ID, see restorableUserByEmail
+func (s *AuthService) restorableUserByID(ctx context.Context, id string) (*entity.User, error) {
+	user, err := s.userRepo.GetDeletedByID(ctx, id)
+	if err != nil || !s.isRestorable(user) || user.IsDisabled() {
+		return nil, entity.ErrUserNotFound
+	}
+	return user, nil
+}
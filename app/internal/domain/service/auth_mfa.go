@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// recoveryCodeCount is the number of recovery codes issued at a time
+const recoveryCodeCount = 10
+
+var totpCodeRegex = regexp.MustCompile(`^[0-9]{6}$`)
+
+// TOTPEnrollment holds what a user needs to add an authenticator app
+type TOTPEnrollment struct {
+	Secret string
+	// KeyURI is the otpauth:// URI, usually shown as a QR code
+	KeyURI string
+}
+
+// MFAChallenge is issued by Login instead of tokens when the user has
+// two-factor authentication enabled
+type MFAChallenge struct {
+	Token     string
+	ExpiresIn int // challenge lifetime in seconds
+}
+
+// MFAStatus describes the two-factor authentication setup of a user
+type MFAStatus struct {
+	Enabled                bool
+	RecoveryCodesRemaining int
+}
+
+// GetMFAStatus returns whether the user has two-factor authentication enabled
+func (s *AuthService) GetMFAStatus(ctx context.Context, userID string) (*MFAStatus, error) {
+	factor, err := s.confirmedTOTPFactor(ctx, userID)
+	if err == entity.ErrMFANotEnabled {
+		return &MFAStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := s.recoveryCodeRepo.CountUnused(ctx, factor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAStatus{Enabled: true, RecoveryCodesRemaining: remaining}, nil
+}
+
+// EnrollTOTP starts TOTP enrollment by generating a new secret. The factor
+// does not protect logins until it is confirmed with ConfirmTOTP.
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	existing, err := s.totpRepo.GetByUserID(ctx, userID)
+	if err != nil && err != entity.ErrTOTPNotFound {
+		return nil, err
+	}
+	if existing != nil && existing.IsConfirmed() {
+		return nil, entity.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	// A new enrollment replaces any unconfirmed one
+	if err := s.totpRepo.Save(ctx, entity.NewTOTPFactor(userID, secret)); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		KeyURI: auth.TOTPKeyURI(s.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP completes TOTP enrollment with a code from the authenticator
+// and returns the user's recovery codes. They are only shown this once. Wrong
+// codes count towards the same lock as those given at login.
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	factor, err := s.totpRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err == entity.ErrTOTPNotFound {
+			return nil, entity.ErrMFANotEnabled
+		}
+		return nil, err
+	}
+
+	if factor.IsConfirmed() {
+		return nil, entity.ErrMFAAlreadyEnabled
+	}
+
+	if err := s.beginMFACodeCheck(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.verifyTOTPCode(ctx, factor, code); err != nil {
+		return nil, err
+	}
+	if err := s.endMFACodeCheck(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	factor.Confirm()
+	if err := s.totpRepo.Save(ctx, factor); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// DisableMFA removes the user's authenticator and recovery codes after
+// checking their password and a TOTP or recovery code
+func (s *AuthService) DisableMFA(ctx context.Context, userID, password, code string) error {
+	if err := s.authenticateSecondFactor(ctx, userID, password, code); err != nil {
+		return err
+	}
+
+	if err := s.totpRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.recoveryCodeRepo.DeleteForUser(ctx, userID)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking
+// their password and a TOTP or recovery code
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID, password, code string) ([]string, error) {
+	if err := s.authenticateSecondFactor(ctx, userID, password, code); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// VerifyMFA completes a two-step login with a TOTP or recovery code and
+// returns the token pair. Each challenge allows a limited number of attempts,
+// and too many wrong codes across challenges lock the user's second factor
+// for a while with an entity.RetryAfterError wrapping entity.ErrAccountLocked.
+func (s *AuthService) VerifyMFA(ctx context.Context, challengeToken, code string, client ClientInfo) (*TokenPair, error) {
+	stored, err := s.oneTimeTokenRepo.GetByHash(ctx, entity.TokenPurposeMFAChallenge, auth.HashOpaqueToken(challengeToken))
+	if err != nil {
+		if err == entity.ErrOneTimeTokenNotFound {
+			return nil, entity.ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	if stored.IsUsed() || stored.IsExpired() {
+		return nil, entity.ErrInvalidMFAChallenge
+	}
+
+	if err := s.beginMFAAttempt(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	// A login to a deleted account restores it once the second factor checks out
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		user, err = s.restorableUserByID(ctx, stored.UserID)
+	}
+	if err != nil {
+		return nil, entity.ErrInvalidMFAChallenge
+	}
+	if user.IsDisabled() {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	factor, err := s.confirmedTOTPFactor(ctx, user.ID)
+	if err != nil {
+		return nil, entity.ErrInvalidMFAChallenge
+	}
+
+	if err := s.verifySecondFactor(ctx, factor, code); err != nil {
+		if err != entity.ErrInvalidMFACode {
+			return nil, err
+		}
+		// A wrong code fails the sign-in rather than a form the user is filling in
+		return nil, entity.ErrInvalidMFACode.WithKind(entity.KindUnauthorized)
+	}
+
+	if err := s.oneTimeTokenRepo.MarkUsed(ctx, stored.ID); err != nil {
+		if err == entity.ErrOneTimeTokenUsed {
+			return nil, entity.ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	if err := s.resetMFAFailures(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return s.signIn(ctx, user, client)
+}
+
+// issueMFAChallenge creates a challenge that VerifyMFA exchanges for tokens
+func (s *AuthService) issueMFAChallenge(ctx context.Context, user *entity.User) (*MFAChallenge, error) {
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := entity.NewOneTimeToken(user.ID, entity.TokenPurposeMFAChallenge, user.Email, tokenHash, s.cfg.MFAChallengeExpiry)
+	stored.ID = uuid.New().String()
+
+	// Save challenge
+	if err := s.oneTimeTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &MFAChallenge{
+		Token:     token,
+		ExpiresIn: int(s.cfg.MFAChallengeExpiry.Seconds()),
+	}, nil
+}
+
+// confirmedTOTPFactor returns the user's enrolled TOTP factor or entity.ErrMFANotEnabled
+func (s *AuthService) confirmedTOTPFactor(ctx context.Context, userID string) (*entity.TOTPFactor, error) {
+	factor, err := s.totpRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err == entity.ErrTOTPNotFound {
+			return nil, entity.ErrMFANotEnabled
+		}
+		return nil, err
+	}
+
+	if !factor.IsConfirmed() {
+		return nil, entity.ErrMFANotEnabled
+	}
+
+	return factor, nil
+}
+
+// authenticateSecondFactor checks the password and a TOTP or recovery code of
+// a signed-in user changing their two-factor settings, so that a stolen
+// session and password are not enough to turn the second factor off. Wrong
+// codes count towards the same lock as those given at login.
+func (s *AuthService) authenticateSecondFactor(ctx context.Context, userID, password, code string) error {
+	if _, err := s.authenticateUser(ctx, userID, password); err != nil {
+		return err
+	}
+
+	factor, err := s.confirmedTOTPFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.beginMFACodeCheck(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.verifySecondFactor(ctx, factor, code); err != nil {
+		return err
+	}
+
+	return s.endMFACodeCheck(ctx, userID)
+}
+
+// verifySecondFactor accepts either a TOTP code or an unused recovery code
+func (s *AuthService) verifySecondFactor(ctx context.Context, factor *entity.TOTPFactor, code string) error {
+	if totpCodeRegex.MatchString(code) {
+		return s.verifyTOTPCode(ctx, factor, code)
+	}
+
+	recovery, err := s.recoveryCodeRepo.GetByHash(ctx, factor.UserID, auth.HashRecoveryCode(code))
+	if err != nil {
+		if err == entity.ErrRecoveryCodeNotFound {
+			return entity.ErrInvalidMFACode
+		}
+		return err
+	}
+
+	if recovery.IsUsed() {
+		return entity.ErrInvalidMFACode
+	}
+
+	if err := s.recoveryCodeRepo.MarkUsed(ctx, recovery.ID); err != nil {
+		if err == entity.ErrRecoveryCodeUsed {
+			return entity.ErrInvalidMFACode
+		}
+		return err
+	}
+
+	return nil
+}
+
+// verifyTOTPCode checks a TOTP code and records its time step so it cannot be replayed
+func (s *AuthService) verifyTOTPCode(ctx context.Context, factor *entity.TOTPFactor, code string) error {
+	step, ok := auth.ValidateTOTP(factor.Secret, code, time.Now())
+	if !ok || step <= factor.LastUsedStep {
+		return entity.ErrInvalidMFACode
+	}
+
+	if err := s.totpRepo.MarkStepUsed(ctx, factor.UserID, step); err != nil {
+		if err == entity.ErrMFACodeReused {
+			return entity.ErrInvalidMFACode
+		}
+		return err
+	}
+
+	factor.LastUsedStep = step
+	return nil
+}
+
+// issueRecoveryCodes replaces the user's recovery codes with new ones and returns them
+func (s *AuthService) issueRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	stored := make([]*entity.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, codeHash, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recovery := entity.NewRecoveryCode(userID, codeHash)
+		recovery.ID = uuid.New().String()
+
+		codes = append(codes, code)
+		stored = append(stored, recovery)
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(ctx, userID, stored); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// OIDCAuthRequestExpiry bounds how long a user may take at the identity provider
+const OIDCAuthRequestExpiry = 10 * time.Minute
+
+// OIDCAuthorization is a started authorization request: the URL to send the
+// user to, and the state that the browser sending them must present again
+// with the callback
+type OIDCAuthorization struct {
+	URL   string
+	State string
+}
+
+// OIDCResult is the outcome of an identity provider callback: a login for
+// sign-in requests, or the new identity for link requests
+type OIDCResult struct {
+	Login          *LoginResult
+	LinkedIdentity *entity.UserIdentity
+}
+
+// IdentityProviders returns the names of the configured identity providers
+func (s *AuthService) IdentityProviders() []string {
+	names := make([]string, 0, len(s.identityProviders))
+	for name := range s.identityProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartOIDCLogin begins a sign-in with an identity provider
+func (s *AuthService) StartOIDCLogin(ctx context.Context, providerName string) (*OIDCAuthorization, error) {
+	return s.startOIDC(ctx, providerName, nil)
+}
+
+// StartOIDCLink begins linking an identity provider account to the signed-in user
+func (s *AuthService) StartOIDCLink(ctx context.Context, userID, providerName string) (*OIDCAuthorization, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, entity.ErrUserNotFound
+	}
+	return s.startOIDC(ctx, providerName, &userID)
+}
+
+// CompleteOIDC handles the identity provider callback. The state must belong
+// to an unused authorization request for the provider and match browserState,
+// the state remembered by the browser that started the request, so that a
+// request cannot be completed by someone it was passed on to. The code is
+// redeemed with the request's PKCE verifier and the ID token must carry its nonce.
+//
+// A sign-in logs in the user linked to the external identity. Unknown
+// identities get a new account unless their email is already registered, in
+// which case the user must sign in and link the provider themselves.
+func (s *AuthService) CompleteOIDC(ctx context.Context, providerName, state, browserState, code string, client ClientInfo) (*OIDCResult, error) {
+	provider, ok := s.identityProviders[providerName]
+	if !ok {
+		return nil, entity.ErrIdentityProviderNotFound
+	}
+
+	if browserState == "" || subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, entity.ErrInvalidOIDCState
+	}
+
+	stored, err := s.oidcRequestRepo.GetByStateHash(ctx, auth.HashOpaqueToken(state))
+	if err != nil {
+		if err == entity.ErrOIDCAuthRequestNotFound {
+			return nil, entity.ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+
+	if stored.Provider != providerName || stored.IsUsed() || stored.IsExpired() {
+		return nil, entity.ErrInvalidOIDCState
+	}
+
+	// Consume the request first so a callback cannot be replayed
+	if err := s.oidcRequestRepo.MarkUsed(ctx, stored.ID); err != nil {
+		return nil, err
+	}
+
+	external, err := provider.Exchange(ctx, code, stored.CodeVerifier, stored.Nonce)
+	if err != nil {
+		log.Printf("Sign-in with identity provider %s failed: %v", providerName, err)
+		return nil, entity.ErrOIDCAuthFailed
+	}
+
+	if stored.LinkUserID != nil {
+		identity, err := s.linkIdentity(ctx, *stored.LinkUserID, external)
+		if err != nil {
+			return nil, err
+		}
+		return &OIDCResult{LinkedIdentity: identity}, nil
+	}
+
+	user, err := s.userForIdentity(ctx, external)
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := s.completeLogin(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCResult{Login: login}, nil
+}
+
+// ListIdentities returns the identity provider accounts linked to the user
+func (s *AuthService) ListIdentities(ctx context.Context, userID string) ([]*entity.UserIdentity, error) {
+	return s.identityRepo.ListByUserID(ctx, userID)
+}
+
+// UnlinkIdentity removes the user's link to an identity provider. Users
+// without a password must keep at least one identity to sign in with.
+func (s *AuthService) UnlinkIdentity(ctx context.Context, userID, providerName string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.ErrUserNotFound
+	}
+
+	if !user.HasPassword() {
+		identities, err := s.identityRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(identities) == 1 && identities[0].Provider == providerName {
+			return entity.ErrLastSignInMethod
+		}
+	}
+
+	return s.identityRepo.Delete(ctx, userID, providerName)
+}
+
+// startOIDC stores a new authorization request and returns the provider URL for it
+func (s *AuthService) startOIDC(ctx context.Context, providerName string, linkUserID *string) (*OIDCAuthorization, error) {
+	provider, ok := s.identityProviders[providerName]
+	if !ok {
+		return nil, entity.ErrIdentityProviderNotFound
+	}
+
+	state, stateHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, _, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, _, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := entity.NewOIDCAuthRequest(providerName, stateHash, nonce, codeVerifier, OIDCAuthRequestExpiry)
+	stored.ID = uuid.New().String()
+	stored.LinkUserID = linkUserID
+
+	// Save request
+	if err := s.oidcRequestRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, output.AuthorizationRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: auth.PKCEChallenge(codeVerifier),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorization{URL: authURL, State: state}, nil
+}
+
+// linkIdentity links an external identity to an existing user
+func (s *AuthService) linkIdentity(ctx context.Context, userID string, external *entity.ExternalIdentity) (*entity.UserIdentity, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, entity.ErrUserNotFound
+	}
+
+	identity := entity.NewUserIdentity(userID, external)
+	identity.ID = uuid.New().String()
+
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+// userForIdentity returns the user linked to an external identity, creating
+// an account for identities seen for the first time
+func (s *AuthService) userForIdentity(ctx context.Context, external *entity.ExternalIdentity) (*entity.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, external.Provider, external.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err == nil {
+			return user, nil
+		}
+		// Signing in restores an account deleted within the grace period
+		// once the login is complete
+		return s.restorableUserByID(ctx, identity.UserID)
+	}
+	if err != entity.ErrIdentityNotFound {
+		return nil, err
+	}
+
+	return s.registerWithIdentity(ctx, external)
+}
+
+// registerWithIdentity creates a password-less account for an external identity.
+// Existing accounts are never linked by email alone, since that would hand
+// them to whoever controls the address at the provider.
+func (s *AuthService) registerWithIdentity(ctx context.Context, external *entity.ExternalIdentity) (*entity.User, error) {
+	if !external.EmailVerified {
+		return nil, entity.ErrOIDCEmailNotVerified
+	}
+
+	user, err := entity.NewUser(external.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if email already exists
+	existingUser, _ := s.userRepo.GetByEmail(ctx, user.Email)
+	if existingUser != nil {
+		return nil, entity.ErrOIDCAccountExists
+	}
+
+	user.ID = uuid.New().String()
+	user.MarkEmailVerified()
+
+	// Save user
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, entity.ErrEmailExists) {
+			return nil, entity.ErrOIDCAccountExists
+		}
+		return nil, err
+	}
+
+	if _, err := s.linkIdentity(ctx, user.ID, external); err != nil {
+		// Do not leave behind an account nobody can sign in to
+		if err := s.userRepo.Delete(ctx, user.ID); err != nil {
+			log.Printf("Failed to remove user %s after linking failed: %v", user.ID, err)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
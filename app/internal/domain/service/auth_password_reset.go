@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// RequestPasswordReset emails a password reset link to the user.
+// It succeeds silently for unknown addresses and delivery failures, and the
+// email is sent after it returns, so that callers cannot use it to discover
+// registered emails. Requests for one address are throttled whether or not
+// it is registered.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = lookupEmail(email)
+
+	if err := s.throttleAddress(ctx, "password-reset", email); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	s.sendInBackground(ctx, "password reset", user, s.sendPasswordResetEmail)
+	return nil
+}
+
+// ResetPassword consumes a password reset token, sets the new password,
+// signs the user out everywhere and revokes their personal access tokens
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	stored, err := s.oneTimeTokenRepo.GetByHash(ctx, entity.TokenPurposePasswordReset, auth.HashOpaqueToken(token))
+	if err != nil {
+		if err == entity.ErrOneTimeTokenNotFound {
+			return entity.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if stored.IsUsed() || stored.IsExpired() {
+		return entity.ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil || user.Email != stored.Email {
+		return entity.ErrInvalidResetToken
+	}
+
+	// Validate password before consuming the token so the user can retry
+	if err := s.validatePassword(ctx, newPassword, user.Email); err != nil {
+		return err
+	}
+
+	if err := s.oneTimeTokenRepo.MarkUsed(ctx, stored.ID); err != nil {
+		if err == entity.ErrOneTimeTokenUsed {
+			return entity.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// Hash password
+	hashedPassword, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = hashedPassword
+	// Receiving the reset email proves ownership of the address
+	if !user.IsEmailVerified() {
+		user.MarkEmailVerified()
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// Other outstanding reset links must not be usable after a successful reset
+	if err := s.oneTimeTokenRepo.DeleteForUser(ctx, user.ID, entity.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// Personal access tokens survive logouts and password changes, but a reset
+	// may follow a compromise in which one was minted
+	return s.accessTokenRepo.RevokeAllForUser(ctx, user.ID)
+}
+
+// sendPasswordResetEmail issues a new password reset token and emails it to the user
+func (s *AuthService) sendPasswordResetEmail(ctx context.Context, user *entity.User) error {
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	stored := entity.NewOneTimeToken(user.ID, entity.TokenPurposePasswordReset, user.Email, tokenHash, s.cfg.PasswordResetExpiry)
+	stored.ID = uuid.New().String()
+
+	// Save token
+	if err := s.oneTimeTokenRepo.Create(ctx, stored); err != nil {
+		return err
+	}
+
+	link := s.cfg.AppBaseURL + "/auth/password/reset?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, output.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("We received a request to reset your Todolist password.\n\n"+
+			"Use the link below to choose a new password:\n\n%s\n\n"+
+			"This link expires in %d minutes and can only be used once. "+
+			"If you did not request a reset, you can ignore this email.\n",
+			link, int(s.cfg.PasswordResetExpiry.Minutes())),
+	})
+}
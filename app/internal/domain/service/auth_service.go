@@ -2,33 +2,184 @@ package service
 
 import (
 	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/twaydev/golang-todolist/app/internal/auth"
 	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
 	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
 )
 
+// TokenPair holds the credentials issued on login or refresh
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // access token lifetime in seconds
+}
+
+// LoginResult is the outcome of a password login: either a token pair or,
+// for users with two-factor authentication, a challenge for VerifyMFA
+type LoginResult struct {
+	Tokens       *TokenPair
+	MFAChallenge *MFAChallenge
+}
+
+// AuthConfig holds the tunable settings of the auth service
+type AuthConfig struct {
+	RefreshTokenExpiry       time.Duration
+	EmailVerificationExpiry  time.Duration
+	RequireEmailVerification bool
+	PasswordResetExpiry      time.Duration
+	// PasswordPolicy is enforced whenever a password is set
+	PasswordPolicy entity.PasswordPolicy
+	// AppBaseURL is used to build links in emails sent to users
+	AppBaseURL string
+
+	// Account lockout after MaxFailedLogins failed logins within a window of
+	// FailedLoginWindow; zero disables it. The first lockout lasts
+	// LockoutDuration and doubles with every lockout in a row up to
+	// MaxLockoutDuration.
+	MaxFailedLogins    int
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+	FailedLoginWindow  time.Duration
+
+	// Per-IP throttling of public auth endpoints: ClientRateLimit requests per
+	// ClientRateWindow; zero disables it
+	ClientRateLimit  int
+	ClientRateWindow time.Duration
+
+	// Per-address throttling of requests that email a user, such as password
+	// resets: EmailRateLimit requests per EmailRateWindow; zero disables it
+	EmailRateLimit  int
+	EmailRateWindow time.Duration
+
+	// Two-factor authentication: MFAIssuer names the service in authenticator
+	// apps; an MFA challenge lasts MFAChallengeExpiry and allows MFAMaxAttempts codes
+	MFAIssuer          string
+	MFAChallengeExpiry time.Duration
+	MFAMaxAttempts     int
+	// MFAMaxFailures wrong codes across challenges within FailedLoginWindow
+	// lock the user's second factor until the window ends; zero disables it
+	MFAMaxFailures int
+
+	// Deleted accounts can be restored for AccountDeletionGracePeriod before
+	// they are purged; zero deletes accounts immediately
+	AccountDeletionGracePeriod time.Duration
+}
+
 // AuthService handles authentication operations
 type AuthService struct {
-	userRepo   output.UserRepository
-	jwtManager *auth.JWTManager
+	userRepo         output.UserRepository
+	refreshTokenRepo output.RefreshTokenRepository
+	revocationStore  output.TokenRevocationStore
+	oneTimeTokenRepo output.OneTimeTokenRepository
+	totpRepo         output.TOTPRepository
+	recoveryCodeRepo output.RecoveryCodeRepository
+	identityRepo     output.UserIdentityRepository
+	oidcRequestRepo  output.OIDCAuthRequestRepository
+	accessTokenRepo  output.PersonalAccessTokenRepository
+	sessionRepo      output.SessionRepository
+	attemptStore     output.AttemptStore
+	mailer           output.Mailer
+	jwtManager       *auth.JWTManager
+	passwordHasher   auth.PasswordHasher
+	breachChecker    output.BreachedPasswordChecker
+	cfg              AuthConfig
+
+	// identityProviders holds the OpenID Connect providers users may sign in with, by name
+	identityProviders map[string]output.IdentityProvider
+
+	// dummyPasswordHash is compared against when a login names an unknown user
+	dummyPasswordHash string
+
+	// background tracks emails still being sent after their request returned
+	background sync.WaitGroup
 }
 
-// NewAuthService creates a new auth service
-func NewAuthService(userRepo output.UserRepository, jwtSecret string, jwtExpiryHours int) *AuthService {
-	return &AuthService{
-		userRepo:   userRepo,
-		jwtManager: auth.NewJWTManager(jwtSecret, jwtExpiryHours),
+// NewAuthService creates a new auth service. It hashes a dummy password up
+// front so that logins naming unknown users only verify, like wrong passwords.
+func NewAuthService(
+	userRepo output.UserRepository,
+	refreshTokenRepo output.RefreshTokenRepository,
+	revocationStore output.TokenRevocationStore,
+	oneTimeTokenRepo output.OneTimeTokenRepository,
+	totpRepo output.TOTPRepository,
+	recoveryCodeRepo output.RecoveryCodeRepository,
+	identityRepo output.UserIdentityRepository,
+	oidcRequestRepo output.OIDCAuthRequestRepository,
+	accessTokenRepo output.PersonalAccessTokenRepository,
+	sessionRepo output.SessionRepository,
+	attemptStore output.AttemptStore,
+	mailer output.Mailer,
+	jwtManager *auth.JWTManager,
+	passwordHasher auth.PasswordHasher,
+	breachChecker output.BreachedPasswordChecker,
+	identityProviders []output.IdentityProvider,
+	cfg AuthConfig,
+) (*AuthService, error) {
+	dummyPasswordHash, err := passwordHasher.Hash("dummy-password-for-timing")
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make(map[string]output.IdentityProvider, len(identityProviders))
+	for _, provider := range identityProviders {
+		providers[provider.Name()] = provider
 	}
+
+	return &AuthService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationStore:  revocationStore,
+		oneTimeTokenRepo: oneTimeTokenRepo,
+		totpRepo:         totpRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		identityRepo:     identityRepo,
+		oidcRequestRepo:  oidcRequestRepo,
+		accessTokenRepo:  accessTokenRepo,
+		sessionRepo:      sessionRepo,
+		attemptStore:     attemptStore,
+		mailer:           mailer,
+		jwtManager:       jwtManager,
+		passwordHasher:   passwordHasher,
+		breachChecker:    breachChecker,
+		cfg:              cfg,
+
+		identityProviders: providers,
+		dummyPasswordHash: dummyPasswordHash,
+	}, nil
+}
+
+// Wait blocks until the emails sent in the background have been handed to
+// the mailer. Call it on shutdown, after the server stopped taking requests.
+func (s *AuthService) Wait() {
+	s.background.Wait()
+}
+
+// sendInBackground sends an email to the user after the request returns, so
+// that its response takes as long whether or not an email was sent. Failures
+// are logged, since nobody is waiting for them.
+func (s *AuthService) sendInBackground(ctx context.Context, kind string, user *entity.User, send func(context.Context, *entity.User) error) {
+	ctx = context.WithoutCancel(ctx)
+
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		if err := send(ctx, user); err != nil {
+			log.Printf("Failed to send %s email to user %s: %v", kind, user.ID, err)
+		}
+	}()
 }
 
 // Register creates a new user account
 func (s *AuthService) Register(ctx context.Context, email, password string) (*entity.User, error) {
 	// Validate password
-	if err := entity.ValidatePassword(password); err != nil {
+	if err := s.validatePassword(ctx, password, email); err != nil {
 		return nil, err
 	}
 
@@ -38,54 +189,393 @@ func (s *AuthService) Register(ctx context.Context, email, password string) (*en
 		return nil, err
 	}
 
+	// Hash password before the duplicate check so that a conflict takes as
+	// long as a registration and only the documented 409 reveals the account
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if email already exists
-	existingUser, _ := s.userRepo.GetByEmail(ctx, email)
+	existingUser, _ := s.userRepo.GetByEmail(ctx, user.Email)
 	if existingUser != nil {
 		return nil, entity.ErrEmailExists
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, err
-	}
-
 	user.ID = uuid.New().String()
-	user.PasswordHash = string(hashedPassword)
+	user.PasswordHash = hashedPassword
 
 	// Save user
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
+	// The account exists at this point; a failed email can be resent later
+	if err := s.sendVerificationEmail(ctx, user, user.Email); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
 	return user, nil
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
-	// Get user by email
+// Login authenticates a user and returns an access and refresh token pair, or
+// an MFA challenge to be completed with VerifyMFA when the user has
+// two-factor authentication enabled.
+// Unknown emails and wrong passwords both fail with entity.ErrInvalidCredentials.
+// Repeated failures lock the account for a while, during which every attempt
+// fails with an entity.RetryAfterError wrapping entity.ErrAccountLocked.
+func (s *AuthService) Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error) {
+	email = lookupEmail(email)
+
+	attempt, err := s.beginLoginAttempt(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get user by email; unknown emails count failures too so that lockouts
+	// do not reveal which accounts exist
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", entity.ErrUserNotFound
+		// Signing in restores an account deleted within the grace period
+		user, err = s.restorableUserByEmail(ctx, email)
+	}
+	if err != nil {
+		// Compare against a dummy hash so the response takes as long as a wrong password
+		_ = s.passwordHasher.Verify(s.dummyPasswordHash, password)
+
+		if err := s.recordLoginFailure(ctx, email, attempt); err != nil {
+			return nil, err
+		}
+		return nil, entity.ErrInvalidCredentials
 	}
 
 	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", entity.ErrInvalidPassword
+	if err := s.verifyPassword(user, password); err != nil {
+		if err := s.recordLoginFailure(ctx, email, attempt); err != nil {
+			return nil, err
+		}
+		return nil, entity.ErrInvalidCredentials
+	}
+
+	if err := s.resetLoginFailures(ctx, email); err != nil {
+		return nil, err
 	}
 
+	// Unverified users may not sign in until they confirm their address
+	if s.cfg.RequireEmailVerification && !user.IsEmailVerified() {
+		return nil, entity.ErrEmailNotVerified
+	}
+
+	// Upgrade hashes made with an outdated algorithm or parameters while the
+	// plain-text password is at hand
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		s.rehashPassword(ctx, user, password)
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+// completeLogin finishes a login whose first factor succeeded: users with an
+// authenticator get an MFA challenge, everyone else a new session. A deleted
+// account stays deleted until the login is complete, see signIn.
+func (s *AuthService) completeLogin(ctx context.Context, user *entity.User, client ClientInfo) (*LoginResult, error) {
+	if user.IsDisabled() {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	// Users with an authenticator must prove the second factor first
+	if _, err := s.confirmedTOTPFactor(ctx, user.ID); err != entity.ErrMFANotEnabled {
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkMFALocked(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		challenge, err := s.issueMFAChallenge(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{MFAChallenge: challenge}, nil
+	}
+
+	tokens, err := s.signIn(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Tokens: tokens}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair in the same session.
+// Each refresh token can be used once; presenting a token that was already
+// exchanged revokes its whole family and returns entity.ErrRefreshTokenReused.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error) {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		return nil, entity.ErrInvalidRefreshToken
+	}
+
+	if stored.IsRevoked() {
+		return nil, entity.ErrInvalidRefreshToken
+	}
+
+	if stored.IsUsed() {
+		return nil, s.revokeReusedFamily(ctx, stored)
+	}
+
+	if stored.IsExpired() {
+		return nil, entity.ErrRefreshTokenExpired
+	}
+
+	// Guard against two concurrent exchanges of the same token
+	if err := s.refreshTokenRepo.MarkUsed(ctx, stored.ID); err != nil {
+		if err == entity.ErrRefreshTokenReused {
+			return nil, s.revokeReusedFamily(ctx, stored)
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, entity.ErrInvalidRefreshToken
+	}
+	if user.IsDisabled() {
+		return nil, entity.ErrAccountDisabled
+	}
+
+	if err := s.resumeSession(ctx, user, stored.FamilyID, client); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokenPair(ctx, user, stored.FamilyID)
+}
+
+// issueTokenPair generates an access token and a refresh token in the given
+// family. The family ID is also the ID of the session the tokens belong to.
+func (s *AuthService) issueTokenPair(ctx context.Context, user *entity.User, familyID string) (*TokenPair, error) {
 	// Generate token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Email)
+	accessToken, err := s.jwtManager.GenerateToken(user.ID, user.Email, string(user.Role), familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := entity.NewRefreshToken(user.ID, familyID, refreshHash, s.cfg.RefreshTokenExpiry)
+	stored.ID = uuid.New().String()
+
+	// Save refresh token
+	if err := s.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.jwtManager.Expiry().Seconds()),
+	}, nil
+}
+
+// revokeReusedFamily signs out the session of a token family after a replayed
+// refresh token, so that the access tokens issued in it stop working as well
+func (s *AuthService) revokeReusedFamily(ctx context.Context, token *entity.RefreshToken) error {
+	err := s.RevokeSession(ctx, token.UserID, token.FamilyID)
+	if err == entity.ErrSessionNotFound {
+		// The session is already signed out, or the family was started
+		// before sessions were tracked
+		err = s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+	}
+	if err != nil {
+		return err
+	}
+	return entity.ErrRefreshTokenReused
+}
+
+// ValidateToken validates a JWT token and returns the claims.
+// Tokens that were revoked by logout, or whose session was signed out, are
+// rejected with auth.ErrRevokedToken.
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (*auth.Claims, error) {
+	claims, err := s.jwtManager.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	revoked, err := s.revocationStore.IsRevoked(ctx, claims.ID, claims.UserID, issuedAt)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if revoked {
+		return nil, auth.ErrRevokedToken
 	}
 
-	return token, nil
+	if err := s.checkSession(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func (s *AuthService) ValidateToken(token string) (*auth.Claims, error) {
-	return s.jwtManager.ValidateToken(token)
+// JWKS returns the public keys that verify issued access tokens
+func (s *AuthService) JWKS() auth.JWKS {
+	return s.jwtManager.JWKS()
+}
+
+// Logout revokes the access token described by claims and signs out its session.
+// If a refresh token is supplied, its whole rotation family is revoked as well.
+func (s *AuthService) Logout(ctx context.Context, claims *auth.Claims, refreshToken string) error {
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	if err := s.revocationStore.RevokeToken(ctx, claims.ID, claims.UserID, expiresAt); err != nil {
+		return err
+	}
+
+	if claims.SessionID != "" {
+		if err := s.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil && err != entity.ErrSessionNotFound {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil || stored.UserID != claims.UserID {
+		// Unknown or foreign refresh tokens are ignored; the access token is already revoked
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// LogoutAll revokes every access and refresh token issued to the user so far
+func (s *AuthService) LogoutAll(ctx context.Context, claims *auth.Claims) error {
+	if err := s.revokeAllSessions(ctx, claims.UserID); err != nil {
+		return err
+	}
+
+	// The calling token is revoked explicitly in case it was issued within
+	// the same second as the cutoff
+	return s.Logout(ctx, claims, "")
+}
+
+// PurgeExpiredRevocations deletes the revocations of access tokens that have
+// expired anyway and returns how many were removed
+func (s *AuthService) PurgeExpiredRevocations(ctx context.Context) (int, error) {
+	return s.revocationStore.PurgeExpired(ctx)
+}
+
+// PurgeExpiredRefreshTokens deletes the refresh tokens that can no longer be
+// used and returns how many were removed
+func (s *AuthService) PurgeExpiredRefreshTokens(ctx context.Context) (int, error) {
+	return s.refreshTokenRepo.PurgeExpired(ctx)
+}
+
+// PurgeExpiredOneTimeTokens deletes the verification and reset tokens that
+// have expired and returns how many were removed
+func (s *AuthService) PurgeExpiredOneTimeTokens(ctx context.Context) (int, error) {
+	return s.oneTimeTokenRepo.PurgeExpired(ctx)
+}
+
+// PurgeExpiredOIDCRequests deletes the OIDC login requests that have expired
+// and returns how many were removed
+func (s *AuthService) PurgeExpiredOIDCRequests(ctx context.Context) (int, error) {
+	return s.oidcRequestRepo.PurgeExpired(ctx)
+}
+
+// PurgeEndedSessions deletes the sessions that were signed out or idle for
+// longer than a refresh token lives and returns how many were removed
+func (s *AuthService) PurgeEndedSessions(ctx context.Context) (int, error) {
+	return s.sessionRepo.PurgeEnded(ctx, time.Now().Add(-s.cfg.RefreshTokenExpiry))
+}
+
+// revokeAllSessions revokes every access and refresh token issued to the user so far
+func (s *AuthService) revokeAllSessions(ctx context.Context, userID string) error {
+	// JWT issue times are whole seconds, so cut off at the current second to
+	// keep tokens issued right after this call valid. Tokens issued earlier
+	// in that second are caught by signing out their sessions below.
+	if err := s.revocationStore.RevokeAllForUser(ctx, userID, time.Now().Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
+
+// lookupEmail normalizes an address a user signs in with. Malformed input is
+// only trimmed so that it is looked up, and throttled, like any unknown address.
+func lookupEmail(email string) string {
+	if normalized, err := entity.NormalizeEmail(email); err == nil {
+		return normalized
+	}
+	return strings.TrimSpace(email)
+}
+
+// validatePassword checks a new password against the password policy and,
+// when a breach checker is configured, against known data breaches
+func (s *AuthService) validatePassword(ctx context.Context, password, email string) error {
+	violations := s.cfg.PasswordPolicy.Check(password, email)
+
+	if s.breachChecker != nil {
+		breached, err := s.breachChecker.IsBreached(ctx, password)
+		if err != nil {
+			// An unavailable dataset should not block sign-ups
+			log.Printf("Failed to check password against breaches: %v", err)
+		} else if breached {
+			violations = append(violations, entity.PasswordViolation{
+				Code:    entity.ViolationBreached,
+				Message: "Password has appeared in a data breach",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &entity.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// verifyPassword checks a plain-text password against the user's stored hash
+func (s *AuthService) verifyPassword(user *entity.User, password string) error {
+	// Accounts created through an identity provider have no password; compare
+	// against the dummy hash so this takes as long as a wrong password
+	if !user.HasPassword() {
+		_ = s.passwordHasher.Verify(s.dummyPasswordHash, password)
+		return entity.ErrInvalidPassword
+	}
+
+	if err := s.passwordHasher.Verify(user.PasswordHash, password); err != nil {
+		return entity.ErrInvalidPassword
+	}
+	return nil
+}
+
+// rehashPassword stores a new hash of the user's password made with the
+// current algorithm. Failures are only logged since the old hash still works.
+func (s *AuthService) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		log.Printf("Failed to save rehashed password of user %s: %v", user.ID, err)
+	}
 }
 
 // GetUserByID retrieves a user by ID
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// ClientInfo describes the client a request came from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// ListSessions returns the user's signed-in sessions, most recently seen first.
+// Sessions idle for longer than a refresh token lives can no longer be resumed
+// and are left out.
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]*entity.Session, error) {
+	return s.sessionRepo.ListActiveByUserID(ctx, userID, time.Now().Add(-s.cfg.RefreshTokenExpiry))
+}
+
+// RevokeSession signs out one of the user's sessions: its refresh tokens stop
+// working and its access tokens are rejected from the next request on
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return entity.ErrSessionNotFound
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, sessionID)
+}
+
+// startSession records a new sign-in from the client and issues the first
+// token pair of its refresh token family
+func (s *AuthService) startSession(ctx context.Context, user *entity.User, client ClientInfo) (*TokenPair, error) {
+	session := entity.NewSession(user.ID, client.UserAgent, client.IPAddress)
+	session.ID = uuid.New().String()
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokenPair(ctx, user, session.ID)
+}
+
+// resumeSession records a refresh of the session behind a token family.
+// Families started before sessions were tracked get a session on first refresh.
+func (s *AuthService) resumeSession(ctx context.Context, user *entity.User, familyID string, client ClientInfo) error {
+	session, err := s.sessionRepo.GetByID(ctx, familyID)
+	if err == entity.ErrSessionNotFound {
+		session = entity.NewSession(user.ID, client.UserAgent, client.IPAddress)
+		session.ID = familyID
+		return s.sessionRepo.Create(ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+
+	if session.IsRevoked() {
+		return entity.ErrInvalidRefreshToken
+	}
+
+	return s.sessionRepo.Touch(ctx, session.ID, time.Now(), client.IPAddress)
+}
+
+// checkSession rejects access tokens whose session was signed out and keeps
+// track of when the session was last used
+func (s *AuthService) checkSession(ctx context.Context, claims *auth.Claims) error {
+	// Tokens issued before sessions were tracked carry no session
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
+	if err != nil {
+		if err == entity.ErrSessionNotFound {
+			return auth.ErrRevokedToken
+		}
+		return err
+	}
+
+	if session.IsRevoked() || session.UserID != claims.UserID {
+		return auth.ErrRevokedToken
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) >= lastUsedResolution {
+		// Activity tracking is informational and must not fail the request
+		if err := s.sessionRepo.Touch(ctx, session.ID, now, ""); err != nil {
+			log.Printf("Failed to record activity on session %s: %v", session.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// ThrottleClient counts a request to action from the client IP and rejects it
+// with an entity.RetryAfterError wrapping entity.ErrTooManyRequests once the
+// client exceeds the configured rate
+func (s *AuthService) ThrottleClient(ctx context.Context, action, clientIP string) error {
+	if s.cfg.ClientRateLimit <= 0 {
+		return nil
+	}
+
+	// Counting first keeps concurrent requests from all passing on the same
+	// count. Rejected requests count too, but do not extend the window.
+	counter, err := s.attemptStore.Increment(ctx, "client:"+action+":"+clientIP, s.cfg.ClientRateWindow)
+	if err != nil {
+		return err
+	}
+
+	if counter.Count > s.cfg.ClientRateLimit {
+		return &entity.RetryAfterError{
+			Err:        entity.ErrTooManyRequests,
+			RetryAfter: time.Until(counter.ExpiresAt),
+		}
+	}
+
+	return nil
+}
+
+// PurgeExpiredAttempts deletes the throttling counters whose window has ended
+// and returns how many were removed
+func (s *AuthService) PurgeExpiredAttempts(ctx context.Context) (int, error) {
+	return s.attemptStore.PurgeExpired(ctx)
+}
+
+// throttleAddress counts a request to action that emails the address and
+// rejects it with an entity.RetryAfterError wrapping entity.ErrTooManyRequests
+// once the address exceeds the configured rate. Unknown addresses are counted
+// like registered ones, so the rejection reveals nothing about them.
+func (s *AuthService) throttleAddress(ctx context.Context, action, email string) error {
+	if s.cfg.EmailRateLimit <= 0 {
+		return nil
+	}
+
+	counter, err := s.attemptStore.Increment(ctx, "email:"+action+":"+strings.ToLower(email), s.cfg.EmailRateWindow)
+	if err != nil {
+		return err
+	}
+
+	if counter.Count > s.cfg.EmailRateLimit {
+		return &entity.RetryAfterError{
+			Err:        entity.ErrTooManyRequests,
+			RetryAfter: time.Until(counter.ExpiresAt),
+		}
+	}
+
+	return nil
+}
+
+// beginLoginAttempt rejects logins to a locked account and otherwise counts
+// the attempt against it before the credentials are checked, so that
+// concurrent attempts cannot all pass on the same count. It returns the
+// number of attempts in the current failure window, including this one.
+func (s *AuthService) beginLoginAttempt(ctx context.Context, email string) (int, error) {
+	if s.cfg.MaxFailedLogins <= 0 {
+		return 0, nil
+	}
+
+	if err := s.checkAccountLocked(ctx, email); err != nil {
+		return 0, err
+	}
+
+	counter, err := s.attemptStore.Increment(ctx, loginAttemptKey(email), s.cfg.FailedLoginWindow)
+	if err != nil {
+		return 0, err
+	}
+
+	// An attempt counted just after a racing one locked the account and
+	// started a new window must not be checked either
+	if err := s.checkAccountLocked(ctx, email); err != nil {
+		return 0, err
+	}
+
+	// Attempts racing the one that used up the limit are failed unchecked
+	if counter.Count > s.cfg.MaxFailedLogins {
+		return 0, s.lockAccount(ctx, email)
+	}
+
+	return counter.Count, nil
+}
+
+// checkAccountLocked returns an entity.RetryAfterError while the account is locked
+func (s *AuthService) checkAccountLocked(ctx context.Context, email string) error {
+	locked, err := s.attemptStore.Get(ctx, lockedKey(email))
+	if err != nil {
+		return err
+	}
+	if locked.Count > 0 {
+		return &entity.RetryAfterError{
+			Err:        entity.ErrAccountLocked,
+			RetryAfter: time.Until(locked.ExpiresAt),
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure locks the account when a failed attempt used up the
+// limit; the attempt itself was counted by beginLoginAttempt
+func (s *AuthService) recordLoginFailure(ctx context.Context, email string, attempt int) error {
+	if s.cfg.MaxFailedLogins <= 0 || attempt < s.cfg.MaxFailedLogins {
+		return nil
+	}
+
+	err := s.lockAccount(ctx, email)
+	if errors.Is(err, entity.ErrAccountLocked) {
+		// The failure is reported; the lockout applies from the next attempt
+		return nil
+	}
+	return err
+}
+
+// lockAccount locks the account for a lockout that doubles with every
+// lockout in a row, and starts a new failure window for when it ends. It
+// returns the entity.RetryAfterError to report while locked.
+func (s *AuthService) lockAccount(ctx context.Context, email string) error {
+	lockouts, err := s.attemptStore.Get(ctx, lockoutsKey(email))
+	if err != nil {
+		return err
+	}
+
+	// A lockout that is already live keeps its end
+	locked, err := s.attemptStore.Increment(ctx, lockedKey(email), s.lockoutDuration(lockouts.Count+1))
+	if err != nil {
+		return err
+	}
+
+	// Only the attempt that started the lockout escalates it and starts the
+	// next failure window; racing attempts just report it
+	if locked.Count == 1 {
+		if _, err := s.attemptStore.Increment(ctx, lockoutsKey(email), s.cfg.FailedLoginWindow+s.cfg.MaxLockoutDuration); err != nil {
+			return err
+		}
+		if err := s.attemptStore.Reset(ctx, loginAttemptKey(email)); err != nil {
+			return err
+		}
+	}
+
+	return &entity.RetryAfterError{
+		Err:        entity.ErrAccountLocked,
+		RetryAfter: time.Until(locked.ExpiresAt),
+	}
+}
+
+// resetLoginFailures forgets the failures and lockouts of an account after a
+// successful login, including a lockout started by an attempt racing it
+func (s *AuthService) resetLoginFailures(ctx context.Context, email string) error {
+	if s.cfg.MaxFailedLogins <= 0 {
+		return nil
+	}
+	for _, key := range []string{loginAttemptKey(email), lockoutsKey(email), lockedKey(email)} {
+		if err := s.attemptStore.Reset(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockoutDuration returns how long the given lockout in a row lasts. The
+// lockout doubles every time, up to MaxLockoutDuration.
+func (s *AuthService) lockoutDuration(lockouts int) time.Duration {
+	lockout := s.cfg.LockoutDuration
+	for i := 1; i < lockouts && lockout < s.cfg.MaxLockoutDuration; i++ {
+		lockout *= 2
+	}
+	if lockout > s.cfg.MaxLockoutDuration {
+		lockout = s.cfg.MaxLockoutDuration
+	}
+	return lockout
+}
+
+// beginMFAAttempt counts an attempt against the challenge and its user before
+// the code is checked, so that concurrent codes cannot all pass on the same
+// count. A challenge with no attempts left is invalid.
+func (s *AuthService) beginMFAAttempt(ctx context.Context, challenge *entity.OneTimeToken) error {
+	attempts, err := s.attemptStore.Increment(ctx, mfaChallengeKey(challenge.ID), time.Until(challenge.ExpiresAt))
+	if err != nil {
+		return err
+	}
+	if attempts.Count > s.cfg.MFAMaxAttempts {
+		return entity.ErrInvalidMFAChallenge
+	}
+
+	return s.beginMFACodeCheck(ctx, challenge.UserID)
+}
+
+// beginMFACodeCheck counts a code given by the user against the wrong codes
+// that lock their second factor, whether it completes a login or changes the
+// two-factor settings of a signed-in user
+func (s *AuthService) beginMFACodeCheck(ctx context.Context, userID string) error {
+	if s.cfg.MFAMaxFailures <= 0 {
+		return nil
+	}
+
+	failures, err := s.attemptStore.Increment(ctx, mfaFailuresKey(userID), s.cfg.FailedLoginWindow)
+	if err != nil {
+		return err
+	}
+	if failures.Count > s.cfg.MFAMaxFailures {
+		return &entity.RetryAfterError{
+			Err:        entity.ErrAccountLocked,
+			RetryAfter: time.Until(failures.ExpiresAt),
+		}
+	}
+	return nil
+}
+
+// checkMFALocked rejects new MFA challenges for a user whose wrong codes used
+// up the limit, so that knowing the password does not buy fresh attempts
+func (s *AuthService) checkMFALocked(ctx context.Context, userID string) error {
+	if s.cfg.MFAMaxFailures <= 0 {
+		return nil
+	}
+
+	failures, err := s.attemptStore.Get(ctx, mfaFailuresKey(userID))
+	if err != nil {
+		return err
+	}
+	if failures.Count >= s.cfg.MFAMaxFailures {
+		return &entity.RetryAfterError{
+			Err:        entity.ErrAccountLocked,
+			RetryAfter: time.Until(failures.ExpiresAt),
+		}
+	}
+	return nil
+}
+
+// resetMFAFailures forgets the attempts counted by beginMFAAttempt after a
+// challenge was completed
+func (s *AuthService) resetMFAFailures(ctx context.Context, challenge *entity.OneTimeToken) error {
+	if err := s.attemptStore.Reset(ctx, mfaChallengeKey(challenge.ID)); err != nil {
+		return err
+	}
+	return s.endMFACodeCheck(ctx, challenge.UserID)
+}
+
+// endMFACodeCheck forgets the wrong codes counted by beginMFACodeCheck after
+// the user gave a right one
+func (s *AuthService) endMFACodeCheck(ctx context.Context, userID string) error {
+	if s.cfg.MFAMaxFailures <= 0 {
+		return nil
+	}
+	return s.attemptStore.Reset(ctx, mfaFailuresKey(userID))
+}
+
+// loginAttemptKey returns the key counting the login attempts of an account
+func loginAttemptKey(email string) string {
+	return "login:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// lockoutsKey returns the key counting the lockouts in a row of an account
+func lockoutsKey(email string) string {
+	return "lockouts:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// lockedKey returns the key that is live while an account is locked
+func lockedKey(email string) string {
+	return "locked:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// mfaChallengeKey returns the key counting the codes tried against an MFA challenge
+func mfaChallengeKey(challengeID string) string {
+	return "mfa:" + challengeID
+}
+
+// mfaFailuresKey returns the key counting the MFA codes tried by a user
+func mfaFailuresKey(userID string) string {
+	return "mfa-user:" + userID
+}
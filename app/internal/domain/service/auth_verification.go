@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// VerifyEmail consumes an email verification token and marks the address as verified.
+// A token sent to an address other than the current one confirms a pending email
+// change: the old address is told about it and every session is signed out.
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	return s.verifyEmail(ctx, token, true)
+}
+
+// VerifyEmailLink verifies an address from an opened link. Links are fetched by
+// mail scanners and previews too, so a token confirming an email change is left
+// unused and rejected with entity.ErrEmailChangeNotConfirmed; the change has to
+// be confirmed with VerifyEmail.
+func (s *AuthService) VerifyEmailLink(ctx context.Context, token string) error {
+	return s.verifyEmail(ctx, token, false)
+}
+
+// verifyEmail consumes an email verification token, applying the email change
+// it confirms only when allowChange is set
+func (s *AuthService) verifyEmail(ctx context.Context, token string, allowChange bool) error {
+	stored, err := s.oneTimeTokenRepo.GetByHash(ctx, entity.TokenPurposeEmailVerification, auth.HashOpaqueToken(token))
+	if err != nil {
+		if err == entity.ErrOneTimeTokenNotFound {
+			return entity.ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	if stored.IsUsed() || stored.IsExpired() {
+		return entity.ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return entity.ErrInvalidVerificationToken
+	}
+
+	changed := user.Email != stored.Email
+	if changed && !allowChange {
+		return entity.ErrEmailChangeNotConfirmed
+	}
+
+	if err := s.oneTimeTokenRepo.MarkUsed(ctx, stored.ID); err != nil {
+		if err == entity.ErrOneTimeTokenUsed {
+			return entity.ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	if !changed {
+		if user.IsEmailVerified() {
+			return nil
+		}
+		user.MarkEmailVerified()
+		return s.userRepo.Update(ctx, user)
+	}
+
+	// The token proves ownership of the address it was sent to
+	oldEmail := user.Email
+	user.Email = stored.Email
+	user.MarkEmailVerified()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// Sessions started under the old address must sign in again with the new one
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.sendInBackground(ctx, "email change notice", user, func(ctx context.Context, user *entity.User) error {
+		return s.sendEmailChangedNotice(ctx, oldEmail, user.Email)
+	})
+	return nil
+}
+
+// ResendVerificationEmail sends a fresh verification email, invalidating earlier ones.
+// It succeeds silently for unknown or already verified addresses, and the
+// email is sent after it returns, so that callers cannot use it to discover
+// registered emails. Requests for one address are throttled whether or not
+// it is registered.
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, email string) error {
+	email = lookupEmail(email)
+
+	if err := s.throttleAddress(ctx, "verification", email); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil || user.IsEmailVerified() {
+		return nil
+	}
+
+	s.sendInBackground(ctx, "verification", user, func(ctx context.Context, user *entity.User) error {
+		return s.sendVerificationEmail(ctx, user, user.Email)
+	})
+	return nil
+}
+
+// sendVerificationEmail issues a new verification token for the address and emails it there.
+// The address differs from the user's current one when confirming an email change.
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *entity.User, email string) error {
+	if err := s.oneTimeTokenRepo.DeleteForUser(ctx, user.ID, entity.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	stored := entity.NewOneTimeToken(user.ID, entity.TokenPurposeEmailVerification, email, tokenHash, s.cfg.EmailVerificationExpiry)
+	stored.ID = uuid.New().String()
+
+	// Save token
+	if err := s.oneTimeTokenRepo.Create(ctx, stored); err != nil {
+		return err
+	}
+
+	expiryHours := int(s.cfg.EmailVerificationExpiry.Hours())
+
+	if email != user.Email {
+		// Opening the link only shows the confirmation page; the change
+		// applies once it is confirmed there
+		link := s.cfg.AppBaseURL + "/auth/verify-email/confirm?token=" + url.QueryEscape(token)
+		return s.mailer.Send(ctx, output.Message{
+			To:      email,
+			Subject: "Confirm your new email address",
+			Body: fmt.Sprintf("You asked to change the email address of your Todolist account.\n\n"+
+				"Open the link below and confirm the change to start using this address:\n\n%s\n\n"+
+				"This link expires in %d hours. Until then you can keep signing in with your current address.\n",
+				link, expiryHours),
+		})
+	}
+
+	link := s.cfg.AppBaseURL + "/auth/verify-email?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, output.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Welcome to Todolist!\n\n"+
+			"Please confirm your email address by opening the link below:\n\n%s\n\n"+
+			"This link expires in %d hours. If you did not create an account, you can ignore this email.\n",
+			link, expiryHours),
+	})
+}
+
+// sendEmailChangedNotice tells the old address of an account that its email was
+// changed, so that the owner notices a change they did not make
+func (s *AuthService) sendEmailChangedNotice(ctx context.Context, oldEmail, newEmail string) error {
+	return s.mailer.Send(ctx, output.Message{
+		To:      oldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("The email address of your Todolist account was changed to %s.\n\n"+
+			"You have been signed out everywhere; sign in again with the new address.\n\n"+
+			"If you did not make this change, contact support right away.\n",
+			newEmail),
+	})
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// CreateTagInput holds the fields accepted when creating a tag
+type CreateTagInput struct {
+	Name  string
+	Color string // the default color when empty
+}
+
+// UpdateTagInput holds the fields accepted when updating a tag.
+// Nil fields are left unchanged.
+type UpdateTagInput struct {
+	Name  *string
+	Color *string
+}
+
+// TagService handles the tags of a single owner. Todos refer to tags by
+// name, so renaming, merging and deleting a tag also relabels the todos.
+type TagService struct {
+	tagRepo output.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo output.TagRepository) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// ListTags retrieves the user's tags with the number of todos labelled with each
+func (s *TagService) ListTags(ctx context.Context, userID string) ([]*entity.Tag, error) {
+	return s.tagRepo.List(ctx, userID)
+}
+
+// CreateTag creates a tag that no todo is labelled with yet
+func (s *TagService) CreateTag(ctx context.Context, userID string, input CreateTagInput) (*entity.Tag, error) {
+	tag, err := entity.NewTag(userID, input.Name, input.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	tag.ID = uuid.New().String()
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// GetTag retrieves a tag owned by the user
+func (s *TagService) GetTag(ctx context.Context, userID, id string) (*entity.Tag, error) {
+	return s.tagRepo.GetByID(ctx, userID, id)
+}
+
+// UpdateTag renames or recolors a tag owned by the user. A new name is
+// applied to every todo labelled with the tag; it cannot be the name of
+// another tag, which should be merged instead.
+func (s *TagService) UpdateTag(ctx context.Context, userID, id string, input UpdateTagInput) (*entity.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if err := tag.Rename(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+	if input.Color != nil {
+		if err := tag.SetColor(*input.Color); err != nil {
+			return nil, err
+		}
+	}
+
+	tag.UpdatedAt = time.Now()
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// MergeTags moves the todos of the source tag to the target tag and deletes
+// the source tag. It returns the target tag with its new todo count.
+func (s *TagService) MergeTags(ctx context.Context, userID, sourceID, targetID string) (*entity.Tag, error) {
+	if sourceID == targetID {
+		return nil, entity.ErrTagMergeSelf
+	}
+
+	// Both tags must belong to the user
+	if _, err := s.tagRepo.GetByID(ctx, userID, sourceID); err != nil {
+		return nil, err
+	}
+	if _, err := s.tagRepo.GetByID(ctx, userID, targetID); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Merge(ctx, userID, sourceID, targetID); err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.GetByID(ctx, userID, targetID)
+}
+
+// DeleteTag deletes a tag owned by the user and removes it from its todos
+func (s *TagService) DeleteTag(ctx context.Context, userID, id string) error {
+	return s.tagRepo.Delete(ctx, userID, id)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// todoCursor is the decoded form of the opaque cursor returned with a page of
+// todos. It records the sort order it was issued for, so that it cannot be
+// used to continue a listing in a different order.
+type todoCursor struct {
+	Sort       output.TodoSortField `json:"s"`
+	Descending bool                 `json:"d,omitempty"`
+	ID         string               `json:"i"`
+	Time       *time.Time           `json:"t,omitempty"`
+	Priority   entity.Priority      `json:"p,omitempty"`
+}
+
+// encodeTodoCursor returns the cursor of the position after the given todo
+func encodeTodoCursor(filter output.TodoFilter, todo *entity.Todo) string {
+	cursor := todoCursor{
+		Sort:       filter.Sort,
+		Descending: filter.Descending,
+		ID:         todo.ID,
+	}
+	switch filter.Sort {
+	case output.TodoSortCreatedAt:
+		cursor.Time = &todo.CreatedAt
+	case output.TodoSortUpdatedAt:
+		cursor.Time = &todo.UpdatedAt
+	case output.TodoSortDueDate:
+		cursor.Time = todo.DueDate
+	case output.TodoSortPriority:
+		cursor.Priority = todo.Priority
+	}
+
+	// Marshalling a struct of plain fields cannot fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTodoCursor reads a cursor issued for the filter's sort order
+func decodeTodoCursor(value string, filter output.TodoFilter) (*output.TodoCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	var cursor todoCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+	if cursor.Sort != filter.Sort || cursor.Descending != filter.Descending || uuid.Validate(cursor.ID) != nil {
+		return nil, entity.ErrInvalidCursor
+	}
+
+	switch cursor.Sort {
+	case output.TodoSortCreatedAt, output.TodoSortUpdatedAt:
+		if cursor.Time == nil {
+			return nil, entity.ErrInvalidCursor
+		}
+	case output.TodoSortPriority:
+		if !cursor.Priority.IsValid() {
+			return nil, entity.ErrInvalidCursor
+		}
+	}
+
+	return &output.TodoCursor{
+		ID:       cursor.ID,
+		Time:     cursor.Time,
+		Priority: cursor.Priority,
+	}, nil
+}
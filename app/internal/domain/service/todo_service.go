@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// CreateTodoInput holds the fields accepted when creating a todo
+type CreateTodoInput struct {
+	Title       string
+	Description *string
+	Priority    entity.Priority
+	DueDate     *time.Time
+	Tags        []string
+}
+
+// UpdateTodoInput holds the fields accepted when updating a todo.
+// Nil fields are left unchanged; the Clear flags remove the optional fields.
+type UpdateTodoInput struct {
+	Title            *string
+	Description      *string
+	ClearDescription bool
+	Status           *entity.Status
+	Priority         *entity.Priority
+	DueDate          *time.Time
+	ClearDueDate     bool
+	Tags             []string
+}
+
+// Page sizes for listing todos
+const (
+	defaultTodoPageSize = 20
+	maxTodoPageSize     = 100
+)
+
+// Result counts for searching todos
+const (
+	defaultTodoSearchLimit = 20
+	maxTodoSearchLimit     = 50
+)
+
+// ListTodosInput holds the filters, sort order and paging options for listing todos
+type ListTodosInput struct {
+	Statuses   []string
+	Priorities []string
+	// Tags matches todos with any of the tags, or all of them with MatchAllTags
+	Tags         []string
+	MatchAllTags bool
+
+	DueBefore     *time.Time
+	DueAfter      *time.Time
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	Overdue       bool
+	Query         string // part of the title or description, ignoring case
+
+	Sort  string // created_at when empty
+	Order string // asc or desc; defaults to ascending for due dates and descending otherwise
+	// Cursor is the NextCursor of the previous page, used with the same filters and sort order
+	Cursor string
+	Limit  int
+}
+
+// TodoPage is one page of a todo listing
+type TodoPage struct {
+	Todos []*entity.Todo
+	Total int
+	Limit int
+	// NextCursor continues the listing after this page; empty on the last page
+	NextCursor string
+}
+
+// SearchTodosInput holds a full-text search of the user's todos
+type SearchTodosInput struct {
+	// Query holds words that must all match; quoted text is a phrase and a
+	// word ending in * is a prefix
+	Query    string
+	Language string // en when empty
+	Limit    int
+}
+
+// TodoService handles todo operations for a single owner
+type TodoService struct {
+	todoRepo    output.TodoRepository
+	historyRepo output.TodoStatusHistoryRepository
+}
+
+// NewTodoService creates a new todo service
+func NewTodoService(todoRepo output.TodoRepository, historyRepo output.TodoStatusHistoryRepository) *TodoService {
+	return &TodoService{
+		todoRepo:    todoRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// CreateTodo creates a new todo owned by the user
+func (s *TodoService) CreateTodo(ctx context.Context, userID string, input CreateTodoInput) (*entity.Todo, error) {
+	todo, err := entity.NewTodo(userID, input.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Priority != "" {
+		todo.Priority = input.Priority
+	}
+	todo.Description = input.Description
+	todo.DueDate = input.DueDate
+	todo.SetTags(input.Tags)
+
+	if err := todo.Validate(); err != nil {
+		return nil, err
+	}
+
+	todo.ID = uuid.New().String()
+
+	// History starts with the status the todo was created with
+	change := todo.InitialStatusChange()
+	change.ID = uuid.New().String()
+
+	// Save todo
+	if err := s.todoRepo.Create(ctx, todo, change); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// GetTodo retrieves a todo owned by the user
+func (s *TodoService) GetTodo(ctx context.Context, userID, id string) (*entity.Todo, error) {
+	return s.todoRepo.GetByID(ctx, userID, id)
+}
+
+// ListTodos retrieves a page of the todos owned by the user
+func (s *TodoService) ListTodos(ctx context.Context, userID string, input ListTodosInput) (*TodoPage, error) {
+	filter, err := newTodoFilter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ask for one extra todo to learn whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	todos, total, err := s.todoRepo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TodoPage{
+		Todos: todos,
+		Total: total,
+		Limit: limit,
+	}
+	if len(todos) > limit {
+		page.Todos = todos[:limit]
+		page.NextCursor = encodeTodoCursor(filter, page.Todos[limit-1])
+	}
+
+	return page, nil
+}
+
+// SearchTodos finds the user's todos matching a full-text search, most relevant first
+func (s *TodoService) SearchTodos(ctx context.Context, userID string, input SearchTodosInput) ([]*entity.TodoSearchResult, error) {
+	terms, err := entity.ParseSearchQuery(input.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	search := output.TodoSearch{
+		Terms:    terms,
+		Language: entity.SearchLanguage(input.Language),
+		Limit:    input.Limit,
+	}
+	if search.Language == "" {
+		search.Language = entity.SearchLanguageEnglish
+	}
+	if !search.Language.IsValid() {
+		return nil, entity.ErrInvalidSearchLanguage
+	}
+	if search.Limit <= 0 {
+		search.Limit = defaultTodoSearchLimit
+	}
+	search.Limit = min(search.Limit, maxTodoSearchLimit)
+
+	return s.todoRepo.Search(ctx, userID, search)
+}
+
+// newTodoFilter checks the listing options and converts them to a repository filter
+func newTodoFilter(input ListTodosInput) (output.TodoFilter, error) {
+	filter := output.TodoFilter{
+		MatchAllTags:  input.MatchAllTags,
+		DueBefore:     input.DueBefore,
+		DueAfter:      input.DueAfter,
+		CreatedBefore: input.CreatedBefore,
+		CreatedAfter:  input.CreatedAfter,
+		Overdue:       input.Overdue,
+		Query:         strings.TrimSpace(input.Query),
+		Sort:          output.TodoSortField(input.Sort),
+		Limit:         input.Limit,
+	}
+
+	for _, value := range input.Statuses {
+		status := entity.Status(strings.TrimSpace(value))
+		if !status.IsValid() {
+			return filter, entity.ErrInvalidStatus
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	for _, value := range input.Priorities {
+		priority := entity.Priority(strings.TrimSpace(value))
+		if !priority.IsValid() {
+			return filter, entity.ErrInvalidPriority
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+	for _, tag := range input.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(filter.Tags, tag) {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = output.TodoSortCreatedAt
+	}
+	if !filter.Sort.IsValid() {
+		return filter, entity.ErrValidation.WithMessage("sort must be one of: created_at, updated_at, due_date, priority")
+	}
+	switch input.Order {
+	case "":
+		filter.Descending = filter.Sort != output.TodoSortDueDate
+	case "asc", "desc":
+		filter.Descending = input.Order == "desc"
+	default:
+		return filter, entity.ErrValidation.WithMessage("order must be one of: asc, desc")
+	}
+
+	if input.Cursor != "" {
+		after, err := decodeTodoCursor(input.Cursor, filter)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = after
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTodoPageSize
+	}
+	filter.Limit = min(filter.Limit, maxTodoPageSize)
+
+	return filter, nil
+}
+
+// UpdateTodo applies the given changes to a todo owned by the user.
+// Status changes must follow the allowed transitions; closed todos can only
+// be reopened with ReopenTodo.
+func (s *TodoService) UpdateTodo(ctx context.Context, userID, id string, input UpdateTodoInput) (*entity.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		todo.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Description != nil {
+		todo.Description = input.Description
+	} else if input.ClearDescription {
+		todo.Description = nil
+	}
+	if input.Priority != nil {
+		todo.Priority = *input.Priority
+	}
+	if input.DueDate != nil {
+		todo.DueDate = input.DueDate
+	} else if input.ClearDueDate {
+		todo.DueDate = nil
+	}
+	if input.Tags != nil {
+		todo.SetTags(input.Tags)
+	}
+
+	var change *entity.TodoStatusChange
+	if input.Status != nil {
+		change, err = todo.TransitionTo(*input.Status)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := todo.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveTodo(ctx, todo, change); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// ReopenTodo moves a completed or cancelled todo owned by the user back to pending
+func (s *TodoService) ReopenTodo(ctx context.Context, userID, id string) (*entity.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := todo.Reopen()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveTodo(ctx, todo, change); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// GetTodoHistory retrieves the status changes of a todo owned by the user, oldest first
+func (s *TodoService) GetTodoHistory(ctx context.Context, userID, id string) ([]*entity.TodoStatusChange, error) {
+	// Only the owner may see the history; id may also be a short code
+	todo, err := s.todoRepo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.historyRepo.ListByTodoID(ctx, todo.ID)
+}
+
+// DeleteTodo deletes a todo owned by the user
+func (s *TodoService) DeleteTodo(ctx context.Context, userID, id string) error {
+	return s.todoRepo.Delete(ctx, userID, id)
+}
+
+// saveTodo stores an updated todo together with its status change, if any
+func (s *TodoService) saveTodo(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error {
+	todo.UpdatedAt = time.Now()
+	if change != nil {
+		change.ID = uuid.New().String()
+	}
+
+	// Save todo
+	return s.todoRepo.Update(ctx, todo, change)
+}
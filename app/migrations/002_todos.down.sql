@@ -0,0 +1,5 @@
+-- Drop todos table and related objects
+DROP TRIGGER IF EXISTS update_todos_updated_at ON todos;
+DROP INDEX IF EXISTS idx_todos_user_status;
+DROP INDEX IF EXISTS idx_todos_user_created;
+DROP TABLE IF EXISTS todos;
@@ -0,0 +1,4 @@
+-- Drop one_time_tokens table and email verification column
+DROP INDEX IF EXISTS idx_one_time_tokens_user_purpose;
+DROP TABLE IF EXISTS one_time_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
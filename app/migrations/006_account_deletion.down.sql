@@ -0,0 +1,9 @@
+-- Remove revocations of deleted users before restoring the foreign keys
+DELETE FROM revoked_tokens WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM user_token_revocations WHERE user_id NOT IN (SELECT id FROM users);
+
+-- Restore foreign keys to users
+ALTER TABLE revoked_tokens
+    ADD CONSTRAINT revoked_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE user_token_revocations
+    ADD CONSTRAINT user_token_revocations_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+-- Keep access token revocations after a user is deleted so tokens issued
+-- to the deleted account stay rejected until they expire
+ALTER TABLE revoked_tokens DROP CONSTRAINT IF EXISTS revoked_tokens_user_id_fkey;
+ALTER TABLE user_token_revocations DROP CONSTRAINT IF EXISTS user_token_revocations_user_id_fkey;
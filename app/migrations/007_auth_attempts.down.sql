@@ -0,0 +1,3 @@
+-- Drop auth_attempts table and related objects
+DROP INDEX IF EXISTS idx_auth_attempts_expires_at;
+DROP TABLE IF EXISTS auth_attempts;
@@ -0,0 +1,13 @@
+-- Create auth_attempts table for login lockout and per-IP throttling counters
+CREATE TABLE IF NOT EXISTS auth_attempts (
+    key TEXT PRIMARY KEY,
+    count INTEGER NOT NULL,
+    last_attempt_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- Create index for purging forgotten counters
+CREATE INDEX IF NOT EXISTS idx_auth_attempts_expires_at ON auth_attempts(expires_at);
+
+-- Enable Row Level Security
+ALTER TABLE auth_attempts ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,3 @@
+-- Drop two-factor authentication tables
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS totp_factors;
@@ -0,0 +1,4 @@
+-- Drop identity provider tables
+DROP INDEX IF EXISTS idx_oidc_auth_requests_expires_at;
+DROP TABLE IF EXISTS oidc_auth_requests;
+DROP TABLE IF EXISTS user_identities;
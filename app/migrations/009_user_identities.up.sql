@@ -0,0 +1,31 @@
+-- Create user_identities table linking users to identity provider accounts
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT user_identities_provider_subject_key UNIQUE (provider, subject),
+    CONSTRAINT user_identities_user_provider_key UNIQUE (user_id, provider)
+);
+
+-- Create oidc_auth_requests table for sign-ins in progress at an identity provider
+CREATE TABLE IF NOT EXISTS oidc_auth_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider TEXT NOT NULL,
+    state_hash TEXT UNIQUE NOT NULL,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    link_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMPTZ
+);
+
+-- Create index for cleaning up expired requests
+CREATE INDEX IF NOT EXISTS idx_oidc_auth_requests_expires_at ON oidc_auth_requests(expires_at);
+
+-- Enable Row Level Security
+ALTER TABLE user_identities ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oidc_auth_requests ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,9 @@
+-- Add roles and account disabling to users.
+-- Grant the first administrator with:
+--   UPDATE users SET role = 'admin' WHERE email = 'admin@example.com';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user'
+    CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+
+-- Create index for listing users by role
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
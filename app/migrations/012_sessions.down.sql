@@ -0,0 +1,3 @@
+-- Drop sessions table
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
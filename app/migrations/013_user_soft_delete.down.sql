@@ -0,0 +1,5 @@
+-- Purge accounts awaiting deletion before dropping the column that marks them
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
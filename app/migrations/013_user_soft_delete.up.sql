@@ -0,0 +1,6 @@
+-- Keep deleted accounts for a grace period during which they can be restored.
+-- A background job purges them afterwards, removing their data through ON DELETE CASCADE.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Create index for finding accounts to purge
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
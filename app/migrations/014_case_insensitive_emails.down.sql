@@ -0,0 +1,2 @@
+-- Drop the case-insensitive email index; normalized addresses are kept
+DROP INDEX IF EXISTS users_email_lower_key;
@@ -0,0 +1,35 @@
+-- Treat email addresses as case-insensitive. Stored addresses are normalized
+-- the way the application does (trimmed, Unicode NFC, lowercase domain) and a
+-- unique index on lower(email) stops new accounts differing only in case.
+--
+-- Accounts whose addresses already collide cannot be merged automatically.
+-- The migration then fails and lists them so they can be resolved by hand.
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(emails, '; ')
+    INTO collisions
+    FROM (
+        SELECT string_agg(email || ' (' || id || ')', ', ' ORDER BY created_at) AS emails
+        FROM users
+        GROUP BY lower(normalize(btrim(email), NFC))
+        HAVING COUNT(*) > 1
+    ) AS colliding;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'email addresses differ only in case: %', collisions
+            USING HINT = 'Rename or delete the duplicate accounts, then run the migration again.';
+    END IF;
+END
+$$;
+
+-- Normalize stored addresses
+UPDATE users
+SET email = substring(normalized FROM '^(.*)@') || '@' || lower(substring(normalized FROM '@([^@]*)$'))
+FROM (SELECT id AS user_id, normalize(btrim(email), NFC) AS normalized FROM users) AS n
+WHERE users.id = n.user_id
+    AND email <> substring(normalized FROM '^(.*)@') || '@' || lower(substring(normalized FROM '@([^@]*)$'));
+
+-- Create case-insensitive unique index used for lookups by email
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email));
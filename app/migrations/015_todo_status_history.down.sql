@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS todo_status_history;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS completed_at;
+
+-- Blocked todos go back to pending before the old constraint is restored
+UPDATE todos SET status = 'pending' WHERE status = 'blocked';
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_status_check;
+ALTER TABLE todos ADD CONSTRAINT todos_status_check
+    CHECK (status IN ('pending', 'in_progress', 'completed', 'cancelled'));
@@ -0,0 +1,27 @@
+-- Allow todos to be blocked
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_status_check;
+ALTER TABLE todos ADD CONSTRAINT todos_status_check
+    CHECK (status IN ('pending', 'in_progress', 'blocked', 'completed', 'cancelled'));
+
+-- Record when a todo was completed; existing completed todos use their last update
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+UPDATE todos SET completed_at = updated_at WHERE status = 'completed' AND completed_at IS NULL;
+
+-- Create todo status history table
+CREATE TABLE IF NOT EXISTS todo_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create index for listing a todo's history in order
+CREATE INDEX IF NOT EXISTS idx_todo_status_history_todo ON todo_status_history(todo_id, changed_at);
+
+-- Start the history of existing todos with their current status
+INSERT INTO todo_status_history (todo_id, from_status, to_status, changed_at)
+SELECT id, NULL, status, updated_at FROM todos;
+
+-- Enable Row Level Security
+ALTER TABLE todo_status_history ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_todos_user_code;
+ALTER TABLE todos DROP COLUMN IF EXISTS code;
+
+DROP TABLE IF EXISTS code_sequences;
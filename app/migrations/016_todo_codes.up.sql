@@ -0,0 +1,42 @@
+-- Number each user's todos per year so they can be referred to by short codes such as 26-0042.
+-- code_sequences holds the last number handed out to a user in a year.
+CREATE TABLE IF NOT EXISTS code_sequences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    last_value INTEGER NOT NULL,
+    PRIMARY KEY (user_id, year)
+);
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS code TEXT;
+
+-- Give existing todos codes in the order they were created
+WITH numbered AS (
+    SELECT id,
+           EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')::INTEGER AS year,
+           ROW_NUMBER() OVER (
+               PARTITION BY user_id, EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')
+               ORDER BY created_at, id
+           ) AS seq
+    FROM todos
+    WHERE code IS NULL
+)
+UPDATE todos
+SET code = LPAD((numbered.year % 100)::TEXT, 2, '0') || '-' ||
+           LPAD(numbered.seq::TEXT, GREATEST(4, LENGTH(numbered.seq::TEXT)), '0')
+FROM numbered
+WHERE todos.id = numbered.id;
+
+-- Continue each sequence after the codes handed out above
+INSERT INTO code_sequences (user_id, year, last_value)
+SELECT user_id, EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')::INTEGER, COUNT(*)
+FROM todos
+GROUP BY user_id, EXTRACT(YEAR FROM created_at AT TIME ZONE 'UTC')
+ON CONFLICT (user_id, year) DO NOTHING;
+
+ALTER TABLE todos ALTER COLUMN code SET NOT NULL;
+
+-- Create index for code lookups; codes are unique per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todos_user_code ON todos(user_id, code);
+
+-- Enable Row Level Security
+ALTER TABLE code_sequences ENABLE ROW LEVEL SECURITY;
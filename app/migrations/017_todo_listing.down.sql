@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_todos_tags;
+DROP INDEX IF EXISTS idx_todos_user_due_desc;
+DROP INDEX IF EXISTS idx_todos_user_due;
+DROP INDEX IF EXISTS idx_todos_user_priority;
+DROP INDEX IF EXISTS idx_todos_user_updated;
+
+DROP INDEX IF EXISTS idx_todos_user_created;
+CREATE INDEX IF NOT EXISTS idx_todos_user_created ON todos(user_id, created_at DESC);
+
+ALTER TABLE todos DROP COLUMN IF EXISTS priority_rank;
@@ -0,0 +1,16 @@
+-- Rank priorities so that todos can be sorted by importance rather than alphabetically
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS priority_rank SMALLINT
+    GENERATED ALWAYS AS (CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END) STORED;
+
+-- Create indexes for each sort order; the ID breaks ties so that keyset cursors have a fixed position
+DROP INDEX IF EXISTS idx_todos_user_created;
+CREATE INDEX IF NOT EXISTS idx_todos_user_created ON todos(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_todos_user_updated ON todos(user_id, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_todos_user_priority ON todos(user_id, priority_rank DESC, id DESC);
+
+-- Todos without a due date come last in both directions, which a single index cannot serve
+CREATE INDEX IF NOT EXISTS idx_todos_user_due ON todos(user_id, due_date ASC NULLS LAST, id ASC);
+CREATE INDEX IF NOT EXISTS idx_todos_user_due_desc ON todos(user_id, due_date DESC NULLS LAST, id DESC);
+
+-- Create index for tag filters
+CREATE INDEX IF NOT EXISTS idx_todos_tags ON todos USING GIN (tags);
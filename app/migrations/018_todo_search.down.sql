@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_todos_search_vi;
+DROP INDEX IF EXISTS idx_todos_search_en;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS search_vi;
+ALTER TABLE todos DROP COLUMN IF EXISTS search_en;
+
+DROP FUNCTION IF EXISTS todo_tags_text(TEXT[]);
+DROP TEXT SEARCH CONFIGURATION IF EXISTS vietnamese_unaccent;
+DROP EXTENSION IF EXISTS unaccent;
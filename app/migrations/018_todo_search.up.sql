@@ -0,0 +1,34 @@
+-- Search todos by their title, tags and description
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Vietnamese has no stemmer; match words as written but ignore diacritics
+DROP TEXT SEARCH CONFIGURATION IF EXISTS vietnamese_unaccent;
+CREATE TEXT SEARCH CONFIGURATION vietnamese_unaccent (COPY = simple);
+ALTER TEXT SEARCH CONFIGURATION vietnamese_unaccent
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+    WITH unaccent, simple;
+
+-- array_to_string is only stable, which generated columns do not accept;
+-- joining text with a space does not depend on any setting
+CREATE OR REPLACE FUNCTION todo_tags_text(tags TEXT[]) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT array_to_string(tags, ' ') $$;
+
+-- Weight matches in the title highest, then tags, then the description
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_en TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english'::regconfig, title), 'A') ||
+        setweight(to_tsvector('english'::regconfig, todo_tags_text(tags)), 'B') ||
+        setweight(to_tsvector('english'::regconfig, COALESCE(description, '')), 'C')
+    ) STORED;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS search_vi TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('vietnamese_unaccent'::regconfig, title), 'A') ||
+        setweight(to_tsvector('vietnamese_unaccent'::regconfig, todo_tags_text(tags)), 'B') ||
+        setweight(to_tsvector('vietnamese_unaccent'::regconfig, COALESCE(description, '')), 'C')
+    ) STORED;
+
+-- Create indexes for full-text search
+CREATE INDEX IF NOT EXISTS idx_todos_search_en ON todos USING GIN (search_en);
+CREATE INDEX IF NOT EXISTS idx_todos_search_vi ON todos USING GIN (search_vi);
@@ -0,0 +1,5 @@
+-- Tag names stay on todos.tags, so only the tag details are lost
+CREATE INDEX IF NOT EXISTS idx_todos_tags ON todos USING GIN (tags);
+
+DROP TABLE IF EXISTS todo_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,48 @@
+-- Create tags table; todos refer to tags by name, which is unique per user
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT '#6b7280',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Link todos to their tags. todos.tags keeps the names in order for reading
+-- and for the search vectors; every tag change rewrites both.
+CREATE TABLE IF NOT EXISTS todo_tags (
+    todo_id UUID NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (todo_id, tag_id)
+);
+
+-- Create index for finding the todos of a tag
+CREATE INDEX IF NOT EXISTS idx_todo_tags_tag ON todo_tags(tag_id, todo_id);
+
+-- Create a tag for every name already used on a todo
+INSERT INTO tags (user_id, name)
+SELECT DISTINCT todos.user_id, tag.name
+FROM todos CROSS JOIN LATERAL unnest(todos.tags) AS tag(name)
+ON CONFLICT (user_id, name) DO NOTHING;
+
+INSERT INTO todo_tags (todo_id, tag_id)
+SELECT todos.id, tags.id
+FROM todos
+CROSS JOIN LATERAL unnest(todos.tags) AS tag(name)
+JOIN tags ON tags.user_id = todos.user_id AND tags.name = tag.name
+ON CONFLICT DO NOTHING;
+
+-- Tag filters use todo_tags now
+DROP INDEX IF EXISTS idx_todos_tags;
+
+-- Create updated_at trigger
+DROP TRIGGER IF EXISTS update_tags_updated_at ON tags;
+CREATE TRIGGER update_tags_updated_at
+    BEFORE UPDATE ON tags
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Enable Row Level Security
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE todo_tags ENABLE ROW LEVEL SECURITY;
@@ -0,0 +1,166 @@
+package bdd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// Personal access token step definitions
+
+func (tc *testContext) iCreateAPersonalAccessTokenWithScopes(name, scopes string) error {
+	return tc.createPersonalAccessToken(map[string]interface{}{
+		"name":   name,
+		"scopes": splitScopes(scopes),
+	})
+}
+
+func (tc *testContext) iCreateAPersonalAccessTokenWithScopesExpiringInDays(name, scopes string, days int) error {
+	return tc.createPersonalAccessToken(map[string]interface{}{
+		"name":            name,
+		"scopes":          splitScopes(scopes),
+		"expires_in_days": days,
+	})
+}
+
+func (tc *testContext) iHaveAPersonalAccessTokenWithScopes(scopes string) error {
+	if err := tc.iCreateAPersonalAccessTokenWithScopes("automation", scopes); err != nil {
+		return err
+	}
+	if tc.response.StatusCode != 201 {
+		return fmt.Errorf("failed to create personal access token: %d %v", tc.response.StatusCode, tc.responseBody)
+	}
+	return nil
+}
+
+func (tc *testContext) createPersonalAccessToken(body map[string]interface{}) error {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	if err := tc.makeRequest("POST", "/api/v1/me/tokens", jsonBody, tc.authToken); err != nil {
+		return err
+	}
+	if token, ok := tc.responseBody["token"].(string); ok {
+		tc.personalAccessToken = token
+		tc.personalAccessTokenID, _ = tc.responseBody["id"].(string)
+	}
+	return nil
+}
+
+func (tc *testContext) iAuthenticateWithMyPersonalAccessToken() error {
+	if tc.personalAccessToken == "" {
+		return fmt.Errorf("no personal access token has been created")
+	}
+	tc.previousAuthToken = tc.authToken
+	tc.authToken = tc.personalAccessToken
+	return nil
+}
+
+func (tc *testContext) iAuthenticateWithMySessionAgain() error {
+	tc.authToken = tc.previousAuthToken
+	return nil
+}
+
+func (tc *testContext) iListMyPersonalAccessTokens() error {
+	return tc.makeGetRequest("/api/v1/me/tokens", tc.authToken)
+}
+
+func (tc *testContext) iRevokeMyPersonalAccessToken() error {
+	return tc.iRevokeThePersonalAccessTokenWithID(tc.personalAccessTokenID)
+}
+
+func (tc *testContext) iRevokeThePersonalAccessTokenWithID(id string) error {
+	return tc.makeRequest("DELETE", "/api/v1/me/tokens/"+id, nil, tc.authToken)
+}
+
+func (tc *testContext) myPersonalAccessTokenHasExpired() error {
+	tc.accessTokenRepo.expire(tc.personalAccessTokenID)
+	return nil
+}
+
+func (tc *testContext) thePersonalAccessTokenShouldStartWith(prefix string) error {
+	if !strings.HasPrefix(tc.personalAccessToken, prefix) {
+		return fmt.Errorf("expected personal access token to start with %q, got %q", prefix, tc.personalAccessToken)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseShouldListPersonalAccessTokens(count int) error {
+	tokens, ok := tc.responseBody["tokens"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not list tokens: %v", tc.responseBody)
+	}
+	if len(tokens) != count {
+		return fmt.Errorf("expected %d tokens, got %d: %v", count, len(tokens), tokens)
+	}
+	return nil
+}
+
+func (tc *testContext) theListedTokensShouldNotRevealTheirValue() error {
+	tokens, _ := tc.responseBody["tokens"].([]interface{})
+	for _, item := range tokens {
+		token, _ := item.(map[string]interface{})
+		if _, ok := token["token"]; ok {
+			return fmt.Errorf("listed token reveals its value: %v", token)
+		}
+	}
+	if strings.Contains(fmt.Sprint(tc.responseBody), tc.personalAccessToken) {
+		return fmt.Errorf("token list contains the token value")
+	}
+	return nil
+}
+
+func (tc *testContext) theFirstListedTokenShouldHaveBeenUsed() error {
+	tokens, _ := tc.responseBody["tokens"].([]interface{})
+	if len(tokens) == 0 {
+		return fmt.Errorf("response lists no tokens: %v", tc.responseBody)
+	}
+	token, _ := tokens[0].(map[string]interface{})
+	if _, ok := token["last_used_at"].(string); !ok {
+		return fmt.Errorf("token has no last use: %v", token)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseShouldHaveAnExpiryTime() error {
+	if _, ok := tc.responseBody["expires_at"].(string); !ok {
+		return fmt.Errorf("response has no expiry time: %v", tc.responseBody)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseShouldHaveNoExpiryTime() error {
+	if expiresAt, ok := tc.responseBody["expires_at"]; !ok || expiresAt != nil {
+		return fmt.Errorf("expected no expiry time, got %v", tc.responseBody["expires_at"])
+	}
+	return nil
+}
+
+func splitScopes(scopes string) []string {
+	if scopes == "" {
+		return []string{}
+	}
+	return strings.Split(scopes, ",")
+}
+
+// registerAccessTokenSteps registers the personal access token step definitions
+func registerAccessTokenSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I create a personal access token "([^"]*)" with scopes "([^"]*)"$`, tc.iCreateAPersonalAccessTokenWithScopes)
+	ctx.Step(`^I create a personal access token "([^"]*)" with scopes "([^"]*)" expiring in (-?\d+) days$`, tc.iCreateAPersonalAccessTokenWithScopesExpiringInDays)
+	ctx.Step(`^I have a personal access token with scopes "([^"]*)"$`, tc.iHaveAPersonalAccessTokenWithScopes)
+	ctx.Step(`^I authenticate with my personal access token$`, tc.iAuthenticateWithMyPersonalAccessToken)
+	ctx.Step(`^I authenticate with my session again$`, tc.iAuthenticateWithMySessionAgain)
+	ctx.Step(`^I list my personal access tokens$`, tc.iListMyPersonalAccessTokens)
+	ctx.Step(`^I revoke my personal access token$`, tc.iRevokeMyPersonalAccessToken)
+	ctx.Step(`^I revoke the personal access token with id "([^"]*)"$`, tc.iRevokeThePersonalAccessTokenWithID)
+	ctx.Step(`^my personal access token has expired$`, tc.myPersonalAccessTokenHasExpired)
+	ctx.Step(`^the personal access token should start with "([^"]*)"$`, tc.thePersonalAccessTokenShouldStartWith)
+	ctx.Step(`^the response should list (\d+) personal access tokens?$`, tc.theResponseShouldListPersonalAccessTokens)
+	ctx.Step(`^the listed tokens should not reveal their value$`, tc.theListedTokensShouldNotRevealTheirValue)
+	ctx.Step(`^the first listed token should have been used$`, tc.theFirstListedTokenShouldHaveBeenUsed)
+	ctx.Step(`^the response should have an expiry time$`, tc.theResponseShouldHaveAnExpiryTime)
+	ctx.Step(`^the response should have no expiry time$`, tc.theResponseShouldHaveNoExpiryTime)
+}
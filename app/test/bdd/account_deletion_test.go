@@ -0,0 +1,75 @@
+package bdd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cucumber/godog"
+)
+
+// Account deletion step definitions
+
+func (tc *testContext) theDeletionOfHappenedDaysAgo(email string, days int) error {
+	return tc.userRepo.ageDeletion(email, time.Duration(days)*24*time.Hour)
+}
+
+func (tc *testContext) theAccountPurgeJobRuns() error {
+	purged, err := tc.authService.PurgeDeletedAccounts(context.Background())
+	if err != nil {
+		return err
+	}
+	tc.purgedAccounts = purged
+	return nil
+}
+
+func (tc *testContext) accountsShouldHaveBeenPurged(count int) error {
+	if tc.purgedAccounts != count {
+		return fmt.Errorf("expected %d purged accounts, got %d", count, tc.purgedAccounts)
+	}
+	return nil
+}
+
+func (tc *testContext) theUserShouldNoLongerExist(email string) error {
+	if _, err := tc.userRepo.GetByEmail(context.Background(), email); err == nil {
+		return fmt.Errorf("user %s still exists", email)
+	}
+	if _, err := tc.userRepo.GetDeletedByEmail(context.Background(), email); err == nil {
+		return fmt.Errorf("user %s is deleted but not purged", email)
+	}
+	return nil
+}
+
+func (tc *testContext) theUserShouldStillBeDeleted(email string) error {
+	if _, err := tc.userRepo.GetDeletedByEmail(context.Background(), email); err != nil {
+		return fmt.Errorf("user %s is not deleted", email)
+	}
+	return nil
+}
+
+func (tc *testContext) theUserShouldNoLongerBeDeleted(email string) error {
+	if _, err := tc.userRepo.GetByEmail(context.Background(), email); err != nil {
+		return fmt.Errorf("user %s was not restored", email)
+	}
+	return nil
+}
+
+func (tc *testContext) iListTheDeletedUsers() error {
+	return tc.makeGetRequest("/api/v1/admin/users?deleted=true", tc.authToken)
+}
+
+func (tc *testContext) iRestoreTheUser(email string) error {
+	return tc.adminUserAction("POST", email, "/restore")
+}
+
+// registerAccountDeletionSteps registers the account deletion step definitions
+func registerAccountDeletionSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^the deletion of "([^"]*)" happened (\d+) days ago$`, tc.theDeletionOfHappenedDaysAgo)
+	ctx.Step(`^the account purge job runs$`, tc.theAccountPurgeJobRuns)
+	ctx.Step(`^(\d+) accounts? should have been purged$`, tc.accountsShouldHaveBeenPurged)
+	ctx.Step(`^the user "([^"]*)" should no longer exist$`, tc.theUserShouldNoLongerExist)
+	ctx.Step(`^the user "([^"]*)" should still be deleted$`, tc.theUserShouldStillBeDeleted)
+	ctx.Step(`^the user "([^"]*)" should no longer be deleted$`, tc.theUserShouldNoLongerBeDeleted)
+	ctx.Step(`^I list the deleted users$`, tc.iListTheDeletedUsers)
+	ctx.Step(`^I restore the user "([^"]*)"$`, tc.iRestoreTheUser)
+}
@@ -0,0 +1,159 @@
+package bdd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cucumber/godog"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// Admin step definitions
+
+func (tc *testContext) anAdminExistsWithEmailAndPassword(email, password string) error {
+	if err := tc.aUserExistsWithEmailAndPassword(email, password); err != nil {
+		return err
+	}
+	return tc.iChangeTheRoleOfToDirectly(email, string(entity.RoleAdmin))
+}
+
+// iChangeTheRoleOfToDirectly sets a role in the database, the way the first
+// administrator is promoted
+func (tc *testContext) iChangeTheRoleOfToDirectly(email, role string) error {
+	user, err := tc.userRepo.GetByEmail(context.Background(), email)
+	if err != nil {
+		return err
+	}
+	user.Role = entity.Role(role)
+	return tc.userRepo.Update(context.Background(), user)
+}
+
+func (tc *testContext) iListTheUsers() error {
+	return tc.makeGetRequest("/api/v1/admin/users", tc.authToken)
+}
+
+func (tc *testContext) iSearchTheUsersFor(query string) error {
+	return tc.makeGetRequest("/api/v1/admin/users?q="+url.QueryEscape(query), tc.authToken)
+}
+
+func (tc *testContext) iListTheUsersWithRole(role string) error {
+	return tc.makeGetRequest("/api/v1/admin/users?role="+url.QueryEscape(role), tc.authToken)
+}
+
+func (tc *testContext) iListTheUsersWithLimitAndOffset(limit, offset int) error {
+	return tc.makeGetRequest(fmt.Sprintf("/api/v1/admin/users?limit=%d&offset=%d", limit, offset), tc.authToken)
+}
+
+func (tc *testContext) iGetTheUser(email string) error {
+	id, err := tc.userIDFor(email)
+	if err != nil {
+		return err
+	}
+	return tc.iGetTheUserWithID(id)
+}
+
+func (tc *testContext) iGetTheUserWithID(id string) error {
+	return tc.makeGetRequest("/api/v1/admin/users/"+id, tc.authToken)
+}
+
+func (tc *testContext) iChangeTheRoleOfTo(email, role string) error {
+	id, err := tc.userIDFor(email)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"role": role})
+	if err != nil {
+		return err
+	}
+	return tc.makeRequest("PUT", "/api/v1/admin/users/"+id+"/role", body, tc.authToken)
+}
+
+func (tc *testContext) iDisableTheUser(email string) error {
+	return tc.adminUserAction("POST", email, "/disable")
+}
+
+func (tc *testContext) iEnableTheUser(email string) error {
+	return tc.adminUserAction("POST", email, "/enable")
+}
+
+func (tc *testContext) iDeleteTheUser(email string) error {
+	return tc.adminUserAction("DELETE", email, "")
+}
+
+func (tc *testContext) adminUserAction(method, email, action string) error {
+	id, err := tc.userIDFor(email)
+	if err != nil {
+		return err
+	}
+	return tc.makeRequest(method, "/api/v1/admin/users/"+id+action, nil, tc.authToken)
+}
+
+func (tc *testContext) theResponseShouldListUsers(count int) error {
+	emails, err := tc.listedUserEmails()
+	if err != nil {
+		return err
+	}
+	if len(emails) != count {
+		return fmt.Errorf("expected %d users, got %d: %v", count, len(emails), emails)
+	}
+	return nil
+}
+
+func (tc *testContext) theListedUsersShouldBe(expected string) error {
+	emails, err := tc.listedUserEmails()
+	if err != nil {
+		return err
+	}
+	if got := strings.Join(emails, ","); got != expected {
+		return fmt.Errorf("expected users [%s], got [%s]", expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) listedUserEmails() ([]string, error) {
+	users, ok := tc.responseBody["users"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response does not list users: %v", tc.responseBody)
+	}
+	emails := make([]string, 0, len(users))
+	for _, item := range users {
+		user, _ := item.(map[string]interface{})
+		email, _ := user["email"].(string)
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+// userIDFor looks up the ID of a registered user, including a deleted one
+func (tc *testContext) userIDFor(email string) (string, error) {
+	user, err := tc.userRepo.GetByEmail(context.Background(), email)
+	if err != nil {
+		user, err = tc.userRepo.GetDeletedByEmail(context.Background(), email)
+	}
+	if err != nil {
+		return "", fmt.Errorf("user %s does not exist", email)
+	}
+	return user.ID, nil
+}
+
+// registerAdminSteps registers the user management step definitions
+func registerAdminSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^an admin exists with email "([^"]*)" and password "([^"]*)"$`, tc.anAdminExistsWithEmailAndPassword)
+	ctx.Step(`^I list the users$`, tc.iListTheUsers)
+	ctx.Step(`^I search the users for "([^"]*)"$`, tc.iSearchTheUsersFor)
+	ctx.Step(`^I list the users with role "([^"]*)"$`, tc.iListTheUsersWithRole)
+	ctx.Step(`^I list the users with limit (\d+) and offset (\d+)$`, tc.iListTheUsersWithLimitAndOffset)
+	ctx.Step(`^I get the user "([^"]*)"$`, tc.iGetTheUser)
+	ctx.Step(`^I get the user with id "([^"]*)"$`, tc.iGetTheUserWithID)
+	ctx.Step(`^I change the role of "([^"]*)" to "([^"]*)"$`, tc.iChangeTheRoleOfTo)
+	ctx.Step(`^I change the role of "([^"]*)" to "([^"]*)" directly$`, tc.iChangeTheRoleOfToDirectly)
+	ctx.Step(`^I disable the user "([^"]*)"$`, tc.iDisableTheUser)
+	ctx.Step(`^I enable the user "([^"]*)"$`, tc.iEnableTheUser)
+	ctx.Step(`^I delete the user "([^"]*)"$`, tc.iDeleteTheUser)
+	ctx.Step(`^the response should list (\d+) users?$`, tc.theResponseShouldListUsers)
+	ctx.Step(`^the listed users should be "([^"]*)"$`, tc.theListedUsersShouldBe)
+}
@@ -7,64 +7,200 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cucumber/godog"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
 
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/breach"
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/memory"
+	"github.com/twaydev/golang-todolist/app/internal/adapter/driven/oidc"
 	apphttp "github.com/twaydev/golang-todolist/app/internal/adapter/driving/http"
+	"github.com/twaydev/golang-todolist/app/internal/auth"
 	"github.com/twaydev/golang-todolist/app/internal/config"
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
 	"github.com/twaydev/golang-todolist/app/internal/domain/service"
 )
 
 // testContext holds the state for each scenario
 type testContext struct {
-	server       *httptest.Server
-	echo         *echo.Echo
-	handlers     *apphttp.Handlers
-	authService  *service.AuthService
-	userRepo     *mockUserRepository
-	response     *http.Response
-	responseBody map[string]interface{}
-	authToken    string
+	server           *httptest.Server
+	echo             *echo.Echo
+	jwtManager       *auth.JWTManager
+	hasher           auth.PasswordHasher
+	authService      *service.AuthService
+	todoService      *service.TodoService
+	tagService       *service.TagService
+	userRepo         *mockUserRepository
+	todoRepo         *mockTodoRepository
+	historyRepo      *mockTodoStatusHistoryRepository
+	tagRepo          *mockTagRepository
+	refreshRepo      *mockRefreshTokenRepository
+	revocations      *memory.TokenRevocationStore
+	attempts         *memory.AttemptStore
+	tokenRepo        *mockOneTimeTokenRepository
+	totpRepo         *mockTOTPRepository
+	recoveryRepo     *mockRecoveryCodeRepository
+	identityRepo     *mockUserIdentityRepository
+	oidcRequests     *mockOIDCAuthRequestRepository
+	accessTokenRepo  *mockPersonalAccessTokenRepository
+	sessionRepo      *mockSessionRepository
+	idp              *stubIdentityProvider
+	mailer           *mockMailer
+	response         *http.Response
+	responseBody     map[string]interface{}
+	responseText     string
+	authToken        string
+	lastTodoID       string
+	lastTodoCode     string
+	createdCodes     []string
+	parallelStatuses []int
+	parallelErrors   []string
+	lastListQuery    string
+	todoIDsByTitle   map[string]string
+	lastTagID        string
+
+	previousAuthToken string
+	signingKeys       []*auth.SigningKey
+
+	rememberedEmailToken string
+
+	refreshToken         string
+	previousRefreshToken string
+
+	clientIP  string
+	userAgent string
+	accept    string
+	sessionID string
+	// cookies is the cookie jar of the browser the requests are sent from
+	cookies http.CookieJar
+
+	// purgedAccounts is the number of accounts removed by the last purge
+	purgedAccounts int
+	timings        map[string][]time.Duration
+
+	mfaToken              string
+	totpSecret            string
+	lastTOTPCode          string
+	recoveryCodes         []string
+	previousRecoveryCodes []string
+
+	personalAccessToken   string
+	personalAccessTokenID string
+
+	// authorizationURL is the identity provider URL of the last started link
+	authorizationURL string
 }
 
+// appBaseURL is the address the API puts in emailed links; opening such a
+// link sends the request to the test server instead
+const appBaseURL = "http://todolist.test"
+
 // newTestContext creates a fresh test context
 func newTestContext() *testContext {
-	return &testContext{
-		userRepo: newMockUserRepository(),
+	tc := &testContext{
+		userRepo:        newMockUserRepository(),
+		historyRepo:     newMockTodoStatusHistoryRepository(),
+		refreshRepo:     newMockRefreshTokenRepository(),
+		revocations:     memory.NewTokenRevocationStore(),
+		attempts:        memory.NewAttemptStore(),
+		tokenRepo:       newMockOneTimeTokenRepository(),
+		totpRepo:        newMockTOTPRepository(),
+		recoveryRepo:    newMockRecoveryCodeRepository(),
+		identityRepo:    newMockUserIdentityRepository(),
+		oidcRequests:    newMockOIDCAuthRequestRepository(),
+		accessTokenRepo: newMockPersonalAccessTokenRepository(),
+		sessionRepo:     newMockSessionRepository(),
+		mailer:          newMockMailer(),
+		cookies:         newCookieJar(),
+	}
+	tc.todoRepo = newMockTodoRepository(tc.historyRepo)
+	tc.tagRepo = newMockTagRepository(tc.todoRepo)
+	tc.userRepo.onDelete = func(userID string) {
+		tc.todoRepo.deleteForUser(userID)
+		tc.identityRepo.deleteForUser(userID)
+		tc.accessTokenRepo.deleteForUser(userID)
+		tc.sessionRepo.deleteForUser(userID)
 	}
+	return tc
 }
 
 // setupServer initializes the test server
-func (tc *testContext) setupServer() {
+func (tc *testContext) setupServer() error {
 	cfg := &config.Config{
 		Port:           "8080",
 		Environment:    "test",
 		JWTSecret:      "test-secret-key-minimum-32-characters-long",
 		JWTExpiryHours: 24,
-	}
 
-	tc.authService = service.NewAuthService(tc.userRepo, cfg.JWTSecret, cfg.JWTExpiryHours)
-	tc.handlers = apphttp.NewHandlers(tc.authService)
+		RefreshTokenExpiryHours: 720,
+	}
 
-	tc.echo = echo.New()
+	if tc.jwtManager == nil {
+		tc.jwtManager = auth.NewJWTManager(cfg.JWTSecret, cfg.JWTExpiryHours)
+	}
 
-	// Health
-	tc.echo.GET("/health", tc.handlers.HealthCheck)
+	// The listener is opened first so the identity provider knows the callback URL
+	tc.server = httptest.NewUnstartedServer(nil)
+	serverURL := "http://" + tc.server.Listener.Addr().String()
 
-	// Auth routes
-	tc.echo.POST("/auth/register", tc.handlers.Register)
-	tc.echo.POST("/auth/login", tc.handlers.Login)
+	if tc.idp == nil {
+		tc.idp = newStubIdentityProvider()
+	}
+	identityProvider := oidc.NewProvider(oidc.Config{
+		Name:         "stub",
+		Issuer:       tc.idp.server.URL,
+		ClientID:     stubClientID,
+		ClientSecret: stubClientSecret,
+		RedirectURL:  serverURL + "/auth/oidc/stub/callback",
+	}, nil)
+
+	tc.hasher = auth.NewMigratingHasher(auth.NewArgon2idHasher(auth.DefaultArgon2Params), auth.NewBcryptHasher(bcrypt.DefaultCost))
+
+	authService, err := service.NewAuthService(tc.userRepo, tc.refreshRepo, tc.revocations, tc.tokenRepo, tc.totpRepo, tc.recoveryRepo, tc.identityRepo, tc.oidcRequests, tc.accessTokenRepo, tc.sessionRepo, tc.attempts, tc.mailer, tc.jwtManager, tc.hasher, breach.NewRangeChecker(breach.NewDirSource("testdata/breached-passwords"), 1), []output.IdentityProvider{identityProvider}, service.AuthConfig{
+		RefreshTokenExpiry:       time.Duration(cfg.RefreshTokenExpiryHours) * time.Hour,
+		EmailVerificationExpiry:  24 * time.Hour,
+		RequireEmailVerification: true,
+		PasswordResetExpiry:      30 * time.Minute,
+		PasswordPolicy:           entity.DefaultPasswordPolicy(),
+		AppBaseURL:               appBaseURL,
+		MaxFailedLogins:          3,
+		LockoutDuration:          time.Minute,
+		MaxLockoutDuration:       10 * time.Minute,
+		FailedLoginWindow:        15 * time.Minute,
+		ClientRateLimit:          10,
+		ClientRateWindow:         time.Minute,
+		EmailRateLimit:           3,
+		EmailRateWindow:          time.Hour,
+		MFAIssuer:                "Todolist",
+		MFAChallengeExpiry:       5 * time.Minute,
+		MFAMaxAttempts:           3,
+		MFAMaxFailures:           5,
+
+		AccountDeletionGracePeriod: 30 * 24 * time.Hour,
+	})
+	if err != nil {
+		return err
+	}
+	tc.authService = authService
+	tc.mailer.pending = authService.Wait
+	tc.todoService = service.NewTodoService(tc.todoRepo, tc.historyRepo)
+	tc.tagService = service.NewTagService(tc.tagRepo)
 
-	// Protected routes
-	protected := tc.echo.Group("/api/v1")
-	protected.Use(apphttp.JWTMiddleware(tc.authService))
-	protected.GET("/me", tc.handlers.GetMe)
+	// Use the production router so every route and middleware is exercised
+	tc.echo = apphttp.NewServer(tc.authService, tc.todoService, tc.tagService)
 
-	tc.server = httptest.NewServer(tc.echo)
+	tc.server.Config.Handler = tc.echo
+	tc.server.Start()
+	return nil
 }
 
 // cleanup tears down the test server
@@ -72,17 +208,33 @@ func (tc *testContext) cleanup() {
 	if tc.server != nil {
 		tc.server.Close()
 	}
+	if tc.idp != nil {
+		tc.idp.server.Close()
+		tc.idp = nil
+	}
 }
 
 // Step definitions
 
 func (tc *testContext) theAPIServerIsRunning() error {
-	tc.setupServer()
-	return nil
+	return tc.setupServer()
 }
 
 func (tc *testContext) theDatabaseIsClean() error {
 	tc.userRepo.clear()
+	tc.todoRepo.clear()
+	tc.historyRepo.clear()
+	tc.refreshRepo.clear()
+	tc.revocations.Clear()
+	tc.attempts.Clear()
+	tc.tokenRepo.clear()
+	tc.totpRepo.clear()
+	tc.recoveryRepo.clear()
+	tc.identityRepo.clear()
+	tc.oidcRequests.clear()
+	tc.accessTokenRepo.clear()
+	tc.sessionRepo.clear()
+	tc.mailer.clear()
 	return nil
 }
 
@@ -99,10 +251,29 @@ func (tc *testContext) iLoginWithEmailAndPassword(email, password string) error
 		"email":    email,
 		"password": password,
 	}
-	return tc.makePostRequest("/auth/login", body)
+	if err := tc.makePostRequest("/auth/login", body); err != nil {
+		return err
+	}
+	if mfaToken, ok := tc.responseBody["mfa_token"].(string); ok {
+		tc.mfaToken = mfaToken
+	}
+	return nil
 }
 
 func (tc *testContext) aUserExistsWithEmailAndPassword(email, password string) error {
+	if err := tc.anUnverifiedUserExistsWithEmailAndPassword(email, password); err != nil {
+		return err
+	}
+	if err := tc.iVerifyMyEmailUsingTheLinkSentTo(email); err != nil {
+		return err
+	}
+	// Reset response for next step
+	tc.response = nil
+	tc.responseBody = nil
+	return nil
+}
+
+func (tc *testContext) anUnverifiedUserExistsWithEmailAndPassword(email, password string) error {
 	body := map[string]string{
 		"email":    email,
 		"password": password,
@@ -127,11 +298,76 @@ func (tc *testContext) iAmLoggedInAsWithPassword(email, password string) error {
 		return err
 	}
 	if token, ok := tc.responseBody["token"].(string); ok {
+		tc.previousAuthToken = tc.authToken
+		tc.authToken = token
+	}
+	if refreshToken, ok := tc.responseBody["refresh_token"].(string); ok {
+		tc.previousRefreshToken = tc.refreshToken
+		tc.refreshToken = refreshToken
+	}
+	return nil
+}
+
+func (tc *testContext) iRefreshMyToken() error {
+	if err := tc.iRefreshWithToken(tc.refreshToken); err != nil {
+		return err
+	}
+	if refreshToken, ok := tc.responseBody["refresh_token"].(string); ok {
+		tc.previousRefreshToken = tc.refreshToken
+		tc.refreshToken = refreshToken
+	}
+	return nil
+}
+
+func (tc *testContext) iRefreshWithMyPreviousRefreshToken() error {
+	return tc.iRefreshWithToken(tc.previousRefreshToken)
+}
+
+func (tc *testContext) iRefreshWithToken(refreshToken string) error {
+	body := map[string]string{
+		"refresh_token": refreshToken,
+	}
+	return tc.makePostRequest("/auth/refresh", body)
+}
+
+func (tc *testContext) iLogout() error {
+	return tc.makeRequest("POST", "/auth/logout", nil, tc.authToken)
+}
+
+func (tc *testContext) iLogoutWithMyRefreshToken() error {
+	body := fmt.Sprintf(`{"refresh_token": %q}`, tc.refreshToken)
+	return tc.makeRequest("POST", "/auth/logout", []byte(body), tc.authToken)
+}
+
+func (tc *testContext) iLogoutFromAllDevices() error {
+	return tc.makeRequest("POST", "/auth/logout-all", nil, tc.authToken)
+}
+
+func (tc *testContext) iChangeMyPasswordFromTo(currentPassword, newPassword string) error {
+	body := fmt.Sprintf(`{"current_password": %q, "new_password": %q}`, currentPassword, newPassword)
+	if err := tc.makeRequest("PUT", "/api/v1/me/password", []byte(body), tc.authToken); err != nil {
+		return err
+	}
+	if token, ok := tc.responseBody["token"].(string); ok {
+		tc.previousAuthToken = tc.authToken
 		tc.authToken = token
 	}
+	if refreshToken, ok := tc.responseBody["refresh_token"].(string); ok {
+		tc.refreshToken = refreshToken
+	}
 	return nil
 }
 
+func (tc *testContext) iChangeMyEmailToWithPassword(newEmail, password string) error {
+	body := fmt.Sprintf(`{"new_email": %q, "password": %q}`, newEmail, password)
+	return tc.makeRequest("PUT", "/api/v1/me/email", []byte(body), tc.authToken)
+}
+
+func (tc *testContext) iDeleteMyAccountWithPassword(password string) error {
+	body := fmt.Sprintf(`{"password": %q}`, password)
+	return tc.makeRequest("DELETE", "/api/v1/me", []byte(body), tc.authToken)
+}
+
 func (tc *testContext) iRequestMyProfile() error {
 	return tc.makeGetRequest("/api/v1/me", tc.authToken)
 }
@@ -140,6 +376,10 @@ func (tc *testContext) iRequestMyProfileWithoutAuthentication() error {
 	return tc.makeGetRequest("/api/v1/me", "")
 }
 
+func (tc *testContext) iRequestMyProfileWithMyPreviousToken() error {
+	return tc.makeGetRequest("/api/v1/me", tc.previousAuthToken)
+}
+
 func (tc *testContext) iRequestMyProfileWithToken(token string) error {
 	return tc.makeGetRequest("/api/v1/me", token)
 }
@@ -177,6 +417,19 @@ func (tc *testContext) theResponseFieldShouldBeString(field, expected string) er
 	return nil
 }
 
+func (tc *testContext) theResponseShouldListThePasswordViolation(code string) error {
+	violations, ok := tc.responseBody["violations"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not list violations: %v", tc.responseBody)
+	}
+	for _, v := range violations {
+		if violation, ok := v.(map[string]interface{}); ok && violation["code"] == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected violation %q, got %v", code, violations)
+}
+
 func (tc *testContext) theResponseFieldShouldBeInt(field string, expected int) error {
 	value, ok := tc.responseBody[field]
 	if !ok {
@@ -201,32 +454,57 @@ func (tc *testContext) makePostRequest(path string, body map[string]string) erro
 		return err
 	}
 
-	req, err := http.NewRequest("POST", tc.server.URL+path, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
+	return tc.makeRequest("POST", path, jsonBody, "")
+}
 
-	client := &http.Client{}
-	tc.response, err = client.Do(req)
-	if err != nil {
-		return err
-	}
+func (tc *testContext) makeGetRequest(path string, token string) error {
+	return tc.makeRequest("GET", path, nil, token)
+}
 
-	return tc.parseResponseBody()
+func (tc *testContext) makeRequest(method, path string, body []byte, token string) error {
+	return tc.sendRequest(method, path, body, "application/json", token)
 }
 
-func (tc *testContext) makeGetRequest(path string, token string) error {
-	req, err := http.NewRequest("GET", tc.server.URL+path, nil)
+// makeFormPost posts the values as a form, the way a browser submits one
+func (tc *testContext) makeFormPost(path string, values url.Values) error {
+	return tc.sendRequest("POST", path, []byte(values.Encode()), "application/x-www-form-urlencoded", "")
+}
+
+func (tc *testContext) sendRequest(method, path string, body []byte, contentType, token string) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewBuffer(body)
+	}
+
+	// Absolute URLs point at other servers, such as the identity provider
+	target := path
+	if !strings.HasPrefix(path, "http") {
+		target = tc.server.URL + path
+	}
+
+	req, err := http.NewRequest(method, target, reader)
 	if err != nil {
 		return err
 	}
 
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
 	if token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
+	if tc.userAgent != "" {
+		req.Header.Set("User-Agent", tc.userAgent)
+	}
+	if tc.accept != "" {
+		req.Header.Set("Accept", tc.accept)
+	}
+	if tc.clientIP != "" {
+		// The test client connects from loopback, which is trusted as a proxy
+		req.Header.Set("X-Forwarded-For", tc.clientIP)
+	}
 
-	client := &http.Client{}
+	client := &http.Client{Jar: tc.cookies}
 	tc.response, err = client.Do(req)
 	if err != nil {
 		return err
@@ -235,6 +513,15 @@ func (tc *testContext) makeGetRequest(path string, token string) error {
 	return tc.parseResponseBody()
 }
 
+// newCookieJar returns an empty cookie jar, as kept by a fresh browser
+func newCookieJar() http.CookieJar {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		panic(err)
+	}
+	return jar
+}
+
 func (tc *testContext) parseResponseBody() error {
 	bodyBytes, err := io.ReadAll(tc.response.Body)
 	tc.response.Body.Close()
@@ -242,6 +529,7 @@ func (tc *testContext) parseResponseBody() error {
 		return err
 	}
 
+	tc.responseText = string(bodyBytes)
 	tc.responseBody = make(map[string]interface{})
 	if len(bodyBytes) > 0 {
 		if err := json.Unmarshal(bodyBytes, &tc.responseBody); err != nil {
@@ -282,9 +570,25 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 	// Logged in step
 	ctx.Step(`^I am logged in as "([^"]*)" with password "([^"]*)"$`, tc.iAmLoggedInAsWithPassword)
 
+	// Refresh token steps
+	ctx.Step(`^I refresh my token$`, tc.iRefreshMyToken)
+	ctx.Step(`^I refresh with my previous refresh token$`, tc.iRefreshWithMyPreviousRefreshToken)
+	ctx.Step(`^I refresh with token "([^"]*)"$`, tc.iRefreshWithToken)
+
+	// Logout steps
+	ctx.Step(`^I logout$`, tc.iLogout)
+	ctx.Step(`^I logout with my refresh token$`, tc.iLogoutWithMyRefreshToken)
+	ctx.Step(`^I logout from all devices$`, tc.iLogoutFromAllDevices)
+
+	// Account management steps
+	ctx.Step(`^I change my password from "([^"]*)" to "([^"]*)"$`, tc.iChangeMyPasswordFromTo)
+	ctx.Step(`^I change my email to "([^"]*)" with password "([^"]*)"$`, tc.iChangeMyEmailToWithPassword)
+	ctx.Step(`^I delete my account with password "([^"]*)"$`, tc.iDeleteMyAccountWithPassword)
+
 	// Profile steps
 	ctx.Step(`^I request my profile$`, tc.iRequestMyProfile)
 	ctx.Step(`^I request my profile without authentication$`, tc.iRequestMyProfileWithoutAuthentication)
+	ctx.Step(`^I request my profile with my previous token$`, tc.iRequestMyProfileWithMyPreviousToken)
 	ctx.Step(`^I request my profile with token "([^"]*)"$`, tc.iRequestMyProfileWithToken)
 
 	// Health check step
@@ -295,6 +599,36 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the response should contain "([^"]*)"$`, tc.theResponseShouldContain)
 	ctx.Step(`^the response "([^"]*)" should be "([^"]*)"$`, tc.theResponseFieldShouldBeString)
 	ctx.Step(`^the response "([^"]*)" should be (\d+)$`, tc.theResponseFieldShouldBeInt)
+	ctx.Step(`^the response should list the password violation "([^"]*)"$`, tc.theResponseShouldListThePasswordViolation)
+
+	// Email verification steps
+	registerEmailVerificationSteps(ctx, tc)
+
+	// Password reset steps
+	registerPasswordResetSteps(ctx, tc)
+
+	// Signing key steps
+	registerSigningKeySteps(ctx, tc)
+
+	// Todo steps
+	registerTodoSteps(ctx, tc)
+	registerTagSteps(ctx, tc)
+
+	// Rate limiting steps
+	registerRateLimitSteps(ctx, tc)
+
+	// Timing steps
+	registerTimingSteps(ctx, tc)
+
+	// Password hashing steps
+	registerPasswordHashingSteps(ctx, tc)
+	registerMFASteps(ctx, tc)
+	registerOIDCSteps(ctx, tc)
+	registerAccessTokenSteps(ctx, tc)
+	registerAdminSteps(ctx, tc)
+	registerSessionSteps(ctx, tc)
+	registerAccountDeletionSteps(ctx, tc)
+	registerErrorSteps(ctx, tc)
 }
 
 func TestFeatures(t *testing.T) {
@@ -0,0 +1,87 @@
+package bdd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// Error response and request validation step definitions
+
+func (tc *testContext) iAcceptProblemDetails() error {
+	tc.accept = "application/problem+json"
+	return nil
+}
+
+func (tc *testContext) iSendARequestTo(method, path string) error {
+	return tc.makeRequest(method, path, nil, tc.authToken)
+}
+
+func (tc *testContext) theResponseContentTypeShouldBe(expected string) error {
+	contentType := tc.response.Header.Get("Content-Type")
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if strings.TrimSpace(mediaType) != expected {
+		return fmt.Errorf("expected content type '%s', got '%s'", expected, contentType)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseDetailShouldBe(field, expected string) error {
+	details, ok := tc.responseBody["details"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("response has no details: %v", tc.responseBody)
+	}
+	if got := fmt.Sprint(details[field]); got != expected {
+		return fmt.Errorf("expected detail '%s' to be '%s', got '%s'", field, expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) iCreateATodoWithADescriptionOfCharacters(length int) error {
+	body, err := json.Marshal(map[string]string{
+		"title":       "Long read",
+		"description": strings.Repeat("a", length),
+	})
+	if err != nil {
+		return err
+	}
+	return tc.makeRequest("POST", "/api/v1/todos", body, tc.authToken)
+}
+
+func (tc *testContext) iListTheUsersWithQuery(query string) error {
+	return tc.makeGetRequest("/api/v1/admin/users?"+query, tc.authToken)
+}
+
+func (tc *testContext) theFieldShouldFailTheRule(field, rule string) error {
+	failures, ok := tc.responseBody["details"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not list failing fields: %v", tc.responseBody)
+	}
+	for _, f := range failures {
+		if failure, ok := f.(map[string]interface{}); ok && failure["field"] == field && failure["rule"] == rule {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected field %q to fail rule %q, got %v", field, rule, failures)
+}
+
+func (tc *testContext) theResponseShouldNotContain(field string) error {
+	if value, ok := tc.responseBody[field]; ok {
+		return fmt.Errorf("expected no '%s' in response, got %v", field, value)
+	}
+	return nil
+}
+
+// registerErrorSteps registers the error response and request validation step definitions
+func registerErrorSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I accept problem details$`, tc.iAcceptProblemDetails)
+	ctx.Step(`^I create a todo with a description of (\d+) characters$`, tc.iCreateATodoWithADescriptionOfCharacters)
+	ctx.Step(`^I list the users with query "([^"]*)"$`, tc.iListTheUsersWithQuery)
+	ctx.Step(`^I send a (GET|POST|PUT|DELETE) request to "([^"]*)"$`, tc.iSendARequestTo)
+	ctx.Step(`^the response content type should be "([^"]*)"$`, tc.theResponseContentTypeShouldBe)
+	ctx.Step(`^the response detail "([^"]*)" should be "([^"]*)"$`, tc.theResponseDetailShouldBe)
+	ctx.Step(`^the field "([^"]*)" should fail the "([^"]*)" rule$`, tc.theFieldShouldFailTheRule)
+	ctx.Step(`^the response should not contain "([^"]*)"$`, tc.theResponseShouldNotContain)
+}
@@ -0,0 +1,201 @@
+package bdd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+)
+
+const (
+	stubClientID     = "todolist-test"
+	stubClientSecret = "stub-client-secret"
+	stubKeyID        = "stub-key"
+)
+
+// stubSigningKey is shared by every stub provider since RSA key generation is slow
+var stubSigningKey = sync.OnceValue(func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+})
+
+// stubUser is the account the stub provider signs in
+type stubUser struct {
+	subject       string
+	email         string
+	emailVerified bool
+}
+
+// stubAuthorization is an authorization code waiting to be redeemed
+type stubAuthorization struct {
+	user          stubUser
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+}
+
+// stubIdentityProvider is a minimal OpenID Connect provider for tests. Its
+// authorization endpoint approves the configured user without a login page
+// and redirects straight back to the client.
+type stubIdentityProvider struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	user         stubUser
+	deny         bool
+	codes        map[string]stubAuthorization
+	lastCallback string
+}
+
+func newStubIdentityProvider() *stubIdentityProvider {
+	p := &stubIdentityProvider{
+		user:  stubUser{subject: "stub-subject", email: "stub@example.com", emailVerified: true},
+		codes: make(map[string]stubAuthorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	p.server = httptest.NewServer(mux)
+
+	return p
+}
+
+func (p *stubIdentityProvider) signIn(user stubUser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.user = user
+	p.deny = false
+}
+
+func (p *stubIdentityProvider) denySignIn() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deny = true
+}
+
+func (p *stubIdentityProvider) lastCallbackURL() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastCallback
+}
+
+func (p *stubIdentityProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.server.URL,
+		"authorization_endpoint": p.server.URL + "/authorize",
+		"token_endpoint":         p.server.URL + "/token",
+		"jwks_uri":               p.server.URL + "/jwks",
+	})
+}
+
+func (p *stubIdentityProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != stubClientID || q.Get("response_type") != "code" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	callback := url.Values{"state": {q.Get("state")}}
+	if p.deny {
+		callback.Set("error", "access_denied")
+	} else {
+		code, _, _ := auth.GenerateOpaqueToken()
+		p.codes[code] = stubAuthorization{
+			user:          p.user,
+			nonce:         q.Get("nonce"),
+			codeChallenge: q.Get("code_challenge"),
+			redirectURI:   q.Get("redirect_uri"),
+		}
+		callback.Set("code", code)
+	}
+	p.lastCallback = q.Get("redirect_uri") + "?" + callback.Encode()
+	p.mu.Unlock()
+
+	http.Redirect(w, r, p.lastCallback, http.StatusFound)
+}
+
+func (p *stubIdentityProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != stubClientID || secret != stubClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	authz, ok := p.codes[r.PostFormValue("code")]
+	delete(p.codes, r.PostFormValue("code"))
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" ||
+		r.PostFormValue("redirect_uri") != authz.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authz.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.server.URL,
+		"sub":            authz.user.subject,
+		"aud":            stubClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          authz.nonce,
+		"email":          authz.user.email,
+		"email_verified": authz.user.emailVerified,
+	})
+	token.Header["kid"] = stubKeyID
+
+	idToken, err := token.SignedString(stubSigningKey())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (p *stubIdentityProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	public := stubSigningKey().PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": stubKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
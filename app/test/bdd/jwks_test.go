@@ -0,0 +1,144 @@
+package bdd
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+)
+
+// Signing key step definitions
+
+func (tc *testContext) theAPIServerSignsTokensWithKey(keyType, kid string) error {
+	key, err := newTestSigningKey(keyType, kid)
+	if err != nil {
+		return err
+	}
+	tc.signingKeys = []*auth.SigningKey{key}
+	return tc.restartWithSigningKeys()
+}
+
+func (tc *testContext) theSigningKeyIsRotatedToANewKey(keyType, kid string) error {
+	key, err := newTestSigningKey(keyType, kid)
+	if err != nil {
+		return err
+	}
+	tc.signingKeys = append([]*auth.SigningKey{key}, tc.signingKeys...)
+	return tc.restartWithSigningKeys()
+}
+
+func (tc *testContext) theSigningKeyIsRetired(kid string) error {
+	remaining := make([]*auth.SigningKey, 0, len(tc.signingKeys))
+	for _, key := range tc.signingKeys {
+		if key.ID != kid {
+			remaining = append(remaining, key)
+		}
+	}
+	tc.signingKeys = remaining
+	return tc.restartWithSigningKeys()
+}
+
+func (tc *testContext) iFetchTheJWKS() error {
+	return tc.makeGetRequest("/.well-known/jwks.json", "")
+}
+
+func (tc *testContext) theJWKSShouldContainKeys(expected string) error {
+	keys, ok := tc.responseBody["keys"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not contain a keys list: %v", tc.responseBody)
+	}
+	kids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		jwk, _ := key.(map[string]interface{})
+		kids = append(kids, fmt.Sprint(jwk["kid"]))
+	}
+	if got := strings.Join(kids, ","); got != expected {
+		return fmt.Errorf("expected JWKS keys [%s], got [%s]", expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) myTokenHeaderShouldBe(field, expected string) error {
+	parts := strings.Split(tc.authToken, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("token is not a JWT: %q", tc.authToken)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return err
+	}
+	header := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return err
+	}
+	if got := fmt.Sprint(header[field]); got != expected {
+		return fmt.Errorf("expected token header '%s' to be '%s', got '%s'", field, expected, got)
+	}
+	return nil
+}
+
+// Helper methods
+
+// restartWithSigningKeys restarts the server signing with the first key and
+// verifying with the others, keeping repositories intact
+func (tc *testContext) restartWithSigningKeys() error {
+	if len(tc.signingKeys) == 0 {
+		return fmt.Errorf("no signing keys configured")
+	}
+
+	// Previously active keys are only kept for verification
+	others := make([]*auth.SigningKey, 0, len(tc.signingKeys)-1)
+	for _, key := range tc.signingKeys[1:] {
+		verifyOnly, err := auth.NewVerificationKey(key.ID, key.PublicKey)
+		if err != nil {
+			return err
+		}
+		others = append(others, verifyOnly)
+	}
+
+	keys, err := auth.NewKeySet(tc.signingKeys[0], others...)
+	if err != nil {
+		return err
+	}
+
+	tc.cleanup()
+	tc.jwtManager = auth.NewJWTManagerWithKeys(keys, 24)
+	return tc.setupServer()
+}
+
+func newTestSigningKey(keyType, kid string) (*auth.SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch keyType {
+	case "Ed25519":
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	case "RSA":
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		return nil, fmt.Errorf("unknown key type %q", keyType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewSigningKey(kid, privateKey)
+}
+
+// registerSigningKeySteps registers the signing key step definitions
+func registerSigningKeySteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^the API server signs tokens with an? (Ed25519|RSA) key "([^"]*)"$`, tc.theAPIServerSignsTokensWithKey)
+	ctx.Step(`^the signing key is rotated to a new (Ed25519|RSA) key "([^"]*)"$`, tc.theSigningKeyIsRotatedToANewKey)
+	ctx.Step(`^the signing key "([^"]*)" is retired$`, tc.theSigningKeyIsRetired)
+	ctx.Step(`^I fetch the JWKS$`, tc.iFetchTheJWKS)
+	ctx.Step(`^the JWKS should contain keys "([^"]*)"$`, tc.theJWKSShouldContainKeys)
+	ctx.Step(`^my token header "([^"]*)" should be "([^"]*)"$`, tc.myTokenHeaderShouldBe)
+}
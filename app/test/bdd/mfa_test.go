@@ -0,0 +1,225 @@
+package bdd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cucumber/godog"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+)
+
+// Two-factor authentication step definitions
+
+func (tc *testContext) iEnrollAnAuthenticatorApp() error {
+	if err := tc.makeRequest("POST", "/api/v1/me/mfa/totp", nil, tc.authToken); err != nil {
+		return err
+	}
+	if secret, ok := tc.responseBody["secret"].(string); ok {
+		tc.totpSecret = secret
+	}
+	return nil
+}
+
+func (tc *testContext) iConfirmTheAuthenticatorWithAValidCode() error {
+	code, err := tc.nextTOTPCode()
+	if err != nil {
+		return err
+	}
+	return tc.iConfirmTheAuthenticatorWithCode(code)
+}
+
+func (tc *testContext) iConfirmTheAuthenticatorWithCode(code string) error {
+	body := fmt.Sprintf(`{"code": %q}`, code)
+	if err := tc.makeRequest("POST", "/api/v1/me/mfa/totp/confirm", []byte(body), tc.authToken); err != nil {
+		return err
+	}
+	tc.rememberRecoveryCodes()
+	return nil
+}
+
+func (tc *testContext) iHaveEnabledTwoFactorAuthentication() error {
+	if err := tc.iEnrollAnAuthenticatorApp(); err != nil {
+		return err
+	}
+	if err := tc.iConfirmTheAuthenticatorWithAValidCode(); err != nil {
+		return err
+	}
+	if tc.response.StatusCode != 200 {
+		return fmt.Errorf("enabling two-factor authentication returned %d: %v", tc.response.StatusCode, tc.responseBody)
+	}
+	// Reset response for next step
+	tc.response = nil
+	tc.responseBody = nil
+	return nil
+}
+
+func (tc *testContext) iVerifyTheLoginWithAValidAuthenticatorCode() error {
+	code, err := tc.nextTOTPCode()
+	if err != nil {
+		return err
+	}
+	tc.lastTOTPCode = code
+	return tc.iVerifyTheLoginWithCode(code)
+}
+
+func (tc *testContext) iVerifyTheLoginWithTheSameAuthenticatorCodeAgain() error {
+	return tc.iVerifyTheLoginWithCode(tc.lastTOTPCode)
+}
+
+func (tc *testContext) iVerifyTheLoginWithARecoveryCode() error {
+	if len(tc.recoveryCodes) == 0 {
+		return fmt.Errorf("no recovery codes were issued")
+	}
+	return tc.iVerifyTheLoginWithCode(tc.recoveryCodes[0])
+}
+
+func (tc *testContext) iVerifyTheLoginWithCode(code string) error {
+	body := fmt.Sprintf(`{"mfa_token": %q, "code": %q}`, tc.mfaToken, code)
+	if err := tc.makeRequest("POST", "/auth/mfa/verify", []byte(body), ""); err != nil {
+		return err
+	}
+	if token, ok := tc.responseBody["token"].(string); ok {
+		tc.previousAuthToken = tc.authToken
+		tc.authToken = token
+	}
+	if refreshToken, ok := tc.responseBody["refresh_token"].(string); ok {
+		tc.refreshToken = refreshToken
+	}
+	return nil
+}
+
+func (tc *testContext) iVerifyTheChallengeWithCode(mfaToken, code string) error {
+	tc.mfaToken = mfaToken
+	return tc.iVerifyTheLoginWithCode(code)
+}
+
+func (tc *testContext) iFailToVerifyTheLoginTimes(times int) error {
+	for i := 0; i < times; i++ {
+		if err := tc.iVerifyTheLoginWithCode("000000"); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 401 {
+			return fmt.Errorf("failed verification %d returned %d: %v", i+1, tc.response.StatusCode, tc.responseBody)
+		}
+	}
+	return nil
+}
+
+func (tc *testContext) iFailToVerifyTheLoginTimesInParallel(times int) error {
+	body := fmt.Sprintf(`{"mfa_token": %q, "code": "000000"}`, tc.mfaToken)
+	return tc.postInParallel(times, "/auth/mfa/verify", []byte(body))
+}
+
+func (tc *testContext) iDisableTwoFactorAuthenticationWithPassword(password string) error {
+	code, err := tc.nextTOTPCode()
+	if err != nil {
+		return err
+	}
+	return tc.iDisableTwoFactorAuthenticationWithPasswordAndCode(password, code)
+}
+
+func (tc *testContext) iDisableTwoFactorAuthenticationWithPasswordAndCode(password, code string) error {
+	body := fmt.Sprintf(`{"password": %q, "code": %q}`, password, code)
+	return tc.makeRequest("DELETE", "/api/v1/me/mfa", []byte(body), tc.authToken)
+}
+
+func (tc *testContext) iRegenerateMyRecoveryCodesWithPassword(password string) error {
+	code, err := tc.nextTOTPCode()
+	if err != nil {
+		return err
+	}
+	return tc.iRegenerateMyRecoveryCodesWithPasswordAndCode(password, code)
+}
+
+func (tc *testContext) iRegenerateMyRecoveryCodesWithPasswordAndARecoveryCode(password string) error {
+	if len(tc.recoveryCodes) == 0 {
+		return fmt.Errorf("no recovery codes were issued")
+	}
+	return tc.iRegenerateMyRecoveryCodesWithPasswordAndCode(password, tc.recoveryCodes[0])
+}
+
+func (tc *testContext) iRegenerateMyRecoveryCodesWithPasswordAndCode(password, code string) error {
+	body := fmt.Sprintf(`{"password": %q, "code": %q}`, password, code)
+	previous := tc.recoveryCodes
+	if err := tc.makeRequest("POST", "/api/v1/me/mfa/recovery-codes", []byte(body), tc.authToken); err != nil {
+		return err
+	}
+	tc.rememberRecoveryCodes()
+	tc.previousRecoveryCodes = previous
+	return nil
+}
+
+func (tc *testContext) iVerifyTheLoginWithAnOldRecoveryCode() error {
+	if len(tc.previousRecoveryCodes) == 0 {
+		return fmt.Errorf("no recovery codes were replaced")
+	}
+	return tc.iVerifyTheLoginWithCode(tc.previousRecoveryCodes[0])
+}
+
+func (tc *testContext) iRequestMyTwoFactorAuthenticationStatus() error {
+	return tc.makeGetRequest("/api/v1/me/mfa", tc.authToken)
+}
+
+func (tc *testContext) theResponseShouldListRecoveryCodes(count int) error {
+	codes, ok := tc.responseBody["recovery_codes"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not list recovery codes: %v", tc.responseBody)
+	}
+	if len(codes) != count {
+		return fmt.Errorf("expected %d recovery codes, got %d", count, len(codes))
+	}
+	return nil
+}
+
+// rememberRecoveryCodes keeps the recovery codes from the last response
+func (tc *testContext) rememberRecoveryCodes() {
+	codes, ok := tc.responseBody["recovery_codes"].([]interface{})
+	if !ok {
+		return
+	}
+	tc.recoveryCodes = nil
+	for _, code := range codes {
+		if s, ok := code.(string); ok {
+			tc.recoveryCodes = append(tc.recoveryCodes, s)
+		}
+	}
+}
+
+// nextTOTPCode returns the authenticator code for the current time step, or
+// for the step after the last accepted one since codes cannot be reused
+func (tc *testContext) nextTOTPCode() (string, error) {
+	if tc.totpSecret == "" {
+		return "", fmt.Errorf("no authenticator has been enrolled")
+	}
+
+	step := auth.TOTPStep(time.Now())
+	if lastUsed := tc.totpRepo.lastUsedStep(tc.totpSecret); lastUsed >= step {
+		step = lastUsed + 1
+	}
+
+	return auth.TOTPCode(tc.totpSecret, step)
+}
+
+// registerMFASteps registers the two-factor authentication step definitions
+func registerMFASteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I enroll an authenticator app$`, tc.iEnrollAnAuthenticatorApp)
+	ctx.Step(`^I confirm the authenticator with a valid code$`, tc.iConfirmTheAuthenticatorWithAValidCode)
+	ctx.Step(`^I confirm the authenticator with code "([^"]*)"$`, tc.iConfirmTheAuthenticatorWithCode)
+	ctx.Step(`^I have enabled two-factor authentication$`, tc.iHaveEnabledTwoFactorAuthentication)
+	ctx.Step(`^I verify the login with a valid authenticator code$`, tc.iVerifyTheLoginWithAValidAuthenticatorCode)
+	ctx.Step(`^I verify the login with the same authenticator code again$`, tc.iVerifyTheLoginWithTheSameAuthenticatorCodeAgain)
+	ctx.Step(`^I verify the login with a recovery code$`, tc.iVerifyTheLoginWithARecoveryCode)
+	ctx.Step(`^I verify the login with an old recovery code$`, tc.iVerifyTheLoginWithAnOldRecoveryCode)
+	ctx.Step(`^I verify the login with code "([^"]*)"$`, tc.iVerifyTheLoginWithCode)
+	ctx.Step(`^I verify the challenge "([^"]*)" with code "([^"]*)"$`, tc.iVerifyTheChallengeWithCode)
+	ctx.Step(`^I fail to verify the login (\d+) times?$`, tc.iFailToVerifyTheLoginTimes)
+	ctx.Step(`^I fail to verify the login (\d+) times in parallel$`, tc.iFailToVerifyTheLoginTimesInParallel)
+	ctx.Step(`^I disable two-factor authentication with password "([^"]*)"$`, tc.iDisableTwoFactorAuthenticationWithPassword)
+	ctx.Step(`^I disable two-factor authentication with password "([^"]*)" and code "([^"]*)"$`, tc.iDisableTwoFactorAuthenticationWithPasswordAndCode)
+	ctx.Step(`^I regenerate my recovery codes with password "([^"]*)"$`, tc.iRegenerateMyRecoveryCodesWithPassword)
+	ctx.Step(`^I regenerate my recovery codes with password "([^"]*)" and a recovery code$`, tc.iRegenerateMyRecoveryCodesWithPasswordAndARecoveryCode)
+	ctx.Step(`^I regenerate my recovery codes with password "([^"]*)" and code "([^"]*)"$`, tc.iRegenerateMyRecoveryCodesWithPasswordAndCode)
+	ctx.Step(`^I request my two-factor authentication status$`, tc.iRequestMyTwoFactorAuthenticationStatus)
+	ctx.Step(`^the response should list (\d+) recovery codes$`, tc.theResponseShouldListRecoveryCodes)
+}
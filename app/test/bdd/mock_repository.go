@@ -1,17 +1,27 @@
 package bdd
 
 import (
+	"cmp"
 	"context"
-	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
 )
 
 // mockUserRepository is an in-memory implementation for testing
 type mockUserRepository struct {
 	mu    sync.RWMutex
 	users map[string]*entity.User // keyed by ID
+	// onDelete mimics ON DELETE CASCADE for data owned by the user
+	onDelete func(userID string)
 }
 
 func newMockUserRepository() *mockUserRepository {
@@ -32,7 +42,7 @@ func (r *mockUserRepository) Create(ctx context.Context, user *entity.User) erro
 
 	// Check for duplicate email
 	for _, u := range r.users {
-		if u.Email == user.Email {
+		if strings.EqualFold(u.Email, user.Email) {
 			return entity.ErrEmailExists
 		}
 	}
@@ -46,8 +56,8 @@ func (r *mockUserRepository) GetByID(ctx context.Context, id string) (*entity.Us
 	defer r.mu.RUnlock()
 
 	user, ok := r.users[id]
-	if !ok {
-		return nil, errors.New("user not found")
+	if !ok || user.IsDeleted() {
+		return nil, entity.ErrUserNotFound
 	}
 	return user, nil
 }
@@ -57,11 +67,34 @@ func (r *mockUserRepository) GetByEmail(ctx context.Context, email string) (*ent
 	defer r.mu.RUnlock()
 
 	for _, user := range r.users {
-		if user.Email == email {
+		if strings.EqualFold(user.Email, email) && !user.IsDeleted() {
+			return user, nil
+		}
+	}
+	return nil, entity.ErrUserNotFound
+}
+
+func (r *mockUserRepository) GetDeletedByID(ctx context.Context, id string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok || !user.IsDeleted() {
+		return nil, entity.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r *mockUserRepository) GetDeletedByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) && user.IsDeleted() {
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, entity.ErrUserNotFound
 }
 
 func (r *mockUserRepository) Update(ctx context.Context, user *entity.User) error {
@@ -69,19 +102,1331 @@ func (r *mockUserRepository) Update(ctx context.Context, user *entity.User) erro
 	defer r.mu.Unlock()
 
 	if _, ok := r.users[user.ID]; !ok {
-		return errors.New("user not found")
+		return entity.ErrUserNotFound
+	}
+	for id, u := range r.users {
+		if id != user.ID && strings.EqualFold(u.Email, user.Email) {
+			return entity.ErrEmailExists
+		}
 	}
 	r.users[user.ID] = user
 	return nil
 }
 
+func (r *mockUserRepository) List(ctx context.Context, filter output.UserFilter) ([]*entity.User, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := strings.ToLower(filter.Query)
+	matches := make([]*entity.User, 0)
+	for _, user := range r.users {
+		if !strings.Contains(strings.ToLower(user.Email), query) {
+			continue
+		}
+		if filter.Role != "" && user.Role != filter.Role {
+			continue
+		}
+		if user.IsDeleted() != filter.Deleted {
+			continue
+		}
+		matches = append(matches, user)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.Before(matches[j].CreatedAt)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	total := len(matches)
+	start := min(filter.Offset, total)
+	end := min(start+filter.Limit, total)
+	return matches[start:end], total, nil
+}
+
 func (r *mockUserRepository) Delete(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, ok := r.users[id]; !ok {
-		return errors.New("user not found")
+		return entity.ErrUserNotFound
 	}
 	delete(r.users, id)
+	if r.onDelete != nil {
+		r.onDelete(id)
+	}
+	return nil
+}
+
+func (r *mockUserRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, user := range r.users {
+		if user.IsDeleted() && user.DeletedAt.Before(deletedBefore) {
+			delete(r.users, id)
+			if r.onDelete != nil {
+				r.onDelete(id)
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// ageDeletion moves the deletion of a user into the past
+func (r *mockUserRepository) ageDeletion(email string, age time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) && user.IsDeleted() {
+			deletedAt := user.DeletedAt.Add(-age)
+			user.DeletedAt = &deletedAt
+			return nil
+		}
+	}
+	return fmt.Errorf("user %s is not deleted", email)
+}
+
+// mockTodoRepository is an in-memory implementation for testing
+type mockTodoRepository struct {
+	mu        sync.RWMutex
+	todos     map[string]*entity.Todo // keyed by ID
+	sequences map[string]int          // last code number, keyed by user ID and year
+	// tags backs mockTagRepository, so that tag changes and todo writes
+	// share one lock like they share a transaction in Postgres
+	tags map[string]*entity.Tag // keyed by ID
+	// history receives the status changes saved with todos
+	history *mockTodoStatusHistoryRepository
+}
+
+func newMockTodoRepository(history *mockTodoStatusHistoryRepository) *mockTodoRepository {
+	return &mockTodoRepository{
+		todos:     make(map[string]*entity.Todo),
+		sequences: make(map[string]int),
+		tags:      make(map[string]*entity.Tag),
+		history:   history,
+	}
+}
+
+func (r *mockTodoRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.todos = make(map[string]*entity.Todo)
+	r.sequences = make(map[string]int)
+	r.tags = make(map[string]*entity.Tag)
+}
+
+// ensureTags creates the tags a todo names that its owner does not have yet
+func (r *mockTodoRepository) ensureTags(todo *entity.Todo) {
+	for _, name := range todo.Tags {
+		if r.findTagByName(todo.UserID, name) == nil {
+			now := time.Now()
+			id := uuid.New().String()
+			r.tags[id] = &entity.Tag{
+				ID:        id,
+				UserID:    todo.UserID,
+				Name:      name,
+				Color:     entity.DefaultTagColor,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+		}
+	}
+}
+
+// findTagByName returns the user's tag with the given name, if any
+func (r *mockTodoRepository) findTagByName(userID, name string) *entity.Tag {
+	for _, tag := range r.tags {
+		if tag.UserID == userID && tag.Name == name {
+			return tag
+		}
+	}
+	return nil
+}
+
+// relabel rewrites the tag names of the user's todos labelled with a tag.
+// Todos get new slices, since copies handed out share the old ones.
+func (r *mockTodoRepository) relabel(userID, name string, rewrite func(tags []string) []string) {
+	for _, todo := range r.todos {
+		if todo.UserID == userID && todo.HasTag(name) {
+			todo.Tags = rewrite(slices.Clone(todo.Tags))
+		}
+	}
+}
+
+// find returns the user's todo with the given ID or short code
+func (r *mockTodoRepository) find(userID, key string) (*entity.Todo, bool) {
+	for _, todo := range r.todos {
+		if todo.UserID == userID && (todo.ID == key || todo.Code == key) {
+			return todo, true
+		}
+	}
+	return nil, false
+}
+
+func (r *mockTodoRepository) Create(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sequence := fmt.Sprintf("%s/%d", todo.UserID, todo.CodeYear())
+	r.sequences[sequence]++
+	todo.AssignCode(r.sequences[sequence])
+
+	stored := *todo
+	r.todos[todo.ID] = &stored
+	r.ensureTags(todo)
+	r.history.record(change)
+	return nil
+}
+
+func (r *mockTodoRepository) GetByID(ctx context.Context, userID, id string) (*entity.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todo, ok := r.find(userID, id)
+	if !ok {
+		return nil, entity.ErrTodoNotFound
+	}
+	found := *todo
+	return &found, nil
+}
+
+func (r *mockTodoRepository) List(ctx context.Context, userID string, filter output.TodoFilter) ([]*entity.Todo, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todos := make([]*entity.Todo, 0)
+	for _, todo := range r.todos {
+		if todo.UserID == userID && matchesTodoFilter(todo, filter) {
+			found := *todo
+			todos = append(todos, &found)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool {
+		return compareTodoPositions(todoPosition(todos[i], filter.Sort), todoPosition(todos[j], filter.Sort), filter) < 0
+	})
+
+	total := len(todos)
+	if filter.After != nil {
+		start := sort.Search(len(todos), func(i int) bool {
+			return compareTodoPositions(todoPosition(todos[i], filter.Sort), *filter.After, filter) > 0
+		})
+		todos = todos[start:]
+	}
+	return todos[:min(filter.Limit, len(todos))], total, nil
+}
+
+// matchesTodoFilter applies a todo filter the way the SQL conditions do;
+// todos without a due date never match a due date bound
+func matchesTodoFilter(todo *entity.Todo, filter output.TodoFilter) bool {
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, todo.Status) {
+		return false
+	}
+	if len(filter.Priorities) > 0 && !slices.Contains(filter.Priorities, todo.Priority) {
+		return false
+	}
+	if len(filter.Tags) > 0 {
+		matched := 0
+		for _, tag := range filter.Tags {
+			if todo.HasTag(tag) {
+				matched++
+			}
+		}
+		if matched == 0 || (filter.MatchAllTags && matched < len(filter.Tags)) {
+			return false
+		}
+	}
+	if filter.DueBefore != nil && (todo.DueDate == nil || !todo.DueDate.Before(*filter.DueBefore)) {
+		return false
+	}
+	if filter.DueAfter != nil && (todo.DueDate == nil || !todo.DueDate.After(*filter.DueAfter)) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !todo.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	if filter.CreatedAfter != nil && !todo.CreatedAt.After(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.Overdue && !todo.IsOverdue() {
+		return false
+	}
+	if filter.Query != "" {
+		query := strings.ToLower(filter.Query)
+		description := ""
+		if todo.Description != nil {
+			description = *todo.Description
+		}
+		if !strings.Contains(strings.ToLower(todo.Title), query) && !strings.Contains(strings.ToLower(description), query) {
+			return false
+		}
+	}
+	return true
+}
+
+// todoPosition returns the sort value of a todo in the form of a cursor
+func todoPosition(todo *entity.Todo, sortField output.TodoSortField) output.TodoCursor {
+	position := output.TodoCursor{ID: todo.ID, Priority: todo.Priority}
+	switch sortField {
+	case output.TodoSortCreatedAt:
+		position.Time = &todo.CreatedAt
+	case output.TodoSortUpdatedAt:
+		position.Time = &todo.UpdatedAt
+	case output.TodoSortDueDate:
+		position.Time = todo.DueDate
+	}
+	return position
+}
+
+// compareTodoPositions orders two positions like the SQL ORDER BY: by sort
+// value then ID in the filter's direction, with missing due dates last
+func compareTodoPositions(a, b output.TodoCursor, filter output.TodoFilter) int {
+	var c int
+	switch {
+	case filter.Sort == output.TodoSortPriority:
+		c = cmp.Compare(a.Priority.Rank(), b.Priority.Rank())
+	case a.Time == nil && b.Time != nil:
+		return 1
+	case a.Time != nil && b.Time == nil:
+		return -1
+	case a.Time != nil && b.Time != nil:
+		c = a.Time.Compare(*b.Time)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if filter.Descending {
+		c = -c
+	}
+	return c
+}
+
+func (r *mockTodoRepository) Update(ctx context.Context, todo *entity.Todo, change *entity.TodoStatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.todos[todo.ID]
+	if !ok || existing.UserID != todo.UserID {
+		return entity.ErrTodoNotFound
+	}
+	stored := *todo
+	r.todos[todo.ID] = &stored
+	r.ensureTags(todo)
+	if change != nil {
+		r.history.record(change)
+	}
+	return nil
+}
+
+func (r *mockTodoRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	todo, ok := r.find(userID, id)
+	if !ok {
+		return entity.ErrTodoNotFound
+	}
+	delete(r.todos, todo.ID)
+	return nil
+}
+
+func (r *mockTodoRepository) deleteForUser(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, todo := range r.todos {
+		if todo.UserID == userID {
+			delete(r.todos, id)
+		}
+	}
+	for id, tag := range r.tags {
+		if tag.UserID == userID {
+			delete(r.tags, id)
+		}
+	}
+}
+
+// mockTagRepository is an in-memory implementation for testing. It keeps its
+// tags in the todo mock, whose todos it relabels.
+type mockTagRepository struct {
+	todos *mockTodoRepository
+}
+
+func newMockTagRepository(todos *mockTodoRepository) *mockTagRepository {
+	return &mockTagRepository{todos: todos}
+}
+
+// withCount returns a copy of a tag with the number of todos labelled with it
+func (r *mockTagRepository) withCount(tag *entity.Tag) *entity.Tag {
+	found := *tag
+	found.TodoCount = 0
+	for _, todo := range r.todos.todos {
+		if todo.UserID == tag.UserID && todo.HasTag(tag.Name) {
+			found.TodoCount++
+		}
+	}
+	return &found
+}
+
+// find returns one of the user's tags
+func (r *mockTagRepository) find(userID, id string) (*entity.Tag, error) {
+	tag, ok := r.todos.tags[id]
+	if !ok || tag.UserID != userID {
+		return nil, entity.ErrTagNotFound
+	}
+	return tag, nil
+}
+
+func (r *mockTagRepository) Create(ctx context.Context, tag *entity.Tag) error {
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	if r.todos.findTagByName(tag.UserID, tag.Name) != nil {
+		return entity.ErrTagExists
+	}
+	stored := *tag
+	r.todos.tags[tag.ID] = &stored
+	return nil
+}
+
+func (r *mockTagRepository) GetByID(ctx context.Context, userID, id string) (*entity.Tag, error) {
+	r.todos.mu.RLock()
+	defer r.todos.mu.RUnlock()
+
+	tag, err := r.find(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.withCount(tag), nil
+}
+
+func (r *mockTagRepository) List(ctx context.Context, userID string) ([]*entity.Tag, error) {
+	r.todos.mu.RLock()
+	defer r.todos.mu.RUnlock()
+
+	tags := make([]*entity.Tag, 0)
+	for _, tag := range r.todos.tags {
+		if tag.UserID == userID {
+			tags = append(tags, r.withCount(tag))
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+func (r *mockTagRepository) Update(ctx context.Context, tag *entity.Tag) error {
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	existing, err := r.find(tag.UserID, tag.ID)
+	if err != nil {
+		return err
+	}
+	if other := r.todos.findTagByName(tag.UserID, tag.Name); other != nil && other.ID != tag.ID {
+		return entity.ErrTagExists
+	}
+
+	if existing.Name != tag.Name {
+		r.todos.relabel(tag.UserID, existing.Name, func(tags []string) []string {
+			tags[slices.Index(tags, existing.Name)] = tag.Name
+			return tags
+		})
+	}
+
+	stored := *tag
+	r.todos.tags[tag.ID] = &stored
+	return nil
+}
+
+func (r *mockTagRepository) Merge(ctx context.Context, userID, sourceID, targetID string) error {
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	source, err := r.find(userID, sourceID)
+	if err != nil {
+		return err
+	}
+	target, err := r.find(userID, targetID)
+	if err != nil {
+		return err
+	}
+
+	r.todos.relabel(userID, source.Name, func(tags []string) []string {
+		i := slices.Index(tags, source.Name)
+		if slices.Contains(tags, target.Name) {
+			return slices.Delete(tags, i, i+1)
+		}
+		tags[i] = target.Name
+		return tags
+	})
+	delete(r.todos.tags, sourceID)
+	return nil
+}
+
+func (r *mockTagRepository) Delete(ctx context.Context, userID, id string) error {
+	r.todos.mu.Lock()
+	defer r.todos.mu.Unlock()
+
+	tag, err := r.find(userID, id)
+	if err != nil {
+		return err
+	}
+
+	r.todos.relabel(userID, tag.Name, func(tags []string) []string {
+		i := slices.Index(tags, tag.Name)
+		return slices.Delete(tags, i, i+1)
+	})
+	delete(r.todos.tags, id)
+	return nil
+}
+
+// mockTodoStatusHistoryRepository is an in-memory implementation for testing
+type mockTodoStatusHistoryRepository struct {
+	mu      sync.RWMutex
+	changes []*entity.TodoStatusChange // in the order they were recorded
+}
+
+func newMockTodoStatusHistoryRepository() *mockTodoStatusHistoryRepository {
+	return &mockTodoStatusHistoryRepository{}
+}
+
+func (r *mockTodoStatusHistoryRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = nil
+}
+
+// record stores a status change saved with its todo
+func (r *mockTodoStatusHistoryRepository) record(change *entity.TodoStatusChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *change
+	r.changes = append(r.changes, &stored)
+}
+
+func (r *mockTodoStatusHistoryRepository) ListByTodoID(ctx context.Context, todoID string) ([]*entity.TodoStatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := make([]*entity.TodoStatusChange, 0)
+	for _, change := range r.changes {
+		if change.TodoID == todoID {
+			found := *change
+			changes = append(changes, &found)
+		}
+	}
+	return changes, nil
+}
+
+// mockRefreshTokenRepository is an in-memory implementation for testing
+type mockRefreshTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*entity.RefreshToken // keyed by ID
+}
+
+func newMockRefreshTokenRepository() *mockRefreshTokenRepository {
+	return &mockRefreshTokenRepository{
+		tokens: make(map[string]*entity.RefreshToken),
+	}
+}
+
+func (r *mockRefreshTokenRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = make(map[string]*entity.RefreshToken)
+}
+
+func (r *mockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *mockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrInvalidRefreshToken
+}
+
+func (r *mockRefreshTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok {
+		return entity.ErrInvalidRefreshToken
+	}
+	if token.UsedAt != nil {
+		return entity.ErrRefreshTokenReused
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return nil
+}
+
+func (r *mockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
 	return nil
 }
+
+func (r *mockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *mockRefreshTokenRepository) PurgeExpired(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, token := range r.tokens {
+		if token.IsExpired() {
+			delete(r.tokens, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// mockOneTimeTokenRepository is an in-memory implementation for testing
+type mockOneTimeTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*entity.OneTimeToken // keyed by ID
+}
+
+func newMockOneTimeTokenRepository() *mockOneTimeTokenRepository {
+	return &mockOneTimeTokenRepository{
+		tokens: make(map[string]*entity.OneTimeToken),
+	}
+}
+
+func (r *mockOneTimeTokenRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = make(map[string]*entity.OneTimeToken)
+}
+
+func (r *mockOneTimeTokenRepository) Create(ctx context.Context, token *entity.OneTimeToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *mockOneTimeTokenRepository) GetByHash(ctx context.Context, purpose entity.TokenPurpose, tokenHash string) (*entity.OneTimeToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrOneTimeTokenNotFound
+}
+
+func (r *mockOneTimeTokenRepository) MarkUsed(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok {
+		return entity.ErrOneTimeTokenNotFound
+	}
+	if token.UsedAt != nil {
+		return entity.ErrOneTimeTokenUsed
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return nil
+}
+
+func (r *mockOneTimeTokenRepository) DeleteForUser(ctx context.Context, userID string, purpose entity.TokenPurpose) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose {
+			delete(r.tokens, id)
+		}
+	}
+	return nil
+}
+
+func (r *mockOneTimeTokenRepository) PurgeExpired(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, token := range r.tokens {
+		if token.IsExpired() {
+			delete(r.tokens, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// mockTOTPRepository is an in-memory implementation for testing
+type mockTOTPRepository struct {
+	mu      sync.Mutex
+	factors map[string]*entity.TOTPFactor // keyed by user ID
+}
+
+func newMockTOTPRepository() *mockTOTPRepository {
+	return &mockTOTPRepository{
+		factors: make(map[string]*entity.TOTPFactor),
+	}
+}
+
+func (r *mockTOTPRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factors = make(map[string]*entity.TOTPFactor)
+}
+
+// lastUsedStep returns the last accepted time step of the factor with the given secret
+func (r *mockTOTPRepository) lastUsedStep(secret string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, factor := range r.factors {
+		if factor.Secret == secret {
+			return factor.LastUsedStep
+		}
+	}
+	return 0
+}
+
+func (r *mockTOTPRepository) Save(ctx context.Context, factor *entity.TOTPFactor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *factor
+	r.factors[factor.UserID] = &stored
+	return nil
+}
+
+func (r *mockTOTPRepository) GetByUserID(ctx context.Context, userID string) (*entity.TOTPFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factor, ok := r.factors[userID]
+	if !ok {
+		return nil, entity.ErrTOTPNotFound
+	}
+	found := *factor
+	return &found, nil
+}
+
+func (r *mockTOTPRepository) MarkStepUsed(ctx context.Context, userID string, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	factor, ok := r.factors[userID]
+	if !ok {
+		return entity.ErrTOTPNotFound
+	}
+	if step <= factor.LastUsedStep {
+		return entity.ErrMFACodeReused
+	}
+	factor.LastUsedStep = step
+	return nil
+}
+
+func (r *mockTOTPRepository) Delete(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.factors, userID)
+	return nil
+}
+
+// mockRecoveryCodeRepository is an in-memory implementation for testing
+type mockRecoveryCodeRepository struct {
+	mu    sync.Mutex
+	codes map[string]*entity.RecoveryCode // keyed by ID
+}
+
+func newMockRecoveryCodeRepository() *mockRecoveryCodeRepository {
+	return &mockRecoveryCodeRepository{
+		codes: make(map[string]*entity.RecoveryCode),
+	}
+}
+
+func (r *mockRecoveryCodeRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes = make(map[string]*entity.RecoveryCode)
+}
+
+func (r *mockRecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID string, codes []*entity.RecoveryCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, id)
+		}
+	}
+	for _, code := range codes {
+		stored := *code
+		r.codes[code.ID] = &stored
+	}
+	return nil
+}
+
+func (r *mockRecoveryCodeRepository) GetByHash(ctx context.Context, userID, codeHash string) (*entity.RecoveryCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, code := range r.codes {
+		if code.UserID == userID && code.CodeHash == codeHash {
+			found := *code
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrRecoveryCodeNotFound
+}
+
+func (r *mockRecoveryCodeRepository) MarkUsed(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code, ok := r.codes[id]
+	if !ok {
+		return entity.ErrRecoveryCodeNotFound
+	}
+	if code.UsedAt != nil {
+		return entity.ErrRecoveryCodeUsed
+	}
+	now := time.Now()
+	code.UsedAt = &now
+	return nil
+}
+
+func (r *mockRecoveryCodeRepository) CountUnused(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, code := range r.codes {
+		if code.UserID == userID && code.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *mockRecoveryCodeRepository) DeleteForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, id)
+		}
+	}
+	return nil
+}
+
+// mockUserIdentityRepository is an in-memory implementation for testing
+type mockUserIdentityRepository struct {
+	mu         sync.Mutex
+	identities map[string]*entity.UserIdentity // keyed by ID
+}
+
+func newMockUserIdentityRepository() *mockUserIdentityRepository {
+	return &mockUserIdentityRepository{
+		identities: make(map[string]*entity.UserIdentity),
+	}
+}
+
+func (r *mockUserIdentityRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.identities = make(map[string]*entity.UserIdentity)
+}
+
+func (r *mockUserIdentityRepository) Create(ctx context.Context, identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return entity.ErrIdentityAlreadyLinked
+		}
+		if existing.UserID == identity.UserID && existing.Provider == identity.Provider {
+			return entity.ErrProviderAlreadyLinked
+		}
+	}
+
+	stored := *identity
+	r.identities[identity.ID] = &stored
+	return nil
+}
+
+func (r *mockUserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			found := *identity
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrIdentityNotFound
+}
+
+func (r *mockUserIdentityRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identities := make([]*entity.UserIdentity, 0)
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			found := *identity
+			identities = append(identities, &found)
+		}
+	}
+	return identities, nil
+}
+
+func (r *mockUserIdentityRepository) Delete(ctx context.Context, userID, provider string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, identity := range r.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			delete(r.identities, id)
+			return nil
+		}
+	}
+	return entity.ErrIdentityNotFound
+}
+
+func (r *mockUserIdentityRepository) deleteForUser(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, identity := range r.identities {
+		if identity.UserID == userID {
+			delete(r.identities, id)
+		}
+	}
+}
+
+// mockOIDCAuthRequestRepository is an in-memory implementation for testing
+type mockOIDCAuthRequestRepository struct {
+	mu       sync.Mutex
+	requests map[string]*entity.OIDCAuthRequest // keyed by ID
+}
+
+func newMockOIDCAuthRequestRepository() *mockOIDCAuthRequestRepository {
+	return &mockOIDCAuthRequestRepository{
+		requests: make(map[string]*entity.OIDCAuthRequest),
+	}
+}
+
+func (r *mockOIDCAuthRequestRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = make(map[string]*entity.OIDCAuthRequest)
+}
+
+func (r *mockOIDCAuthRequestRepository) Create(ctx context.Context, request *entity.OIDCAuthRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *request
+	r.requests[request.ID] = &stored
+	return nil
+}
+
+func (r *mockOIDCAuthRequestRepository) GetByStateHash(ctx context.Context, stateHash string) (*entity.OIDCAuthRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, request := range r.requests {
+		if request.StateHash == stateHash {
+			found := *request
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrOIDCAuthRequestNotFound
+}
+
+func (r *mockOIDCAuthRequestRepository) MarkUsed(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, ok := r.requests[id]
+	if !ok {
+		return entity.ErrOIDCAuthRequestNotFound
+	}
+	if request.UsedAt != nil {
+		return entity.ErrInvalidOIDCState
+	}
+	now := time.Now()
+	request.UsedAt = &now
+	return nil
+}
+
+func (r *mockOIDCAuthRequestRepository) PurgeExpired(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, request := range r.requests {
+		if request.IsExpired() {
+			delete(r.requests, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// mockPersonalAccessTokenRepository is an in-memory implementation for testing
+type mockPersonalAccessTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]*entity.PersonalAccessToken // keyed by ID
+}
+
+func newMockPersonalAccessTokenRepository() *mockPersonalAccessTokenRepository {
+	return &mockPersonalAccessTokenRepository{
+		tokens: make(map[string]*entity.PersonalAccessToken),
+	}
+}
+
+func (r *mockPersonalAccessTokenRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = make(map[string]*entity.PersonalAccessToken)
+}
+
+func (r *mockPersonalAccessTokenRepository) Create(ctx context.Context, token *entity.PersonalAccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *token
+	r.tokens[token.ID] = &stored
+	return nil
+}
+
+func (r *mockPersonalAccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, entity.ErrPersonalAccessTokenNotFound
+}
+
+func (r *mockPersonalAccessTokenRepository) ListByUserID(ctx context.Context, userID string) ([]*entity.PersonalAccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tokens := make([]*entity.PersonalAccessToken, 0)
+	for _, token := range r.tokens {
+		if token.UserID == userID && !token.IsRevoked() {
+			found := *token
+			tokens = append(tokens, &found)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (r *mockPersonalAccessTokenRepository) Revoke(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.UserID != userID || token.IsRevoked() {
+		return entity.ErrPersonalAccessTokenNotFound
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	return nil
+}
+
+func (r *mockPersonalAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && !token.IsRevoked() {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *mockPersonalAccessTokenRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.tokens[id]; ok {
+		token.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+func (r *mockPersonalAccessTokenRepository) deleteForUser(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, id)
+		}
+	}
+}
+
+// expire moves the expiry of a token into the past
+func (r *mockPersonalAccessTokenRepository) expire(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.tokens[id]; ok {
+		past := time.Now().Add(-time.Minute)
+		token.ExpiresAt = &past
+	}
+}
+
+// mockSessionRepository is an in-memory implementation for testing
+type mockSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[string]*entity.Session // keyed by ID
+}
+
+func newMockSessionRepository() *mockSessionRepository {
+	return &mockSessionRepository{
+		sessions: make(map[string]*entity.Session),
+	}
+}
+
+func (r *mockSessionRepository) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions = make(map[string]*entity.Session)
+}
+
+func (r *mockSessionRepository) Create(ctx context.Context, session *entity.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func (r *mockSessionRepository) GetByID(ctx context.Context, id string) (*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, entity.ErrSessionNotFound
+	}
+	found := *session
+	return &found, nil
+}
+
+func (r *mockSessionRepository) ListActiveByUserID(ctx context.Context, userID string, seenSince time.Time) ([]*entity.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := make([]*entity.Session, 0)
+	for _, session := range r.sessions {
+		if session.UserID == userID && !session.IsRevoked() && !session.LastSeenAt.Before(seenSince) {
+			found := *session
+			sessions = append(sessions, &found)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (r *mockSessionRepository) Touch(ctx context.Context, id string, seenAt time.Time, ipAddress string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[id]; ok {
+		if seenAt.After(session.LastSeenAt) {
+			session.LastSeenAt = seenAt
+		}
+		if ipAddress != "" {
+			session.IPAddress = ipAddress
+		}
+	}
+	return nil
+}
+
+func (r *mockSessionRepository) Revoke(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.IsRevoked() {
+		return entity.ErrSessionNotFound
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return nil
+}
+
+func (r *mockSessionRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.UserID == userID && !session.IsRevoked() {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *mockSessionRepository) PurgeEnded(ctx context.Context, seenBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, session := range r.sessions {
+		if session.IsRevoked() || session.LastSeenAt.Before(seenBefore) {
+			delete(r.sessions, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *mockSessionRepository) deleteForUser(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, session := range r.sessions {
+		if session.UserID == userID {
+			delete(r.sessions, id)
+		}
+	}
+}
+
+// mockMailer records sent messages for testing
+type mockMailer struct {
+	mu       sync.Mutex
+	messages []output.Message
+	// pending waits for messages still being sent in the background
+	pending func()
+}
+
+func newMockMailer() *mockMailer {
+	return &mockMailer{}
+}
+
+func (m *mockMailer) clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
+
+func (m *mockMailer) Send(ctx context.Context, msg output.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// lastMessageTo returns the most recent message sent to the address
+func (m *mockMailer) lastMessageTo(to string) (output.Message, bool) {
+	m.waitForPending()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if sameAddress(m.messages[i].To, to) {
+			return m.messages[i], true
+		}
+	}
+	return output.Message{}, false
+}
+
+// countMessagesTo returns how many messages were sent to the address
+func (m *mockMailer) countMessagesTo(to string) int {
+	m.waitForPending()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, msg := range m.messages {
+		if sameAddress(msg.To, to) {
+			count++
+		}
+	}
+	return count
+}
+
+// waitForPending lets background sends finish before messages are inspected
+func (m *mockMailer) waitForPending() {
+	if m.pending != nil {
+		m.pending()
+	}
+}
+
+// sameAddress compares email addresses the way the application stores them
+func sameAddress(a, b string) bool {
+	normalizedA, errA := entity.NormalizeEmail(a)
+	normalizedB, errB := entity.NormalizeEmail(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(normalizedA, normalizedB)
+}
@@ -0,0 +1,241 @@
+package bdd
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+	"github.com/twaydev/golang-todolist/app/internal/domain/port/output"
+)
+
+// Search approximates the Postgres full-text search closely enough for the
+// scenarios: English words lose common suffixes and stop words, Vietnamese
+// words lose their diacritics, and fields are weighted like ts_rank_cd's
+// default weights for A, B and C.
+func (r *mockTodoRepository) Search(ctx context.Context, userID string, search output.TodoSearch) ([]*entity.TodoSearchResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := make([]searchTerm, 0, len(search.Terms))
+	for _, term := range search.Terms {
+		words := searchWords(term.Text, search.Language)
+		// Terms of only stop words match everything, as in Postgres
+		if len(words) > 0 {
+			terms = append(terms, searchTerm{words: words, phrase: term.Phrase, prefix: term.Prefix})
+		}
+	}
+	if len(terms) == 0 {
+		return []*entity.TodoSearchResult{}, nil
+	}
+
+	results := make([]*entity.TodoSearchResult, 0)
+	for _, todo := range r.todos {
+		if todo.UserID != userID {
+			continue
+		}
+
+		description := ""
+		if todo.Description != nil {
+			description = *todo.Description
+		}
+		fields := []searchField{
+			{words: searchWords(todo.Title, search.Language), weight: 1.0},
+			{words: searchWords(strings.Join(todo.Tags, " "), search.Language), weight: 0.4},
+			{words: searchWords(description, search.Language), weight: 0.2},
+		}
+
+		rank, matched := 0.0, true
+		for _, term := range terms {
+			termRank := 0.0
+			for _, field := range fields {
+				if term.matches(field.words) {
+					termRank += field.weight
+				}
+			}
+			if termRank == 0 {
+				matched = false
+				break
+			}
+			rank += termRank
+		}
+		if !matched {
+			continue
+		}
+
+		found := *todo
+		result := &entity.TodoSearchResult{Todo: &found, Rank: rank}
+		result.TitleHighlight = highlightWords(todo.Title, terms, search.Language)
+		result.DescriptionHighlight = highlightWords(description, terms, search.Language)
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		if !a.Todo.UpdatedAt.Equal(b.Todo.UpdatedAt) {
+			return a.Todo.UpdatedAt.After(b.Todo.UpdatedAt)
+		}
+		return a.Todo.ID > b.Todo.ID
+	})
+	return results[:min(search.Limit, len(results))], nil
+}
+
+// searchTerm is a search term split into normalized words
+type searchTerm struct {
+	words  []string
+	phrase bool
+	prefix bool
+}
+
+// searchField is the normalized words of a todo field and its rank weight
+type searchField struct {
+	words  []string
+	weight float64
+}
+
+// matches reports whether the words of a field satisfy the term
+func (t searchTerm) matches(words []string) bool {
+	if t.phrase {
+		for start := 0; start+len(t.words) <= len(words); start++ {
+			if equalWords(words[start:start+len(t.words)], t.words) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, want := range t.words {
+		if !containsWord(words, want, t.prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesWord reports whether a single normalized word is one the term looks for
+func (t searchTerm) matchesWord(word string) bool {
+	return containsWord(t.words, word, false) || (t.prefix && strings.HasPrefix(word, t.words[0]))
+}
+
+func containsWord(words []string, want string, prefix bool) bool {
+	for _, word := range words {
+		if word == want || (prefix && strings.HasPrefix(word, want)) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightWords HTML-escapes text and wraps the words that the terms look
+// for in <mark> tags, or returns an empty string when none match
+func highlightWords(text string, terms []searchTerm, language entity.SearchLanguage) string {
+	var out strings.Builder
+	highlighted := false
+
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		if end == start {
+			out.WriteString(html.EscapeString(string(runes[start])))
+			start++
+			continue
+		}
+
+		word := string(runes[start:end])
+		marked := false
+		for _, normalized := range searchWords(word, language) {
+			for _, term := range terms {
+				if term.matchesWord(normalized) {
+					marked = true
+				}
+			}
+		}
+		if marked {
+			highlighted = true
+			out.WriteString("<mark>" + word + "</mark>")
+		} else {
+			out.WriteString(word)
+		}
+		start = end
+	}
+
+	if !highlighted {
+		return ""
+	}
+	return out.String()
+}
+
+// englishStopWords are dropped from English text, like Postgres does
+var englishStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "it": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// searchWords splits text into lowercase words normalized for the language
+func searchWords(text string, language entity.SearchLanguage) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+
+	words := make([]string, 0, len(fields))
+	for _, word := range fields {
+		switch language {
+		case entity.SearchLanguageVietnamese:
+			words = append(words, unaccent(word))
+		default:
+			if !englishStopWords[word] {
+				words = append(words, stemEnglish(word))
+			}
+		}
+	}
+	return words
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// stemEnglish strips the most common English suffixes
+func stemEnglish(word string) string {
+	for _, suffix := range []string{"ing", "es", "ed", "s"} {
+		if stem, ok := strings.CutSuffix(word, suffix); ok && len(stem) >= 3 {
+			return stem
+		}
+	}
+	return word
+}
+
+// unaccent removes diacritics, including the stroke of the Vietnamese đ
+func unaccent(word string) string {
+	var out strings.Builder
+	for _, r := range norm.NFD.String(word) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == 'đ':
+			out.WriteRune('d')
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,132 @@
+package bdd
+
+import (
+	"fmt"
+
+	"github.com/cucumber/godog"
+)
+
+// Identity provider step definitions
+
+func (tc *testContext) theIdentityProviderSignsInWithSubject(email, subject string) error {
+	tc.idp.signIn(stubUser{subject: subject, email: email, emailVerified: true})
+	return nil
+}
+
+func (tc *testContext) theIdentityProviderSignsInWithSubjectWithoutVerifyingTheEmail(email, subject string) error {
+	tc.idp.signIn(stubUser{subject: subject, email: email})
+	return nil
+}
+
+func (tc *testContext) theIdentityProviderDeniesTheSignIn() error {
+	tc.idp.denySignIn()
+	return nil
+}
+
+func (tc *testContext) iSignInWithTheIdentityProvider() error {
+	return tc.iSignInWithIdentityProvider("stub")
+}
+
+func (tc *testContext) iSignInWithIdentityProvider(provider string) error {
+	// The client follows the redirects through the provider back to the callback
+	if err := tc.makeGetRequest("/auth/oidc/"+provider+"/authorize", ""); err != nil {
+		return err
+	}
+	tc.rememberLogin()
+	return nil
+}
+
+func (tc *testContext) iReplayTheIdentityProviderCallback() error {
+	callback := tc.idp.lastCallbackURL()
+	if callback == "" {
+		return fmt.Errorf("the identity provider has not redirected back yet")
+	}
+	return tc.makeGetRequest(callback, "")
+}
+
+func (tc *testContext) iOpenTheIdentityProviderCallbackWithState(state string) error {
+	return tc.makeGetRequest("/auth/oidc/stub/callback?code=made-up-code&state="+state, "")
+}
+
+func (tc *testContext) iLinkTheIdentityProviderToMyAccount() error {
+	if err := tc.iStartLinkingTheIdentityProviderToMyAccount(); err != nil {
+		return err
+	}
+	if tc.authorizationURL == "" {
+		return nil
+	}
+	// The browser opens the provider without the API token; the state ties
+	// the callback to the account
+	return tc.makeGetRequest(tc.authorizationURL, "")
+}
+
+func (tc *testContext) iStartLinkingTheIdentityProviderToMyAccount() error {
+	if err := tc.makeRequest("POST", "/api/v1/me/identities/stub", nil, tc.authToken); err != nil {
+		return err
+	}
+	tc.authorizationURL, _ = tc.responseBody["authorization_url"].(string)
+	return nil
+}
+
+func (tc *testContext) theLinkIsOpenedInAnotherBrowser() error {
+	if tc.authorizationURL == "" {
+		return fmt.Errorf("no identity provider link was started")
+	}
+	tc.cookies = newCookieJar()
+	return tc.makeGetRequest(tc.authorizationURL, "")
+}
+
+func (tc *testContext) iListMyLinkedIdentities() error {
+	return tc.makeGetRequest("/api/v1/me/identities", tc.authToken)
+}
+
+func (tc *testContext) iUnlinkTheIdentityProvider() error {
+	return tc.makeRequest("DELETE", "/api/v1/me/identities/stub", nil, tc.authToken)
+}
+
+func (tc *testContext) iListTheIdentityProviders() error {
+	return tc.makeGetRequest("/auth/oidc/providers", "")
+}
+
+func (tc *testContext) theResponseShouldListLinkedIdentities(count int) error {
+	identities, ok := tc.responseBody["identities"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not list identities: %v", tc.responseBody)
+	}
+	if len(identities) != count {
+		return fmt.Errorf("expected %d identities, got %d: %v", count, len(identities), identities)
+	}
+	return nil
+}
+
+// rememberLogin keeps the tokens or MFA challenge from a login response
+func (tc *testContext) rememberLogin() {
+	if token, ok := tc.responseBody["token"].(string); ok {
+		tc.previousAuthToken = tc.authToken
+		tc.authToken = token
+	}
+	if refreshToken, ok := tc.responseBody["refresh_token"].(string); ok {
+		tc.refreshToken = refreshToken
+	}
+	if mfaToken, ok := tc.responseBody["mfa_token"].(string); ok {
+		tc.mfaToken = mfaToken
+	}
+}
+
+// registerOIDCSteps registers the identity provider step definitions
+func registerOIDCSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^the identity provider signs in "([^"]*)" with subject "([^"]*)"$`, tc.theIdentityProviderSignsInWithSubject)
+	ctx.Step(`^the identity provider signs in "([^"]*)" with subject "([^"]*)" without verifying the email$`, tc.theIdentityProviderSignsInWithSubjectWithoutVerifyingTheEmail)
+	ctx.Step(`^the identity provider denies the sign-in$`, tc.theIdentityProviderDeniesTheSignIn)
+	ctx.Step(`^I sign in with the identity provider$`, tc.iSignInWithTheIdentityProvider)
+	ctx.Step(`^I sign in with identity provider "([^"]*)"$`, tc.iSignInWithIdentityProvider)
+	ctx.Step(`^I replay the identity provider callback$`, tc.iReplayTheIdentityProviderCallback)
+	ctx.Step(`^I open the identity provider callback with state "([^"]*)"$`, tc.iOpenTheIdentityProviderCallbackWithState)
+	ctx.Step(`^I link the identity provider to my account$`, tc.iLinkTheIdentityProviderToMyAccount)
+	ctx.Step(`^I start linking the identity provider to my account$`, tc.iStartLinkingTheIdentityProviderToMyAccount)
+	ctx.Step(`^the link is opened in another browser$`, tc.theLinkIsOpenedInAnotherBrowser)
+	ctx.Step(`^I list my linked identities$`, tc.iListMyLinkedIdentities)
+	ctx.Step(`^I unlink the identity provider$`, tc.iUnlinkTheIdentityProvider)
+	ctx.Step(`^I list the identity providers$`, tc.iListTheIdentityProviders)
+	ctx.Step(`^the response should list (\d+) linked identit(?:y|ies)$`, tc.theResponseShouldListLinkedIdentities)
+}
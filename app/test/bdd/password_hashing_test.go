@@ -0,0 +1,83 @@
+package bdd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+
+	"github.com/twaydev/golang-todolist/app/internal/auth"
+)
+
+// Password hashing step definitions
+
+func (tc *testContext) aUserExistsWhosePasswordIsHashedWithBcryptCost(email, password string, cost int) error {
+	return tc.aUserExistsWithPasswordHashedBy(email, password, auth.NewBcryptHasher(cost))
+}
+
+func (tc *testContext) aUserExistsWhosePasswordIsHashedWithArgon2idMemory(email, password string, memory int) error {
+	params := auth.DefaultArgon2Params
+	params.Memory = uint32(memory)
+	return tc.aUserExistsWithPasswordHashedBy(email, password, auth.NewArgon2idHasher(params))
+}
+
+func (tc *testContext) theStoredPasswordHashForShouldUse(email, algorithm string) error {
+	hash, err := tc.storedPasswordHash(email)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(hash, "$"+algorithm+"$") && !(algorithm == "bcrypt" && strings.HasPrefix(hash, "$2")) {
+		return fmt.Errorf("expected a %s hash for %s, got %q", algorithm, email, hash)
+	}
+	return nil
+}
+
+func (tc *testContext) theStoredPasswordHashForShouldBeUpToDate(email string) error {
+	hash, err := tc.storedPasswordHash(email)
+	if err != nil {
+		return err
+	}
+	if tc.hasher.NeedsRehash(hash) {
+		return fmt.Errorf("expected the hash for %s to use the current parameters, got %q", email, hash)
+	}
+	return nil
+}
+
+// Helper methods
+
+// aUserExistsWithPasswordHashedBy creates a verified user whose stored hash was made by hasher
+func (tc *testContext) aUserExistsWithPasswordHashedBy(email, password string, hasher auth.PasswordHasher) error {
+	if err := tc.aUserExistsWithEmailAndPassword(email, password); err != nil {
+		return err
+	}
+
+	user, err := tc.userRepo.GetByEmail(context.Background(), email)
+	if err != nil {
+		return err
+	}
+
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = hash
+	return tc.userRepo.Update(context.Background(), user)
+}
+
+// storedPasswordHash returns the password hash stored for the user
+func (tc *testContext) storedPasswordHash(email string) (string, error) {
+	user, err := tc.userRepo.GetByEmail(context.Background(), email)
+	if err != nil {
+		return "", fmt.Errorf("user %s not found: %w", email, err)
+	}
+	return user.PasswordHash, nil
+}
+
+// registerPasswordHashingSteps registers the password hashing step definitions
+func registerPasswordHashingSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^a user exists with email "([^"]*)" whose password "([^"]*)" is hashed with bcrypt cost (\d+)$`, tc.aUserExistsWhosePasswordIsHashedWithBcryptCost)
+	ctx.Step(`^a user exists with email "([^"]*)" whose password "([^"]*)" is hashed with argon2id memory (\d+)$`, tc.aUserExistsWhosePasswordIsHashedWithArgon2idMemory)
+	ctx.Step(`^the stored password hash for "([^"]*)" should use "([^"]*)"$`, tc.theStoredPasswordHashForShouldUse)
+	ctx.Step(`^the stored password hash for "([^"]*)" should be up to date$`, tc.theStoredPasswordHashForShouldBeUpToDate)
+}
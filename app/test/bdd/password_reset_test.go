@@ -0,0 +1,71 @@
+package bdd
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cucumber/godog"
+)
+
+// Password reset step definitions
+
+func (tc *testContext) iRequestAPasswordResetFor(email string) error {
+	body := map[string]string{
+		"email": email,
+	}
+	return tc.makePostRequest("/auth/password/forgot", body)
+}
+
+func (tc *testContext) iRequestPasswordResetsFor(times int, email string) error {
+	for i := 0; i < times; i++ {
+		if err := tc.iRequestAPasswordResetFor(email); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 202 {
+			return fmt.Errorf("reset request %d returned %d: %v", i+1, tc.response.StatusCode, tc.responseBody)
+		}
+	}
+	return nil
+}
+
+func (tc *testContext) iRequestPasswordResetsForDifferentAddresses(count int) error {
+	for i := 0; i < count; i++ {
+		email := fmt.Sprintf("someone%d@example.com", i+1)
+		if err := tc.iRequestAPasswordResetFor(email); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 202 {
+			return fmt.Errorf("reset request for %s returned %d: %v", email, tc.response.StatusCode, tc.responseBody)
+		}
+	}
+	return nil
+}
+
+func (tc *testContext) iResetMyPasswordUsingTheLinkSentTo(password, email string) error {
+	if err := tc.openLinkSentTo(email); err != nil {
+		return err
+	}
+	return tc.submitTheForm(url.Values{"password": {password}})
+}
+
+func (tc *testContext) iResetMyPasswordUsingTheRememberedLink(password string) error {
+	return tc.iResetMyPasswordWithToken(password, tc.rememberedEmailToken)
+}
+
+func (tc *testContext) iResetMyPasswordWithToken(password, token string) error {
+	body := map[string]string{
+		"token":    token,
+		"password": password,
+	}
+	return tc.makePostRequest("/auth/password/reset", body)
+}
+
+// registerPasswordResetSteps registers the password reset step definitions
+func registerPasswordResetSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I request a password reset for "([^"]*)"$`, tc.iRequestAPasswordResetFor)
+	ctx.Step(`^I request (\d+) password resets for "([^"]*)"$`, tc.iRequestPasswordResetsFor)
+	ctx.Step(`^I request password resets for (\d+) different addresses$`, tc.iRequestPasswordResetsForDifferentAddresses)
+	ctx.Step(`^I reset my password to "([^"]*)" using the link sent to "([^"]*)"$`, tc.iResetMyPasswordUsingTheLinkSentTo)
+	ctx.Step(`^I reset my password to "([^"]*)" using the remembered link$`, tc.iResetMyPasswordUsingTheRememberedLink)
+	ctx.Step(`^I reset my password to "([^"]*)" with token "([^"]*)"$`, tc.iResetMyPasswordWithToken)
+}
@@ -0,0 +1,150 @@
+package bdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/cucumber/godog"
+)
+
+// Rate limiting step definitions
+
+func (tc *testContext) iFailToLoginTimesAs(times int, email string) error {
+	for i := 0; i < times; i++ {
+		if err := tc.iLoginWithEmailAndPassword(email, "wrong-password"); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 401 {
+			return fmt.Errorf("failed login %d returned %d: %v", i+1, tc.response.StatusCode, tc.responseBody)
+		}
+	}
+	return nil
+}
+
+// iFailToLoginTimesInParallelAs sends wrong-password logins concurrently
+func (tc *testContext) iFailToLoginTimesInParallelAs(times int, email string) error {
+	body, err := json.Marshal(map[string]string{"email": email, "password": "wrong-password"})
+	if err != nil {
+		return err
+	}
+	return tc.postInParallel(times, "/auth/login", body)
+}
+
+func (tc *testContext) parallelLoginsShouldHaveReturned(expected, status int) error {
+	count := 0
+	for _, got := range tc.parallelStatuses {
+		if got == status {
+			count++
+		}
+	}
+	if count != expected {
+		return fmt.Errorf("expected %d parallel logins to return %d, got statuses %v", expected, status, tc.parallelStatuses)
+	}
+	return nil
+}
+
+func (tc *testContext) parallelRequestsShouldHaveFailedWith(expected int, code string) error {
+	count := 0
+	for _, got := range tc.parallelErrors {
+		if got == code {
+			count++
+		}
+	}
+	if count != expected {
+		return fmt.Errorf("expected %d parallel requests to fail with %q, got errors %v", expected, code, tc.parallelErrors)
+	}
+	return nil
+}
+
+// postInParallel sends the same POST request concurrently and keeps the
+// status codes and "error" fields of the responses; tc.response is left
+// untouched because the requests share it
+func (tc *testContext) postInParallel(times int, path string, body []byte) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses []int
+		codes    []string
+		errs     []error
+	)
+
+	for i := 0; i < times; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Post(tc.server.URL+path, "application/json", bytes.NewReader(body))
+			var result struct {
+				Error string `json:"error"`
+			}
+			if err == nil {
+				err = json.NewDecoder(resp.Body).Decode(&result)
+				resp.Body.Close()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			statuses = append(statuses, resp.StatusCode)
+			codes = append(codes, result.Error)
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d requests failed, first: %w", len(errs), times, errs[0])
+	}
+	tc.parallelStatuses = statuses
+	tc.parallelErrors = codes
+	return nil
+}
+
+func (tc *testContext) iRegisterAccounts(count int) error {
+	for i := 0; i < count; i++ {
+		email := fmt.Sprintf("bulk%d@example.com", i+1)
+		if err := tc.iRegisterWithEmailAndPassword(email, "correct-horse-battery"); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 201 {
+			return fmt.Errorf("registration %d returned %d: %v", i+1, tc.response.StatusCode, tc.responseBody)
+		}
+	}
+	return nil
+}
+
+func (tc *testContext) myRequestsComeFrom(ip string) error {
+	tc.clientIP = ip
+	return nil
+}
+
+func (tc *testContext) theResponseShouldAskMeToRetryWithinSeconds(max int) error {
+	value := tc.response.Header.Get("Retry-After")
+	if value == "" {
+		return fmt.Errorf("response has no Retry-After header")
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Retry-After is not a number of seconds: %q", value)
+	}
+	if seconds < 1 || seconds > max {
+		return fmt.Errorf("expected Retry-After between 1 and %d seconds, got %d", max, seconds)
+	}
+	return nil
+}
+
+// registerRateLimitSteps registers the rate limiting step definitions
+func registerRateLimitSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I fail to login (\d+) times? as "([^"]*)"$`, tc.iFailToLoginTimesAs)
+	ctx.Step(`^I fail to login (\d+) times in parallel as "([^"]*)"$`, tc.iFailToLoginTimesInParallelAs)
+	ctx.Step(`^(\d+) of the parallel logins should have returned (\d+)$`, tc.parallelLoginsShouldHaveReturned)
+	ctx.Step(`^(\d+) of the parallel requests should have failed with "([^"]*)"$`, tc.parallelRequestsShouldHaveFailedWith)
+	ctx.Step(`^I register (\d+) accounts$`, tc.iRegisterAccounts)
+	ctx.Step(`^my requests come from "([^"]*)"$`, tc.myRequestsComeFrom)
+	ctx.Step(`^the response should ask me to retry within (\d+) seconds$`, tc.theResponseShouldAskMeToRetryWithinSeconds)
+}
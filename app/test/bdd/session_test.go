@@ -0,0 +1,158 @@
+package bdd
+
+import (
+	"fmt"
+
+	"github.com/cucumber/godog"
+)
+
+// Session step definitions
+
+func (tc *testContext) iAmUsingTheUserAgent(userAgent string) error {
+	tc.userAgent = userAgent
+	return nil
+}
+
+func (tc *testContext) iListMySessions() error {
+	return tc.makeGetRequest("/api/v1/me/sessions", tc.authToken)
+}
+
+func (tc *testContext) iSignOutMyOtherSession() error {
+	if err := tc.iListMySessions(); err != nil {
+		return err
+	}
+	sessions, err := tc.listedSessions()
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if current, _ := session["current"].(bool); !current {
+			id, _ := session["id"].(string)
+			return tc.iSignOutTheSessionWithID(id)
+		}
+	}
+	return fmt.Errorf("no other session is listed")
+}
+
+func (tc *testContext) iRememberMyCurrentSession() error {
+	if err := tc.iListMySessions(); err != nil {
+		return err
+	}
+	session, err := tc.currentSession()
+	if err != nil {
+		return err
+	}
+	tc.sessionID, _ = session["id"].(string)
+	return nil
+}
+
+func (tc *testContext) iSignOutTheRememberedSession() error {
+	return tc.iSignOutTheSessionWithID(tc.sessionID)
+}
+
+func (tc *testContext) iSignOutTheSessionWithID(id string) error {
+	return tc.makeRequest("DELETE", "/api/v1/me/sessions/"+id, nil, tc.authToken)
+}
+
+// iSwitchBackToMyPreviousLogin makes the earlier login's tokens current again
+func (tc *testContext) iSwitchBackToMyPreviousLogin() error {
+	tc.authToken, tc.previousAuthToken = tc.previousAuthToken, tc.authToken
+	tc.refreshToken, tc.previousRefreshToken = tc.previousRefreshToken, tc.refreshToken
+	return nil
+}
+
+func (tc *testContext) theResponseShouldListSessions(count int) error {
+	sessions, err := tc.listedSessions()
+	if err != nil {
+		return err
+	}
+	if len(sessions) != count {
+		return fmt.Errorf("expected %d sessions, got %d", count, len(sessions))
+	}
+	return nil
+}
+
+func (tc *testContext) theCurrentSessionShouldBeListedFirst() error {
+	sessions, err := tc.listedSessions()
+	if err != nil {
+		return err
+	}
+	if len(sessions) == 0 {
+		return fmt.Errorf("no sessions are listed")
+	}
+	if current, _ := sessions[0]["current"].(bool); !current {
+		return fmt.Errorf("expected the first session to be the current one: %v", sessions)
+	}
+	return nil
+}
+
+func (tc *testContext) theCurrentSessionShouldBeLabelled(label string) error {
+	session, err := tc.currentSession()
+	if err != nil {
+		return err
+	}
+	if got, _ := session["device_label"].(string); got != label {
+		return fmt.Errorf("expected device label %q, got %q", label, got)
+	}
+	return nil
+}
+
+func (tc *testContext) theCurrentSessionShouldBeTheRememberedOne() error {
+	session, err := tc.currentSession()
+	if err != nil {
+		return err
+	}
+	if id, _ := session["id"].(string); id != tc.sessionID {
+		return fmt.Errorf("expected current session %s, got %s", tc.sessionID, id)
+	}
+	return nil
+}
+
+// currentSession returns the listed session marked as current
+func (tc *testContext) currentSession() (map[string]interface{}, error) {
+	sessions, err := tc.listedSessions()
+	if err != nil {
+		return nil, err
+	}
+	var found map[string]interface{}
+	for _, session := range sessions {
+		if current, _ := session["current"].(bool); current {
+			if found != nil {
+				return nil, fmt.Errorf("more than one session is marked as current")
+			}
+			found = session
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no session is marked as current: %v", sessions)
+	}
+	return found, nil
+}
+
+func (tc *testContext) listedSessions() ([]map[string]interface{}, error) {
+	items, ok := tc.responseBody["sessions"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response does not list sessions: %v", tc.responseBody)
+	}
+	sessions := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		session, _ := item.(map[string]interface{})
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// registerSessionSteps registers the session management step definitions
+func registerSessionSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I am using the user agent "([^"]*)"$`, tc.iAmUsingTheUserAgent)
+	ctx.Step(`^I list my sessions$`, tc.iListMySessions)
+	ctx.Step(`^I sign out my other session$`, tc.iSignOutMyOtherSession)
+	ctx.Step(`^I remember my current session$`, tc.iRememberMyCurrentSession)
+	ctx.Step(`^I sign out the remembered session$`, tc.iSignOutTheRememberedSession)
+	ctx.Step(`^I sign out the session with id "([^"]*)"$`, tc.iSignOutTheSessionWithID)
+	ctx.Step(`^I switch back to my previous login$`, tc.iSwitchBackToMyPreviousLogin)
+	ctx.Step(`^the response should list (\d+) sessions?$`, tc.theResponseShouldListSessions)
+	ctx.Step(`^the current session should be listed first$`, tc.theCurrentSessionShouldBeListedFirst)
+	ctx.Step(`^the current session should be labelled "([^"]*)"$`, tc.theCurrentSessionShouldBeLabelled)
+	ctx.Step(`^the current session should be the remembered one$`, tc.theCurrentSessionShouldBeTheRememberedOne)
+}
@@ -0,0 +1,153 @@
+package bdd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+// Tag step definitions
+
+func (tc *testContext) iListMyTags() error {
+	return tc.makeGetRequest("/api/v1/tags", tc.authToken)
+}
+
+func (tc *testContext) iCreateATagWith(body *godog.DocString) error {
+	if err := tc.makeRequest("POST", "/api/v1/tags", []byte(body.Content), tc.authToken); err != nil {
+		return err
+	}
+	if id, ok := tc.responseBody["id"].(string); ok {
+		tc.lastTagID = id
+	}
+	return nil
+}
+
+func (tc *testContext) iGetTheTag(name string) error {
+	id, err := tc.tagIDByName(name)
+	if err != nil {
+		return err
+	}
+	return tc.makeGetRequest("/api/v1/tags/"+id, tc.authToken)
+}
+
+func (tc *testContext) iGetTheLastCreatedTag() error {
+	return tc.makeGetRequest("/api/v1/tags/"+tc.lastTagID, tc.authToken)
+}
+
+func (tc *testContext) iUpdateTheTagWith(name string, body *godog.DocString) error {
+	id, err := tc.tagIDByName(name)
+	if err != nil {
+		return err
+	}
+	return tc.makeRequest("PUT", "/api/v1/tags/"+id, []byte(body.Content), tc.authToken)
+}
+
+func (tc *testContext) iMergeTheTagInto(source, target string) error {
+	sourceID, err := tc.tagIDByName(source)
+	if err != nil {
+		return err
+	}
+	targetID, err := tc.tagIDByName(target)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf(`{"into": %q}`, targetID)
+	return tc.makeRequest("POST", "/api/v1/tags/"+sourceID+"/merge", []byte(body), tc.authToken)
+}
+
+func (tc *testContext) iDeleteTheTag(name string) error {
+	id, err := tc.tagIDByName(name)
+	if err != nil {
+		return err
+	}
+	return tc.makeRequest("DELETE", "/api/v1/tags/"+id, nil, tc.authToken)
+}
+
+// tagIDByName looks up one of the user's tags through the API, leaving the
+// previous response in place for the step that needs the ID
+func (tc *testContext) tagIDByName(name string) (string, error) {
+	response, responseBody := tc.response, tc.responseBody
+	defer func() {
+		tc.response, tc.responseBody = response, responseBody
+	}()
+
+	if err := tc.iListMyTags(); err != nil {
+		return "", err
+	}
+	if tc.response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to list tags: %d %v", tc.response.StatusCode, tc.responseBody)
+	}
+	tags, err := tc.listedTags()
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag["name"] == name {
+			return fmt.Sprint(tag["id"]), nil
+		}
+	}
+	return "", fmt.Errorf("no tag named %q exists", name)
+}
+
+// listedTags returns the tags of a tag list response
+func (tc *testContext) listedTags() ([]map[string]interface{}, error) {
+	list, ok := tc.responseBody["tags"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response does not contain a tags list: %v", tc.responseBody)
+	}
+	tags := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		tag, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected tag: %v", item)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// theListedTagsShouldBe compares the listed tags as name:todo_count pairs
+func (tc *testContext) theListedTagsShouldBe(expected string) error {
+	tags, err := tc.listedTags()
+	if err != nil {
+		return err
+	}
+	values := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		values = append(values, fmt.Sprintf("%v:%v", tag["name"], tag["todo_count"]))
+	}
+	if got := strings.Join(values, ","); got != expected {
+		return fmt.Errorf("expected listed tags [%s], got [%s]", expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) theListedTagValuesShouldBe(field, expected string) error {
+	tags, err := tc.listedTags()
+	if err != nil {
+		return err
+	}
+	values := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		values = append(values, fmt.Sprint(tag[field]))
+	}
+	if got := strings.Join(values, ","); got != expected {
+		return fmt.Errorf("expected listed tag %s values [%s], got [%s]", field, expected, got)
+	}
+	return nil
+}
+
+// registerTagSteps registers the tag step definitions
+func registerTagSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I list my tags$`, tc.iListMyTags)
+	ctx.Step(`^I create a tag with:$`, tc.iCreateATagWith)
+	ctx.Step(`^I get the tag "([^"]*)"$`, tc.iGetTheTag)
+	ctx.Step(`^I get the last created tag$`, tc.iGetTheLastCreatedTag)
+	ctx.Step(`^I update the tag "([^"]*)" with:$`, tc.iUpdateTheTagWith)
+	ctx.Step(`^I merge the tag "([^"]*)" into "([^"]*)"$`, tc.iMergeTheTagInto)
+	ctx.Step(`^I delete the tag "([^"]*)"$`, tc.iDeleteTheTag)
+	ctx.Step(`^the listed tags should be "([^"]*)"$`, tc.theListedTagsShouldBe)
+	ctx.Step(`^the listed tag "([^"]*)" values should be "([^"]*)"$`, tc.theListedTagValuesShouldBe)
+}
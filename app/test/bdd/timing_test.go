@@ -0,0 +1,106 @@
+package bdd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cucumber/godog"
+)
+
+// Timing step definitions
+
+func (tc *testContext) iTimeFailedLoginsAs(times int, email string) error {
+	return tc.timeRequests(email, times, func(int) error {
+		if err := tc.iLoginWithEmailAndPassword(email, "wrong-password"); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 401 {
+			return fmt.Errorf("failed login returned %d: %v", tc.response.StatusCode, tc.responseBody)
+		}
+		return nil
+	})
+}
+
+func (tc *testContext) iTimeRegistrationsOfNewAccounts(times int) error {
+	return tc.timeRequests("new accounts", times, func(i int) error {
+		email := fmt.Sprintf("timed%d@example.com", i+1)
+		if err := tc.iRegisterWithEmailAndPassword(email, "correct-horse-battery"); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 201 {
+			return fmt.Errorf("registration returned %d: %v", tc.response.StatusCode, tc.responseBody)
+		}
+		return nil
+	})
+}
+
+func (tc *testContext) iTimeRegistrationsAs(times int, email string) error {
+	return tc.timeRequests(email, times, func(int) error {
+		if err := tc.iRegisterWithEmailAndPassword(email, "correct-horse-battery"); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 409 {
+			return fmt.Errorf("duplicate registration returned %d: %v", tc.response.StatusCode, tc.responseBody)
+		}
+		return nil
+	})
+}
+
+func (tc *testContext) theTimedRequestsShouldTakeAboutAsLongAs(label, reference string) error {
+	got, want := tc.averageTiming(label), tc.averageTiming(reference)
+	if got == 0 || want == 0 {
+		return fmt.Errorf("no timed requests recorded for %q or %q", label, reference)
+	}
+
+	// Hashing dominates both paths, so anything below half the reference
+	// means the hash was skipped
+	ratio := float64(got) / float64(want)
+	if ratio < 0.5 || ratio > 2 {
+		return fmt.Errorf("requests for %q took %s on average, %q took %s", label, got, reference, want)
+	}
+	return nil
+}
+
+// Helper methods
+
+// timeRequests runs request the given number of times and records how long
+// each took under label. A first, untimed request warms up the path so that
+// one-off costs do not skew the samples, and throttling counters are cleared
+// before every request so lockouts and rate limits do not cut them short.
+func (tc *testContext) timeRequests(label string, times int, request func(i int) error) error {
+	if tc.timings == nil {
+		tc.timings = make(map[string][]time.Duration)
+	}
+	for i := 0; i <= times; i++ {
+		tc.attempts.Clear()
+		start := time.Now()
+		if err := request(i); err != nil {
+			return err
+		}
+		if i > 0 {
+			tc.timings[label] = append(tc.timings[label], time.Since(start))
+		}
+	}
+	return nil
+}
+
+// averageTiming returns the mean duration recorded under label
+func (tc *testContext) averageTiming(label string) time.Duration {
+	durations := tc.timings[label]
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// registerTimingSteps registers the timing step definitions
+func registerTimingSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I time (\d+) failed logins? as "([^"]*)"$`, tc.iTimeFailedLoginsAs)
+	ctx.Step(`^I time (\d+) registrations? of new accounts$`, tc.iTimeRegistrationsOfNewAccounts)
+	ctx.Step(`^I time (\d+) registrations? as "([^"]*)"$`, tc.iTimeRegistrationsAs)
+	ctx.Step(`^the timed requests for "([^"]*)" should take about as long as for "([^"]*)"$`, tc.theTimedRequestsShouldTakeAboutAsLongAs)
+}
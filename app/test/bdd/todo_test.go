@@ -0,0 +1,437 @@
+package bdd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cucumber/godog"
+
+	"github.com/twaydev/golang-todolist/app/internal/domain/entity"
+)
+
+// Todo step definitions
+
+func (tc *testContext) iCreateATodoWithTitle(title string) error {
+	body := fmt.Sprintf(`{"title": %q}`, title)
+	return tc.iCreateATodoWith(&godog.DocString{Content: body})
+}
+
+func (tc *testContext) iCreateATodoWith(body *godog.DocString) error {
+	if err := tc.makeRequest("POST", "/api/v1/todos", []byte(body.Content), tc.authToken); err != nil {
+		return err
+	}
+	if id, ok := tc.responseBody["id"].(string); ok {
+		tc.lastTodoID = id
+	}
+	if code, ok := tc.responseBody["code"].(string); ok {
+		tc.lastTodoCode = code
+	}
+	return nil
+}
+
+// iCreateTodosInParallel sends the create requests concurrently and keeps the
+// codes of the created todos; tc.response is left untouched because the
+// requests share it
+func (tc *testContext) iCreateTodosInParallel(count int) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		codes []string
+		errs  []error
+	)
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			code, err := tc.createTodoForCode(fmt.Sprintf("Parallel %d", i))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			codes = append(codes, code)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d creates failed, first: %w", len(errs), count, errs[0])
+	}
+	tc.createdCodes = codes
+	return nil
+}
+
+// createTodoForCode creates a todo and returns its short code
+func (tc *testContext) createTodoForCode(title string) (string, error) {
+	body, err := json.Marshal(map[string]string{"title": title})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", tc.server.URL+"/api/v1/todos", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tc.authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var created map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("expected status 201, got %d: %v", resp.StatusCode, created)
+	}
+	return fmt.Sprint(created["code"]), nil
+}
+
+func (tc *testContext) aTodoExistsWithTitle(title string) error {
+	if err := tc.iCreateATodoWithTitle(title); err != nil {
+		return err
+	}
+	if tc.response.StatusCode != 201 {
+		return fmt.Errorf("failed to create todo: %d %v", tc.response.StatusCode, tc.responseBody)
+	}
+	// Reset response for next step
+	tc.response = nil
+	tc.responseBody = nil
+	return nil
+}
+
+func (tc *testContext) iListMyTodos() error {
+	return tc.makeGetRequest("/api/v1/todos", tc.authToken)
+}
+
+func (tc *testContext) iGetTheTodo() error {
+	return tc.makeGetRequest("/api/v1/todos/"+tc.lastTodoID, tc.authToken)
+}
+
+func (tc *testContext) iGetTheTodoByItsCode() error {
+	return tc.makeGetRequest("/api/v1/todos/"+tc.lastTodoCode, tc.authToken)
+}
+
+func (tc *testContext) iDeleteTheTodoByItsCode() error {
+	return tc.makeRequest("DELETE", "/api/v1/todos/"+tc.lastTodoCode, nil, tc.authToken)
+}
+
+func (tc *testContext) iGetTheTodoWithID(id string) error {
+	return tc.makeGetRequest("/api/v1/todos/"+id, tc.authToken)
+}
+
+func (tc *testContext) iUpdateTheTodoWith(body *godog.DocString) error {
+	return tc.makeRequest("PUT", "/api/v1/todos/"+tc.lastTodoID, []byte(body.Content), tc.authToken)
+}
+
+func (tc *testContext) iDeleteTheTodo() error {
+	return tc.makeRequest("DELETE", "/api/v1/todos/"+tc.lastTodoID, nil, tc.authToken)
+}
+
+func (tc *testContext) iReopenTheTodo() error {
+	return tc.makeRequest("POST", "/api/v1/todos/"+tc.lastTodoID+"/reopen", nil, tc.authToken)
+}
+
+func (tc *testContext) iGetTheTodoHistory() error {
+	return tc.makeGetRequest("/api/v1/todos/"+tc.lastTodoID+"/history", tc.authToken)
+}
+
+func (tc *testContext) theTodoHistoryShouldBe(expected string) error {
+	history, ok := tc.responseBody["history"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not contain a history list: %v", tc.responseBody)
+	}
+	statuses := make([]string, 0, len(history))
+	for _, entry := range history {
+		change, ok := entry.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected history entry: %v", entry)
+		}
+		statuses = append(statuses, fmt.Sprint(change["to_status"]))
+	}
+	if got := strings.Join(statuses, ","); got != expected {
+		return fmt.Errorf("expected history [%s], got [%s]", expected, got)
+	}
+	return nil
+}
+
+// theFollowingTodosExist creates a todo for each table row. Every column but
+// title is optional; tags are comma-separated and a status other than pending
+// is set with an update after the todo is created.
+func (tc *testContext) theFollowingTodosExist(table *godog.Table) error {
+	header := table.Rows[0].Cells
+	for _, row := range table.Rows[1:] {
+		fields := make(map[string]string, len(header))
+		for i, cell := range row.Cells {
+			if cell.Value != "" {
+				fields[header[i].Value] = cell.Value
+			}
+		}
+
+		todo := map[string]interface{}{"title": fields["title"]}
+		if priority, ok := fields["priority"]; ok {
+			todo["priority"] = priority
+		}
+		if dueDate, ok := fields["due_date"]; ok {
+			todo["due_date"] = dueDate
+		}
+		if tags, ok := fields["tags"]; ok {
+			todo["tags"] = strings.Split(tags, ",")
+		}
+		if description, ok := fields["description"]; ok {
+			todo["description"] = description
+		}
+
+		body, err := json.Marshal(todo)
+		if err != nil {
+			return err
+		}
+		if err := tc.makeRequest("POST", "/api/v1/todos", body, tc.authToken); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != http.StatusCreated {
+			return fmt.Errorf("failed to create todo %q: %d %v", fields["title"], tc.response.StatusCode, tc.responseBody)
+		}
+
+		id := fmt.Sprint(tc.responseBody["id"])
+		if tc.todoIDsByTitle == nil {
+			tc.todoIDsByTitle = make(map[string]string)
+		}
+		tc.todoIDsByTitle[fields["title"]] = id
+
+		if status, ok := fields["status"]; ok && status != string(entity.StatusPending) {
+			body := fmt.Sprintf(`{"status": %q}`, status)
+			if err := tc.makeRequest("PUT", "/api/v1/todos/"+id, []byte(body), tc.authToken); err != nil {
+				return err
+			}
+			if tc.response.StatusCode != http.StatusOK {
+				return fmt.Errorf("failed to set status of todo %q: %d %v", fields["title"], tc.response.StatusCode, tc.responseBody)
+			}
+		}
+	}
+
+	tc.response = nil
+	tc.responseBody = nil
+	return nil
+}
+
+func (tc *testContext) iUpdateTheTodoTitledWith(title string, body *godog.DocString) error {
+	id, ok := tc.todoIDsByTitle[title]
+	if !ok {
+		return fmt.Errorf("no todo titled %q was created", title)
+	}
+	return tc.makeRequest("PUT", "/api/v1/todos/"+id, []byte(body.Content), tc.authToken)
+}
+
+func (tc *testContext) iListMyTodosWithQuery(query string) error {
+	tc.lastListQuery = query
+	return tc.makeGetRequest("/api/v1/todos?"+query, tc.authToken)
+}
+
+func (tc *testContext) iSetTheListQueryTo(query string) error {
+	tc.lastListQuery = query
+	return nil
+}
+
+func (tc *testContext) iListTheNextPageOfMyTodos() error {
+	cursor, ok := tc.responseBody["next_cursor"].(string)
+	if !ok {
+		return fmt.Errorf("response has no next cursor: %v", tc.responseBody)
+	}
+	query := "cursor=" + url.QueryEscape(cursor)
+	if tc.lastListQuery != "" {
+		query = tc.lastListQuery + "&" + query
+	}
+	return tc.makeGetRequest("/api/v1/todos?"+query, tc.authToken)
+}
+
+func (tc *testContext) theListedTodosShouldBe(expected string) error {
+	return tc.theListedTodoValuesShouldBe("title", expected)
+}
+
+func (tc *testContext) theListedTodoValuesShouldBe(field, expected string) error {
+	todos, ok := tc.responseBody["todos"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not contain a todos list: %v", tc.responseBody)
+	}
+	values := make([]string, 0, len(todos))
+	for _, t := range todos {
+		todo, ok := t.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected todo: %v", t)
+		}
+		values = append(values, fmt.Sprint(todo[field]))
+	}
+	if got := strings.Join(values, ","); got != expected {
+		return fmt.Errorf("expected listed %s values [%s], got [%s]", field, expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) iSearchMyTodosFor(query string) error {
+	return tc.iSearchMyTodosWithQuery("q=" + url.QueryEscape(query))
+}
+
+func (tc *testContext) iSearchMyTodosWithQuery(query string) error {
+	return tc.makeGetRequest("/api/v1/todos/search?"+query, tc.authToken)
+}
+
+// searchResults returns the results of a todo search response
+func (tc *testContext) searchResults() ([]map[string]interface{}, error) {
+	list, ok := tc.responseBody["results"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response does not contain search results: %v", tc.responseBody)
+	}
+	results := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		result, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected search result: %v", item)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (tc *testContext) theSearchResultsShouldBe(expected string) error {
+	results, err := tc.searchResults()
+	if err != nil {
+		return err
+	}
+	titles := make([]string, 0, len(results))
+	for _, result := range results {
+		todo, _ := result["todo"].(map[string]interface{})
+		titles = append(titles, fmt.Sprint(todo["title"]))
+	}
+	if got := strings.Join(titles, ","); got != expected {
+		return fmt.Errorf("expected search results [%s], got [%s]", expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseShouldContainSearchResults(expected int) error {
+	results, err := tc.searchResults()
+	if err != nil {
+		return err
+	}
+	if len(results) != expected {
+		return fmt.Errorf("expected %d search results, got %d: %v", expected, len(results), results)
+	}
+	return nil
+}
+
+func (tc *testContext) theFirstSearchResultHighlightShouldBe(field, expected string) error {
+	results, err := tc.searchResults()
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("search returned no results")
+	}
+	highlights, _ := results[0]["highlights"].(map[string]interface{})
+	if got := fmt.Sprint(highlights[field]); got != expected {
+		return fmt.Errorf("expected %s highlight '%s', got '%s'", field, expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) iListMyTodosWithoutAuthentication() error {
+	return tc.makeGetRequest("/api/v1/todos", "")
+}
+
+func (tc *testContext) theResponseShouldContainTodos(expected int) error {
+	todos, ok := tc.responseBody["todos"].([]interface{})
+	if !ok {
+		return fmt.Errorf("response does not contain a todos list: %v", tc.responseBody)
+	}
+	if len(todos) != expected {
+		return fmt.Errorf("expected %d todos, got %d: %v", expected, len(todos), todos)
+	}
+	return nil
+}
+
+func (tc *testContext) theTodoCodeShouldBeNumberOfTheCurrentYear(seq int) error {
+	expected := entity.FormatTodoCode(time.Now().UTC().Year(), seq)
+	if got := fmt.Sprint(tc.responseBody["code"]); got != expected {
+		return fmt.Errorf("expected todo code '%s', got '%s'", expected, got)
+	}
+	return nil
+}
+
+func (tc *testContext) theTodoCodesShouldBeNumberedForTheCurrentYear(first, last int) error {
+	expected := make([]string, 0, last-first+1)
+	for seq := first; seq <= last; seq++ {
+		expected = append(expected, entity.FormatTodoCode(time.Now().UTC().Year(), seq))
+	}
+
+	codes := append([]string(nil), tc.createdCodes...)
+	sort.Strings(codes)
+	if got, want := strings.Join(codes, ","), strings.Join(expected, ","); got != want {
+		return fmt.Errorf("expected todo codes [%s], got [%s]", want, got)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseListShouldBe(field, expected string) error {
+	value, ok := tc.responseBody[field].([]interface{})
+	if !ok {
+		return fmt.Errorf("field '%s' is not a list in response: %v", field, tc.responseBody)
+	}
+	items := make([]string, 0, len(value))
+	for _, item := range value {
+		items = append(items, fmt.Sprint(item))
+	}
+	if got := strings.Join(items, ","); got != expected {
+		return fmt.Errorf("expected '%s' to be [%s], got [%s]", field, expected, got)
+	}
+	return nil
+}
+
+// registerTodoSteps registers the todo step definitions
+func registerTodoSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^I create a todo with title "([^"]*)"$`, tc.iCreateATodoWithTitle)
+	ctx.Step(`^I create a todo with:$`, tc.iCreateATodoWith)
+	ctx.Step(`^a todo exists with title "([^"]*)"$`, tc.aTodoExistsWithTitle)
+	ctx.Step(`^I list my todos$`, tc.iListMyTodos)
+	ctx.Step(`^the following todos exist:$`, tc.theFollowingTodosExist)
+	ctx.Step(`^I list my todos with query "([^"]*)"$`, tc.iListMyTodosWithQuery)
+	ctx.Step(`^I set the list query to "([^"]*)"$`, tc.iSetTheListQueryTo)
+	ctx.Step(`^I list the next page of my todos$`, tc.iListTheNextPageOfMyTodos)
+	ctx.Step(`^the listed todos should be "([^"]*)"$`, tc.theListedTodosShouldBe)
+	ctx.Step(`^the listed todo "([^"]*)" values should be "([^"]*)"$`, tc.theListedTodoValuesShouldBe)
+	ctx.Step(`^I update the todo "([^"]*)" with:$`, tc.iUpdateTheTodoTitledWith)
+	ctx.Step(`^I search my todos for "([^"]*)"$`, tc.iSearchMyTodosFor)
+	ctx.Step(`^I search my todos with query "([^"]*)"$`, tc.iSearchMyTodosWithQuery)
+	ctx.Step(`^the search results should be "([^"]*)"$`, tc.theSearchResultsShouldBe)
+	ctx.Step(`^the response should contain (\d+) search results?$`, tc.theResponseShouldContainSearchResults)
+	ctx.Step(`^the first search result "([^"]*)" highlight should be "([^"]*)"$`, tc.theFirstSearchResultHighlightShouldBe)
+	ctx.Step(`^I list my todos without authentication$`, tc.iListMyTodosWithoutAuthentication)
+	ctx.Step(`^I get the todo$`, tc.iGetTheTodo)
+	ctx.Step(`^I get the todo with id "([^"]*)"$`, tc.iGetTheTodoWithID)
+	ctx.Step(`^I get the todo by its code$`, tc.iGetTheTodoByItsCode)
+	ctx.Step(`^I delete the todo by its code$`, tc.iDeleteTheTodoByItsCode)
+	ctx.Step(`^I create (\d+) todos in parallel$`, tc.iCreateTodosInParallel)
+	ctx.Step(`^the todo code should be number (\d+) of the current year$`, tc.theTodoCodeShouldBeNumberOfTheCurrentYear)
+	ctx.Step(`^the todo codes should be numbered (\d+) to (\d+) for the current year$`, tc.theTodoCodesShouldBeNumberedForTheCurrentYear)
+	ctx.Step(`^I update the todo with:$`, tc.iUpdateTheTodoWith)
+	ctx.Step(`^I delete the todo$`, tc.iDeleteTheTodo)
+	ctx.Step(`^I reopen the todo$`, tc.iReopenTheTodo)
+	ctx.Step(`^I get the todo history$`, tc.iGetTheTodoHistory)
+	ctx.Step(`^the todo history should be "([^"]*)"$`, tc.theTodoHistoryShouldBe)
+	ctx.Step(`^the response should contain (\d+) todos?$`, tc.theResponseShouldContainTodos)
+	ctx.Step(`^the response "([^"]*)" list should be "([^"]*)"$`, tc.theResponseListShouldBe)
+}
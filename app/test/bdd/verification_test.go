@@ -0,0 +1,165 @@
+package bdd
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+var (
+	emailTokenRegex  = regexp.MustCompile(`token=([A-Za-z0-9_%-]+)`)
+	emailLinkRegex   = regexp.MustCompile(regexp.QuoteMeta(appBaseURL) + `(/\S+)`)
+	formActionRegex  = regexp.MustCompile(`<form method="post" action="([^"]*)">`)
+	hiddenInputRegex = regexp.MustCompile(`<input type="hidden" name="([^"]*)" value="([^"]*)">`)
+)
+
+// Email verification step definitions
+
+func (tc *testContext) iVerifyMyEmailUsingTheLinkSentTo(email string) error {
+	token, err := tc.tokenFromLastEmailTo(email)
+	if err != nil {
+		return err
+	}
+	return tc.iVerifyMyEmailWithToken(token)
+}
+
+func (tc *testContext) iVerifyMyEmailWithToken(token string) error {
+	return tc.makeGetRequest("/auth/verify-email?token="+url.QueryEscape(token), "")
+}
+
+func (tc *testContext) iConfirmMyEmailChangeUsingTheLinkSentTo(email string) error {
+	if err := tc.openLinkSentTo(email); err != nil {
+		return err
+	}
+	return tc.submitTheForm(nil)
+}
+
+func (tc *testContext) iRememberTheLinkSentTo(email string) error {
+	token, err := tc.tokenFromLastEmailTo(email)
+	if err != nil {
+		return err
+	}
+	tc.rememberedEmailToken = token
+	return nil
+}
+
+func (tc *testContext) iUseTheRememberedLink() error {
+	return tc.iVerifyMyEmailWithToken(tc.rememberedEmailToken)
+}
+
+func (tc *testContext) iRequestANewVerificationEmailFor(email string) error {
+	body := map[string]string{
+		"email": email,
+	}
+	return tc.makePostRequest("/auth/verify-email/resend", body)
+}
+
+func (tc *testContext) iRequestNewVerificationEmailsFor(times int, email string) error {
+	for i := 0; i < times; i++ {
+		if err := tc.iRequestANewVerificationEmailFor(email); err != nil {
+			return err
+		}
+		if tc.response.StatusCode != 202 {
+			return fmt.Errorf("resend request %d returned %d: %v", i+1, tc.response.StatusCode, tc.responseBody)
+		}
+	}
+	return nil
+}
+
+func (tc *testContext) emailsShouldHaveBeenSentTo(expected int, email string) error {
+	if got := tc.mailer.countMessagesTo(email); got != expected {
+		return fmt.Errorf("expected %d emails to %s, got %d", expected, email, got)
+	}
+	return nil
+}
+
+func (tc *testContext) theLastEmailToShouldMention(email, text string) error {
+	msg, ok := tc.mailer.lastMessageTo(email)
+	if !ok {
+		return fmt.Errorf("no email was sent to %s", email)
+	}
+	if !strings.Contains(msg.Body, text) {
+		return fmt.Errorf("expected the last email to %s to mention %q, got %q", email, text, msg.Body)
+	}
+	return nil
+}
+
+func (tc *testContext) theResponseFieldShouldBeBool(field, expected string) error {
+	value, ok := tc.responseBody[field].(bool)
+	if !ok {
+		return fmt.Errorf("field '%s' is not a boolean in response: %v", field, tc.responseBody)
+	}
+	if fmt.Sprint(value) != expected {
+		return fmt.Errorf("expected '%s' to be %s, got %v", field, expected, value)
+	}
+	return nil
+}
+
+// Helper methods
+
+// openLinkSentTo opens the link in the last email sent to the address
+func (tc *testContext) openLinkSentTo(email string) error {
+	msg, ok := tc.mailer.lastMessageTo(email)
+	if !ok {
+		return fmt.Errorf("no email was sent to %s", email)
+	}
+	match := emailLinkRegex.FindStringSubmatch(msg.Body)
+	if match == nil {
+		return fmt.Errorf("no link found in email to %s: %q", email, msg.Body)
+	}
+	return tc.makeGetRequest(match[1], "")
+}
+
+// submitTheForm posts the form on the last page opened, with its hidden
+// fields and the given ones
+func (tc *testContext) submitTheForm(fields url.Values) error {
+	if tc.response == nil || tc.response.StatusCode != 200 {
+		return fmt.Errorf("no page to submit a form on: %v", tc.responseBody)
+	}
+	action := formActionRegex.FindStringSubmatch(tc.responseText)
+	if action == nil {
+		return fmt.Errorf("no form found on the page: %q", tc.responseText)
+	}
+
+	values := url.Values{}
+	for _, input := range hiddenInputRegex.FindAllStringSubmatch(tc.responseText, -1) {
+		values.Set(html.UnescapeString(input[1]), html.UnescapeString(input[2]))
+	}
+	for name, value := range fields {
+		values[name] = value
+	}
+	return tc.makeFormPost(html.UnescapeString(action[1]), values)
+}
+
+// tokenFromLastEmailTo extracts the token from the last link emailed to the address
+func (tc *testContext) tokenFromLastEmailTo(email string) (string, error) {
+	msg, ok := tc.mailer.lastMessageTo(email)
+	if !ok {
+		return "", fmt.Errorf("no email was sent to %s", email)
+	}
+	match := emailTokenRegex.FindStringSubmatch(msg.Body)
+	if match == nil {
+		return "", fmt.Errorf("no token link found in email to %s: %q", email, msg.Body)
+	}
+	return url.QueryUnescape(match[1])
+}
+
+// registerEmailVerificationSteps registers the email verification step definitions
+func registerEmailVerificationSteps(ctx *godog.ScenarioContext, tc *testContext) {
+	ctx.Step(`^an unverified user exists with email "([^"]*)" and password "([^"]*)"$`, tc.anUnverifiedUserExistsWithEmailAndPassword)
+	ctx.Step(`^I verify my email using the link sent to "([^"]*)"$`, tc.iVerifyMyEmailUsingTheLinkSentTo)
+	ctx.Step(`^I verify my email with token "([^"]*)"$`, tc.iVerifyMyEmailWithToken)
+	ctx.Step(`^I confirm my email change using the link sent to "([^"]*)"$`, tc.iConfirmMyEmailChangeUsingTheLinkSentTo)
+	ctx.Step(`^I open the link sent to "([^"]*)"$`, tc.openLinkSentTo)
+	ctx.Step(`^I remember the link sent to "([^"]*)"$`, tc.iRememberTheLinkSentTo)
+	ctx.Step(`^I verify my email using the remembered link$`, tc.iUseTheRememberedLink)
+	ctx.Step(`^I request a new verification email for "([^"]*)"$`, tc.iRequestANewVerificationEmailFor)
+	ctx.Step(`^I request (\d+) new verification emails for "([^"]*)"$`, tc.iRequestNewVerificationEmailsFor)
+	ctx.Step(`^(\d+) emails? should have been sent to "([^"]*)"$`, tc.emailsShouldHaveBeenSentTo)
+	ctx.Step(`^the last email to "([^"]*)" should mention "([^"]*)"$`, tc.theLastEmailToShouldMention)
+	ctx.Step(`^the response "([^"]*)" should be (true|false)$`, tc.theResponseFieldShouldBeBool)
+}